@@ -24,8 +24,26 @@ func NewLogAdapter(logger *logging.Logger) badger.Logger {
 	}
 }
 
+// NewSampledLogAdapter returns a badger.Logger backed by an oasis-node logger that collapses
+// identical info and debug messages emitted within the given sampling window.
+//
+// The first occurrence of a message is always logged. Any repetitions within the window are
+// suppressed and their number is reported (as "suppressed") with the next occurrence of the
+// message after the window has elapsed. Errors and warnings are never sampled. In case the
+// window is zero, no sampling is performed.
+func NewSampledLogAdapter(logger *logging.Logger, window time.Duration) badger.Logger {
+	l := &badgerLogger{
+		logger: logger,
+	}
+	if window > 0 {
+		l.sampler = newLogSampler(window)
+	}
+	return l
+}
+
 type badgerLogger struct {
-	logger *logging.Logger
+	logger  *logging.Logger
+	sampler *logSampler
 }
 
 func (l *badgerLogger) Errorf(format string, a ...interface{}) {
@@ -37,11 +55,100 @@ func (l *badgerLogger) Warningf(format string, a ...interface{}) {
 }
 
 func (l *badgerLogger) Infof(format string, a ...interface{}) {
-	l.logger.Info(strings.TrimSpace(fmt.Sprintf(format, a...)))
+	l.sampled(l.logger.Info, logging.LevelInfo, format, a...)
 }
 
 func (l *badgerLogger) Debugf(format string, a ...interface{}) {
-	l.logger.Debug(strings.TrimSpace(fmt.Sprintf(format, a...)))
+	l.sampled(l.logger.Debug, logging.LevelDebug, format, a...)
+}
+
+func (l *badgerLogger) sampled(
+	logFn func(string, ...interface{}),
+	level logging.Level,
+	format string,
+	a ...interface{},
+) {
+	msg := strings.TrimSpace(fmt.Sprintf(format, a...))
+	if l.sampler == nil {
+		logFn(msg)
+		return
+	}
+
+	emit, suppressed := l.sampler.sample(level, msg)
+	switch {
+	case !emit:
+	case suppressed > 0:
+		logFn(msg, "suppressed", suppressed)
+	default:
+		logFn(msg)
+	}
+}
+
+// maxSampledMessages is the number of distinct messages tracked by a log sampler after which
+// entries with an elapsed window are discarded.
+const maxSampledMessages = 1024
+
+type logSamplerKey struct {
+	level logging.Level
+	msg   string
+}
+
+type logSamplerEntry struct {
+	windowStart time.Time
+	suppressed  uint64
+}
+
+// logSampler collapses identical log messages within a sampling window.
+type logSampler struct {
+	sync.Mutex
+
+	window  time.Duration
+	entries map[logSamplerKey]*logSamplerEntry
+
+	now func() time.Time
+}
+
+// sample records an occurrence of the given message and returns whether it should be emitted
+// along with the number of occurrences suppressed since the message was last emitted.
+func (s *logSampler) sample(level logging.Level, msg string) (bool, uint64) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := s.now()
+	key := logSamplerKey{level, msg}
+	if entry, ok := s.entries[key]; ok {
+		if now.Sub(entry.windowStart) < s.window {
+			entry.suppressed++
+			return false, 0
+		}
+
+		suppressed := entry.suppressed
+		entry.windowStart = now
+		entry.suppressed = 0
+		return true, suppressed
+	}
+
+	if len(s.entries) >= maxSampledMessages {
+		// Discard entries with an elapsed window. Any suppressed occurrences of these are not
+		// reported as the message may never repeat.
+		for k, entry := range s.entries {
+			if now.Sub(entry.windowStart) >= s.window {
+				delete(s.entries, k)
+			}
+		}
+	}
+	if len(s.entries) < maxSampledMessages {
+		s.entries[key] = &logSamplerEntry{windowStart: now}
+	}
+	return true, 0
+}
+
+func newLogSampler(window time.Duration) *logSampler {
+	return &logSampler{
+		window:  window,
+		entries: make(map[logSamplerKey]*logSamplerEntry),
+		now:     time.Now,
+	}
 }
 
 // GCWorker is a BadgerDB value log GC worker.
@@ -0,0 +1,63 @@
+package badger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+func TestLogSampler(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1600000000, 0)
+	s := newLogSampler(10 * time.Second)
+	s.now = func() time.Time { return now }
+
+	emit, suppressed := s.sample(logging.LevelInfo, "msg")
+	require.True(emit, "first occurrence should be emitted")
+	require.EqualValues(0, suppressed)
+
+	for i := 0; i < 5; i++ {
+		now = now.Add(time.Second)
+		emit, _ = s.sample(logging.LevelInfo, "msg")
+		require.False(emit, "repetition within the window should be suppressed")
+	}
+
+	// Distinct messages and levels are sampled independently.
+	emit, _ = s.sample(logging.LevelInfo, "other msg")
+	require.True(emit, "distinct message should be emitted")
+	emit, _ = s.sample(logging.LevelDebug, "msg")
+	require.True(emit, "same message at a different level should be emitted")
+
+	now = now.Add(5 * time.Second)
+	emit, suppressed = s.sample(logging.LevelInfo, "msg")
+	require.True(emit, "occurrence after the window should be emitted")
+	require.EqualValues(5, suppressed, "suppressed count should be reported")
+
+	now = now.Add(time.Second)
+	emit, _ = s.sample(logging.LevelInfo, "msg")
+	require.False(emit, "window should restart after emitting")
+}
+
+func TestLogSamplerBounded(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1600000000, 0)
+	s := newLogSampler(time.Second)
+	s.now = func() time.Time { return now }
+
+	for i := 0; i < maxSampledMessages+10; i++ {
+		emit, _ := s.sample(logging.LevelInfo, string(rune(i)))
+		require.True(emit, "distinct messages should be emitted")
+	}
+	require.Len(s.entries, maxSampledMessages, "number of tracked messages should be bounded")
+
+	// Once the window has elapsed, stale entries are discarded.
+	now = now.Add(time.Second)
+	emit, _ := s.sample(logging.LevelInfo, "new msg")
+	require.True(emit)
+	require.Len(s.entries, 1, "stale entries should be discarded")
+}
@@ -13,6 +13,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -24,7 +25,7 @@ import (
 var (
 	backend = logBackend{
 		baseLogger:   log.NewNopLogger(),
-		defaultLevel: LevelError,
+		defaultLevel: uint32(LevelError),
 	}
 
 	_ pflag.Value = (*Level)(nil)
@@ -86,18 +87,16 @@ const (
 	LevelError
 )
 
-func (l Level) toOption() level.Option {
-	switch l {
-	case LevelDebug:
-		return level.AllowDebug()
-	case LevelInfo:
-		return level.AllowInfo()
-	case LevelWarn:
-		return level.AllowWarn()
-	case LevelError:
-		return level.AllowError()
+func (l Level) allows(v level.Value) bool {
+	switch v.String() {
+	case level.DebugValue().String():
+		return l <= LevelDebug
+	case level.InfoValue().String():
+		return l <= LevelInfo
+	case level.WarnValue().String():
+		return l <= LevelWarn
 	default:
-		panic("logging: unsupported log level")
+		return true
 	}
 }
 
@@ -142,14 +141,22 @@ func (l *Level) Type() string {
 
 // Logger is a logger instance.
 type Logger struct {
-	logger log.Logger
-	level  Level
-	module string
+	logger     log.Logger
+	level      Level
+	useDefault bool
+	module     string
+}
+
+func (l *Logger) getLevel() Level {
+	if l.useDefault {
+		return GetLevel()
+	}
+	return l.level
 }
 
 // Debug logs the message and key value pairs at the Debug log level.
 func (l *Logger) Debug(msg string, keyvals ...interface{}) {
-	if l.level > LevelDebug {
+	if l.getLevel() > LevelDebug {
 		return
 	}
 	keyvals = append([]interface{}{"msg", msg}, keyvals...)
@@ -158,7 +165,7 @@ func (l *Logger) Debug(msg string, keyvals ...interface{}) {
 
 // Info logs the message and key value pairs at the Info log level.
 func (l *Logger) Info(msg string, keyvals ...interface{}) {
-	if l.level > LevelInfo {
+	if l.getLevel() > LevelInfo {
 		return
 	}
 	keyvals = append([]interface{}{"msg", msg}, keyvals...)
@@ -167,7 +174,7 @@ func (l *Logger) Info(msg string, keyvals ...interface{}) {
 
 // Warn logs the message and key value pairs at the Warn log level.
 func (l *Logger) Warn(msg string, keyvals ...interface{}) {
-	if l.level > LevelWarn {
+	if l.getLevel() > LevelWarn {
 		return
 	}
 	keyvals = append([]interface{}{"msg", msg}, keyvals...)
@@ -176,7 +183,7 @@ func (l *Logger) Warn(msg string, keyvals ...interface{}) {
 
 // Error logs the message and key value pairs at the Error log level.
 func (l *Logger) Error(msg string, keyvals ...interface{}) {
-	if l.level > LevelError {
+	if l.getLevel() > LevelError {
 		return
 	}
 	keyvals = append([]interface{}{"msg", msg}, keyvals...)
@@ -187,14 +194,22 @@ func (l *Logger) Error(msg string, keyvals ...interface{}) {
 // added via log.WithPrefix.
 func (l *Logger) With(keyvals ...interface{}) *Logger {
 	return &Logger{
-		logger: log.With(l.logger, keyvals...),
-		level:  l.level,
+		logger:     log.With(l.logger, keyvals...),
+		level:      l.level,
+		useDefault: l.useDefault,
 	}
 }
 
 // GetLevel returns the current global log level.
 func GetLevel() Level {
-	return backend.defaultLevel
+	return Level(atomic.LoadUint32(&backend.defaultLevel))
+}
+
+// SetLevel changes the global log level at runtime.
+//
+// Loggers with a module-specific log level are not affected.
+func SetLevel(lvl Level) {
+	atomic.StoreUint32(&backend.defaultLevel, uint32(lvl))
 }
 
 // GetLogger creates a new logger instance with the specified module.
@@ -241,12 +256,12 @@ func Initialize(w io.Writer, format Format, defaultLvl Level, moduleLvls map[str
 		}
 	}
 
-	logger = level.NewFilter(logger, defaultLvl.toOption())
+	logger = &levelFilter{next: logger}
 	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
 
 	backend.baseLogger = logger
 	backend.moduleLevels = moduleLvls
-	backend.defaultLevel = defaultLvl
+	SetLevel(defaultLvl)
 	backend.initialized = true
 
 	// Swap all the early loggers to the initialized backend.
@@ -273,6 +288,25 @@ func Initialize(w io.Writer, format Format, defaultLvl Level, moduleLvls map[str
 	return nil
 }
 
+// levelFilter is a log filter that only passes records allowed by the current
+// global log level.
+type levelFilter struct {
+	next log.Logger
+}
+
+func (f *levelFilter) Log(keyvals ...interface{}) error {
+	lvl := GetLevel()
+	for i := 1; i < len(keyvals); i += 2 {
+		if v, ok := keyvals[i].(level.Value); ok {
+			if !lvl.allows(v) {
+				return nil
+			}
+			break
+		}
+	}
+	return f.next.Log(keyvals...)
+}
+
 type earlyLogger struct {
 	swapLogger *log.SwapLogger
 	logger     *Logger
@@ -283,7 +317,7 @@ type logBackend struct {
 
 	baseLogger   log.Logger
 	earlyLoggers []*earlyLogger
-	defaultLevel Level
+	defaultLevel uint32
 	moduleLevels map[string]Level
 
 	initialized bool
@@ -299,15 +333,14 @@ func (b *logBackend) setupLogLevelLocked(l *Logger) {
 	}
 	sort.Sort(sort.Reverse(sort.StringSlice(modulePrefixes)))
 
-	lvl := b.defaultLevel
+	l.useDefault = true
 	for _, k := range modulePrefixes {
 		if strings.HasPrefix(l.module, k) {
-			lvl = b.moduleLevels[k]
+			l.level = b.moduleLevels[k]
+			l.useDefault = false
 			break
 		}
 	}
-
-	l.level = lvl
 }
 
 func (b *logBackend) getLogger(module string, extraUnwind int) *Logger {
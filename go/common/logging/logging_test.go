@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLevel(t *testing.T) {
+	require := require.New(t)
+
+	var buf bytes.Buffer
+	err := Initialize(&buf, FmtLogfmt, LevelInfo, map[string]Level{
+		"test/module": LevelWarn,
+	})
+	require.NoError(err, "Initialize")
+	require.Equal(LevelInfo, GetLevel(), "GetLevel should return the initial level")
+
+	logger := GetLogger("test/default")
+	moduleLogger := GetLogger("test/module")
+
+	logger.Debug("debug before")
+	require.NotContains(buf.String(), "debug before", "debug messages should be filtered")
+
+	// Raise verbosity.
+	defaultLevel := GetLevel()
+	SetLevel(LevelDebug)
+	require.Equal(LevelDebug, GetLevel(), "GetLevel should return the updated level")
+
+	logger.Debug("debug during")
+	require.Contains(buf.String(), "debug during", "debug messages should pass after SetLevel")
+
+	// Loggers with a module-specific level are not affected.
+	moduleLogger.Info("module info during")
+	require.NotContains(buf.String(), "module info during", "module-specific level should be kept")
+
+	// Restore the default.
+	SetLevel(defaultLevel)
+	require.Equal(LevelInfo, GetLevel(), "GetLevel should return the restored level")
+
+	logger.Debug("debug after")
+	require.NotContains(buf.String(), "debug after", "debug messages should be filtered after restoring")
+	logger.Info("info after")
+	require.Contains(buf.String(), "info after", "info messages should pass after restoring")
+}
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
@@ -16,6 +18,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/service"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
@@ -52,6 +55,22 @@ var (
 
 	// ErrDuplicateTx is the error returned when the transaction already exists in the mempool.
 	ErrDuplicateTx = errors.New(moduleName, 5, "consensus: duplicate transaction")
+
+	// ErrBusy is the error returned when the consensus backend is unable to
+	// serve the request due to too many concurrent requests.
+	ErrBusy = errors.New(moduleName, 6, "consensus: too many concurrent requests")
+
+	// ErrNotStarted is the error returned when the consensus backend failed to start within
+	// the configured startup wait deadline.
+	ErrNotStarted = errors.New(moduleName, 7, "consensus: node failed to start in time")
+
+	// ErrEvidenceTooOld is the error returned when the submitted evidence is older than the
+	// maximum evidence age allowed by the consensus parameters.
+	ErrEvidenceTooOld = errors.New(moduleName, 8, "consensus: evidence too old")
+
+	// ErrWrongChain is the error returned when a transaction is submitted for a chain other than
+	// the one the consensus backend is part of.
+	ErrWrongChain = errors.New(moduleName, 9, "consensus: wrong chain")
 )
 
 // FeatureMask is the consensus backend feature bitmask.
@@ -94,12 +113,40 @@ type ClientBackend interface {
 	// in a block. Use SubmitTxNoWait if you only need to broadcast the transaction.
 	SubmitTx(ctx context.Context, tx *transaction.SignedTransaction) error
 
+	// SubmitTxForChain is the same as SubmitTx, but first verifies that the consensus backend is
+	// part of the chain with the given chain ID. In case it is not, ErrWrongChain is returned and
+	// the transaction is not broadcast.
+	SubmitTxForChain(ctx context.Context, tx *transaction.SignedTransaction, chainID string) error
+
+	// CheckTx validates a signed consensus transaction in the same way as SubmitTx does before
+	// accepting it into the mempool, but does not broadcast it. The same errors as for SubmitTx are
+	// returned in case the transaction is invalid.
+	//
+	// NOTE: The transaction is checked against the latest committed state so any transactions
+	// that are still pending in the mempool (e.g., from the same signer) are not taken into account.
+	CheckTx(ctx context.Context, tx *transaction.SignedTransaction) error
+
 	// StateToGenesis returns the genesis state at the specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error)
 
+	// ValidatorSetToGenesis returns the scheduler genesis state together with the consensus
+	// parameters at the specified block height.
+	//
+	// As opposed to StateToGenesis this does not export the state of any other backend so it
+	// is much cheaper in case only the validator set is needed (e.g., to seed a test network).
+	ValidatorSetToGenesis(ctx context.Context, height int64) (*ValidatorSetGenesis, error)
+
 	// EstimateGas calculates the amount of gas required to execute the given transaction.
 	EstimateGas(ctx context.Context, req *EstimateGasRequest) (transaction.Gas, error)
 
+	// GetSignerNonceAtHeight returns the nonce of the given signer as recorded in the consensus
+	// state at the given height, ignoring the height specified in the request.
+	//
+	// Unlike GetSignerNonce, the height is never clamped to the latest height. In case the
+	// state at the given height is not available (e.g., because it has been pruned), an error
+	// is returned.
+	GetSignerNonceAtHeight(ctx context.Context, req *GetSignerNonceRequest, height int64) (uint64, error)
+
 	// WaitEpoch waits for consensus to reach an epoch.
 	//
 	// Note that an epoch is considered reached even if any epoch greater than
@@ -107,27 +154,107 @@ type ClientBackend interface {
 	// in the future).
 	WaitEpoch(ctx context.Context, epoch epochtime.EpochTime) error
 
+	// WaitEpochBlock waits for consensus to reach an epoch and returns the height of the block
+	// at which the given epoch became active.
+	//
+	// As with WaitEpoch, an epoch is considered reached even if any later epoch is reached.
+	WaitEpochBlock(ctx context.Context, epoch epochtime.EpochTime) (int64, error)
+
 	// GetEpoch returns the current epoch.
 	GetEpoch(ctx context.Context, height int64) (epochtime.EpochTime, error)
 
+	// GetEpochProgress returns the progress of the epoch at the specified block height, including
+	// an estimate of when the next epoch transition will happen.
+	GetEpochProgress(ctx context.Context, height int64) (*epochtime.EpochProgress, error)
+
 	// GetBlock returns a consensus block at a specific height.
 	GetBlock(ctx context.Context, height int64) (*Block, error)
 
+	// GetBlockSignatures returns the validator signatures of the commit for the consensus block
+	// at a specific height.
+	//
+	// The commit for a given height is included in the following block so signatures for the
+	// latest height may still change until the next block is committed.
+	GetBlockSignatures(ctx context.Context, height int64) ([]CommitSig, error)
+
 	// GetTransactions returns a list of all transactions contained within a
 	// consensus block at a specific height.
 	//
 	// NOTE: Any of these transactions could be invalid.
 	GetTransactions(ctx context.Context, height int64) ([][]byte, error)
 
+	// GetTransactionsWithHashes returns a list of all transactions contained within a consensus
+	// block at a specific height, together with their hashes.
+	//
+	// The hashes are computed in the same way as when transactions are submitted via SubmitTx.
+	//
+	// NOTE: Any of these transactions could be invalid.
+	GetTransactionsWithHashes(ctx context.Context, height int64) ([]TxWithHash, error)
+
 	// GetTransactionsWithResults returns a list of transactions and their
 	// execution results, contained within a consensus block at a specific
 	// height.
 	GetTransactionsWithResults(ctx context.Context, height int64) (*TransactionsWithResults, error)
 
+	// GetTransactionsWithResultsFiltered is like GetTransactionsWithResults, but allows the
+	// roothash events to be limited to a single runtime.
+	//
+	// Only roothash events for the requested runtime are decoded and returned. Staking and
+	// registry events are not affected by the filter. An empty filter returns all events.
+	GetTransactionsWithResultsFiltered(
+		ctx context.Context,
+		req *GetTransactionsWithResultsRequest,
+	) (*TransactionsWithResults, error)
+
+	// GetEventsAtHeight returns all staking, registry and roothash events emitted in the
+	// consensus block at a specific height, including events emitted outside of transactions.
+	//
+	// Only block results are fetched, the block itself is not. As a consequence the TxHash
+	// field of the returned events is always set to the empty hash.
+	GetEventsAtHeight(ctx context.Context, height int64) ([]*results.Event, error)
+
+	// GetDecodedBlockResults returns the decoded results of processing the consensus block at a
+	// specific height, including the execution result and gas usage of each transaction and all
+	// events emitted outside of transactions.
+	//
+	// Only block results are fetched, the block itself is not. As a consequence the TxHash
+	// field of the returned events is always set to the empty hash.
+	GetDecodedBlockResults(ctx context.Context, height int64) (*DecodedBlockResults, error)
+
+	// GetBlockWithResults returns the consensus block at a specific height together with the
+	// decoded results of processing it.
+	//
+	// The block and its results are guaranteed to be for the same height. As the transactions
+	// are available, the TxHash field of the returned transaction events is set.
+	GetBlockWithResults(ctx context.Context, height int64) (*BlockWithResults, error)
+
+	// StreamTransactionsWithResults returns a channel that produces the transactions and their
+	// execution results for each height in the inclusive range [startHeight, endHeight], in
+	// order.
+	//
+	// In case the transactions at any height cannot be retrieved (e.g., because the height has
+	// been pruned), the last item produced has Err set and the channel is closed.
+	StreamTransactionsWithResults(ctx context.Context, startHeight, endHeight int64) (<-chan *HeightTransactions, error)
+
 	// GetUnconfirmedTransactions returns a list of transactions currently in the local node's
 	// mempool. These have not yet been included in a block.
 	GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error)
 
+	// GetUnconfirmedTransactionsWithMeta returns a list of transactions currently in the local
+	// node's mempool together with their metadata.
+	GetUnconfirmedTransactionsWithMeta(ctx context.Context) ([]*MempoolTx, error)
+
+	// WatchUnconfirmedTransactions returns a channel that produces transactions as they are
+	// accepted into the local node's mempool. Transactions which are re-checked after a block
+	// is committed are not produced again.
+	WatchUnconfirmedTransactions(ctx context.Context) (<-chan [][]byte, pubsub.ClosableSubscription, error)
+
+	// GetBlockIntervalStats returns statistics about the intervals between the last window
+	// consecutive blocks.
+	//
+	// The window may be capped by the backend to bound the cost of the query.
+	GetBlockIntervalStats(ctx context.Context, window int) (*BlockIntervalStats, error)
+
 	// WatchBlocks returns a channel that produces a stream of consensus
 	// blocks as they are being finalized.
 	WatchBlocks(ctx context.Context) (<-chan *Block, pubsub.ClosableSubscription, error)
@@ -156,6 +283,17 @@ type Block struct {
 	Meta cbor.RawMessage `json:"meta"`
 }
 
+// PeerInfo is liveness information about a consensus peer.
+type PeerInfo struct {
+	// Address is the peer's address in the form ID@host:port.
+	Address string `json:"address"`
+	// LastReceived is the time when any data was last received from the peer.
+	LastReceived time.Time `json:"last_received"`
+	// Stale is true if no data has been received from the peer within the configured
+	// stale peer threshold.
+	Stale bool `json:"stale"`
+}
+
 // Status is the current status overview.
 type Status struct { // nolint: maligned
 	// ConsensusVersion is the version of the consensus protocol that the node is using.
@@ -167,6 +305,14 @@ type Status struct { // nolint: maligned
 
 	// NodePeers is a list of node's peers.
 	NodePeers []string `json:"node_peers"`
+	// NodePeersInfo contains liveness information for each of the node's peers.
+	NodePeersInfo []*PeerInfo `json:"node_peers_info"`
+
+	// P2PListenAddress is the address the node's P2P transport listens on.
+	P2PListenAddress string `json:"p2p_listen_address,omitempty"`
+	// P2PExternalAddress is the address the node advertises to its peers. In case it is empty,
+	// the node advertises the listen address.
+	P2PExternalAddress string `json:"p2p_external_address,omitempty"`
 
 	// LatestHeight is the height of the latest block.
 	LatestHeight int64 `json:"latest_height"`
@@ -179,7 +325,10 @@ type Status struct { // nolint: maligned
 
 	// GenesisHeight is the height of the genesis block.
 	GenesisHeight int64 `json:"genesis_height"`
-	// GenesisHash is the hash of the genesis block.
+	// GenesisHash is the hash of the genesis document.
+	//
+	// It is available even before the first block is committed and after the genesis block has
+	// been pruned.
 	GenesisHash []byte `json:"genesis_hash"`
 
 	// LastRetainedHeight is the height of the oldest retained block.
@@ -187,8 +336,23 @@ type Status struct { // nolint: maligned
 	// LastRetainedHash is the hash of the oldest retained block.
 	LastRetainedHash []byte `json:"last_retained_hash"`
 
+	// PruneStrategy is the configured consensus state pruning strategy.
+	PruneStrategy string `json:"prune_strategy,omitempty"`
+	// PruneNumKept is the number of retained versions when pruning is enabled.
+	PruneNumKept uint64 `json:"prune_num_kept,omitempty"`
+
 	// IsValidator returns whether the current node is part of the validator set.
 	IsValidator bool `json:"is_validator"`
+
+	// CheckTxDisabled is true iff the node is configured to skip CheckTx on incoming
+	// transactions. Such a node will accept invalid transactions into its mempool and
+	// should never be used in production.
+	CheckTxDisabled bool `json:"check_tx_disabled,omitempty"`
+
+	// ValidatorCheckFailed is true iff the node is configured to require being a validator and was
+	// not part of the validator set during the last check (performed on each epoch transition,
+	// starting after the first full epoch following the initial sync).
+	ValidatorCheckFailed bool `json:"validator_check_failed,omitempty"`
 }
 
 // Backend is an interface that a consensus backend must provide.
@@ -266,6 +430,57 @@ type GetSignerNonceRequest struct {
 	Height         int64           `json:"height"`
 }
 
+// GetTransactionsWithResultsRequest is a GetTransactionsWithResultsFiltered request.
+type GetTransactionsWithResultsRequest struct {
+	// Height is the consensus block height.
+	Height int64 `json:"height"`
+	// RuntimeID is an optional filter which limits roothash events to the given runtime.
+	RuntimeID *common.Namespace `json:"runtime_id,omitempty"`
+}
+
+// SubmitTxForChainRequest is a SubmitTxForChain request.
+type SubmitTxForChainRequest struct {
+	// Tx is the signed transaction to submit.
+	Tx *transaction.SignedTransaction `json:"tx"`
+	// ChainID is the expected chain ID.
+	ChainID string `json:"chain_id"`
+}
+
+// ValidatorSetGenesis is a genesis document fragment containing the validator set related state.
+//
+// The fields can be spliced into the corresponding fields of a genesis document.
+type ValidatorSetGenesis struct {
+	// Height is the block height at which the state was exported.
+	Height int64 `json:"height"`
+	// Scheduler is the scheduler genesis state.
+	Scheduler scheduler.Genesis `json:"scheduler"`
+	// Consensus is the consensus genesis state.
+	Consensus consensusGenesis.Genesis `json:"consensus"`
+}
+
+// CommitSig is a validator signature in a consensus block commit.
+type CommitSig struct {
+	// PublicKey is the consensus public key of the validator.
+	PublicKey signature.PublicKey `json:"public_key"`
+	// Absent is true when no vote from the validator was included in the commit.
+	Absent bool `json:"absent,omitempty"`
+	// Nil is true when the validator voted for nil instead of the committed block.
+	Nil bool `json:"nil,omitempty"`
+}
+
+// Signed returns true iff the validator signed the committed block.
+func (cs *CommitSig) Signed() bool {
+	return !cs.Absent && !cs.Nil
+}
+
+// TxWithHash is a raw transaction together with its hash.
+type TxWithHash struct {
+	// Tx is the raw transaction.
+	Tx []byte `json:"tx"`
+	// Hash is the hash of the raw transaction.
+	Hash hash.Hash `json:"hash"`
+}
+
 // TransactionsWithResults is GetTransactionsWithResults response.
 //
 // Results[i] are the results of executing Transactions[i].
@@ -273,3 +488,80 @@ type TransactionsWithResults struct {
 	Transactions [][]byte          `json:"transactions"`
 	Results      []*results.Result `json:"results"`
 }
+
+// DecodedTxResult is the decoded result of executing a transaction in a block.
+type DecodedTxResult struct {
+	// Result is the transaction execution result.
+	Result *results.Result `json:"result"`
+	// GasWanted is the amount of gas wanted by the transaction.
+	GasWanted transaction.Gas `json:"gas_wanted"`
+	// GasUsed is the amount of gas used by the transaction.
+	GasUsed transaction.Gas `json:"gas_used"`
+}
+
+// DecodedBlockResults is GetDecodedBlockResults response.
+//
+// TxResults[i] are the results of executing the i-th transaction in the block.
+type DecodedBlockResults struct {
+	// Height is the height of the block.
+	Height int64 `json:"height"`
+	// BeginBlockEvents are the events emitted while beginning the block.
+	BeginBlockEvents []*results.Event `json:"begin_block_events,omitempty"`
+	// TxResults are the decoded transaction results.
+	TxResults []*DecodedTxResult `json:"tx_results,omitempty"`
+	// EndBlockEvents are the events emitted while ending the block.
+	EndBlockEvents []*results.Event `json:"end_block_events,omitempty"`
+}
+
+// BlockWithResults is GetBlockWithResults response.
+type BlockWithResults struct {
+	// Block is the consensus block.
+	Block *Block `json:"block"`
+	// Results are the decoded results of processing the block.
+	Results *DecodedBlockResults `json:"results"`
+}
+
+// StreamTransactionsWithResultsRequest is a StreamTransactionsWithResults request.
+type StreamTransactionsWithResultsRequest struct {
+	StartHeight int64 `json:"start_height"`
+	EndHeight   int64 `json:"end_height"`
+}
+
+// HeightTransactions are the transactions and their execution results at a given height.
+type HeightTransactions struct {
+	TransactionsWithResults
+
+	// Height is the height of the block containing the transactions.
+	Height int64 `json:"height"`
+
+	// Err is the error that terminated the stream. In case it is set, all other fields are
+	// undefined.
+	Err error `json:"-"`
+}
+
+// BlockIntervalStats are the statistics about intervals between consecutive blocks.
+type BlockIntervalStats struct {
+	// FromHeight is the height of the first block in the window.
+	FromHeight int64 `json:"from_height"`
+	// ToHeight is the height of the last block in the window.
+	ToHeight int64 `json:"to_height"`
+
+	// Min is the minimum interval between two consecutive blocks.
+	Min time.Duration `json:"min"`
+	// Max is the maximum interval between two consecutive blocks.
+	Max time.Duration `json:"max"`
+	// Mean is the mean interval between two consecutive blocks.
+	Mean time.Duration `json:"mean"`
+}
+
+// MempoolTx is a transaction in the local node's mempool together with its metadata.
+type MempoolTx struct {
+	// Hash is the hash of the raw transaction.
+	Hash hash.Hash `json:"hash"`
+	// Size is the size of the raw transaction in bytes.
+	Size uint64 `json:"size"`
+	// TimeInMempool is the time since the transaction was first accepted into the mempool.
+	TimeInMempool time.Duration `json:"time_in_mempool"`
+	// GasWanted is the amount of gas wanted by the transaction as reported by CheckTx.
+	GasWanted transaction.Gas `json:"gas_wanted"`
+}
@@ -2,12 +2,14 @@ package api
 
 import (
 	"context"
+	"io"
 
 	"google.golang.org/grpc"
 
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
@@ -21,24 +23,55 @@ var (
 
 	// methodSubmitTx is the SubmitTx method.
 	methodSubmitTx = serviceName.NewMethod("SubmitTx", transaction.SignedTransaction{})
+	// methodSubmitTxForChain is the SubmitTxForChain method.
+	methodSubmitTxForChain = serviceName.NewMethod("SubmitTxForChain", &SubmitTxForChainRequest{})
+	// methodCheckTx is the CheckTx method.
+	methodCheckTx = serviceName.NewMethod("CheckTx", transaction.SignedTransaction{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
+	// methodValidatorSetToGenesis is the ValidatorSetToGenesis method.
+	methodValidatorSetToGenesis = serviceName.NewMethod("ValidatorSetToGenesis", int64(0))
 	// methodEstimateGas is the EstimateGas method.
 	methodEstimateGas = serviceName.NewMethod("EstimateGas", &EstimateGasRequest{})
 	// methodGetSignerNonce is a GetSignerNonce method.
 	methodGetSignerNonce = serviceName.NewMethod("GetSignerNonce", &GetSignerNonceRequest{})
+	// methodGetSignerNonceAtHeight is the GetSignerNonceAtHeight method.
+	methodGetSignerNonceAtHeight = serviceName.NewMethod("GetSignerNonceAtHeight", &GetSignerNonceRequest{})
 	// methodGetEpoch is the GetEpoch method.
 	methodGetEpoch = serviceName.NewMethod("GetEpoch", int64(0))
+	// methodGetEpochProgress is the GetEpochProgress method.
+	methodGetEpochProgress = serviceName.NewMethod("GetEpochProgress", int64(0))
 	// methodWaitEpoch is the WaitEpoch method.
 	methodWaitEpoch = serviceName.NewMethod("WaitEpoch", epochtime.EpochTime(0))
+	// methodWaitEpochBlock is the WaitEpochBlock method.
+	methodWaitEpochBlock = serviceName.NewMethod("WaitEpochBlock", epochtime.EpochTime(0))
 	// methodGetBlock is the GetBlock method.
 	methodGetBlock = serviceName.NewMethod("GetBlock", int64(0))
 	// methodGetTransactions is the GetTransactions method.
 	methodGetTransactions = serviceName.NewMethod("GetTransactions", int64(0))
+	// methodGetTransactionsWithHashes is the GetTransactionsWithHashes method.
+	methodGetTransactionsWithHashes = serviceName.NewMethod("GetTransactionsWithHashes", int64(0))
 	// methodGetTransactionsWithResults is the GetTransactionsWithResults method.
 	methodGetTransactionsWithResults = serviceName.NewMethod("GetTransactionsWithResults", int64(0))
+	// methodGetTransactionsWithResultsFiltered is the GetTransactionsWithResultsFiltered method.
+	methodGetTransactionsWithResultsFiltered = serviceName.NewMethod(
+		"GetTransactionsWithResultsFiltered",
+		&GetTransactionsWithResultsRequest{},
+	)
+	// methodGetBlockSignatures is the GetBlockSignatures method.
+	methodGetBlockSignatures = serviceName.NewMethod("GetBlockSignatures", int64(0))
+	// methodGetEventsAtHeight is the GetEventsAtHeight method.
+	methodGetEventsAtHeight = serviceName.NewMethod("GetEventsAtHeight", int64(0))
+	// methodGetDecodedBlockResults is the GetDecodedBlockResults method.
+	methodGetDecodedBlockResults = serviceName.NewMethod("GetDecodedBlockResults", int64(0))
+	// methodGetBlockWithResults is the GetBlockWithResults method.
+	methodGetBlockWithResults = serviceName.NewMethod("GetBlockWithResults", int64(0))
 	// methodGetUnconfirmedTransactions is the GetUnconfirmedTransactions method.
 	methodGetUnconfirmedTransactions = serviceName.NewMethod("GetUnconfirmedTransactions", nil)
+	// methodGetUnconfirmedTransactionsWithMeta is the GetUnconfirmedTransactionsWithMeta method.
+	methodGetUnconfirmedTransactionsWithMeta = serviceName.NewMethod("GetUnconfirmedTransactionsWithMeta", nil)
+	// methodGetBlockIntervalStats is the GetBlockIntervalStats method.
+	methodGetBlockIntervalStats = serviceName.NewMethod("GetBlockIntervalStats", int(0))
 	// methodGetGenesisDocument is the GetGenesisDocument method.
 	methodGetGenesisDocument = serviceName.NewMethod("GetGenesisDocument", nil)
 	// methodGetStatus is the GetStatus method.
@@ -46,6 +79,13 @@ var (
 
 	// methodWatchBlocks is the WatchBlocks method.
 	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", nil)
+	// methodWatchUnconfirmedTransactions is the WatchUnconfirmedTransactions method.
+	methodWatchUnconfirmedTransactions = serviceName.NewMethod("WatchUnconfirmedTransactions", nil)
+	// methodStreamTransactionsWithResults is the StreamTransactionsWithResults method.
+	methodStreamTransactionsWithResults = serviceName.NewMethod(
+		"StreamTransactionsWithResults",
+		StreamTransactionsWithResultsRequest{},
+	)
 
 	// methodGetLightBlock is the GetLightBlock method.
 	methodGetLightBlock = lightServiceName.NewMethod("GetLightBlock", int64(0))
@@ -71,10 +111,22 @@ var (
 				MethodName: methodSubmitTx.ShortName(),
 				Handler:    handlerSubmitTx,
 			},
+			{
+				MethodName: methodSubmitTxForChain.ShortName(),
+				Handler:    handlerSubmitTxForChain,
+			},
 			{
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
 			},
+			{
+				MethodName: methodValidatorSetToGenesis.ShortName(),
+				Handler:    handlerValidatorSetToGenesis,
+			},
+			{
+				MethodName: methodCheckTx.ShortName(),
+				Handler:    handlerCheckTx,
+			},
 			{
 				MethodName: methodEstimateGas.ShortName(),
 				Handler:    handlerEstimateGas,
@@ -83,14 +135,26 @@ var (
 				MethodName: methodGetSignerNonce.ShortName(),
 				Handler:    handlerGetSignerNonce,
 			},
+			{
+				MethodName: methodGetSignerNonceAtHeight.ShortName(),
+				Handler:    handlerGetSignerNonceAtHeight,
+			},
 			{
 				MethodName: methodGetEpoch.ShortName(),
 				Handler:    handlerGetEpoch,
 			},
+			{
+				MethodName: methodGetEpochProgress.ShortName(),
+				Handler:    handlerGetEpochProgress,
+			},
 			{
 				MethodName: methodWaitEpoch.ShortName(),
 				Handler:    handlerWaitEpoch,
 			},
+			{
+				MethodName: methodWaitEpochBlock.ShortName(),
+				Handler:    handlerWaitEpochBlock,
+			},
 			{
 				MethodName: methodGetBlock.ShortName(),
 				Handler:    handlerGetBlock,
@@ -99,14 +163,46 @@ var (
 				MethodName: methodGetTransactions.ShortName(),
 				Handler:    handlerGetTransactions,
 			},
+			{
+				MethodName: methodGetTransactionsWithHashes.ShortName(),
+				Handler:    handlerGetTransactionsWithHashes,
+			},
 			{
 				MethodName: methodGetTransactionsWithResults.ShortName(),
 				Handler:    handlerGetTransactionsWithResults,
 			},
+			{
+				MethodName: methodGetTransactionsWithResultsFiltered.ShortName(),
+				Handler:    handlerGetTransactionsWithResultsFiltered,
+			},
+			{
+				MethodName: methodGetBlockSignatures.ShortName(),
+				Handler:    handlerGetBlockSignatures,
+			},
+			{
+				MethodName: methodGetEventsAtHeight.ShortName(),
+				Handler:    handlerGetEventsAtHeight,
+			},
+			{
+				MethodName: methodGetDecodedBlockResults.ShortName(),
+				Handler:    handlerGetDecodedBlockResults,
+			},
+			{
+				MethodName: methodGetBlockWithResults.ShortName(),
+				Handler:    handlerGetBlockWithResults,
+			},
 			{
 				MethodName: methodGetUnconfirmedTransactions.ShortName(),
 				Handler:    handlerGetUnconfirmedTransactions,
 			},
+			{
+				MethodName: methodGetUnconfirmedTransactionsWithMeta.ShortName(),
+				Handler:    handlerGetUnconfirmedTransactionsWithMeta,
+			},
+			{
+				MethodName: methodGetBlockIntervalStats.ShortName(),
+				Handler:    handlerGetBlockIntervalStats,
+			},
 			{
 				MethodName: methodGetGenesisDocument.ShortName(),
 				Handler:    handlerGetGenesisDocument,
@@ -122,6 +218,16 @@ var (
 				Handler:       handlerWatchBlocks,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodStreamTransactionsWithResults.ShortName(),
+				Handler:       handlerStreamTransactionsWithResults,
+				ServerStreams: true,
+			},
+			{
+				StreamName:    methodWatchUnconfirmedTransactions.ShortName(),
+				Handler:       handlerWatchUnconfirmedTransactions,
+				ServerStreams: true,
+			},
 		},
 	}
 
@@ -185,6 +291,76 @@ func handlerSubmitTx( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerSubmitTxForChain( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(SubmitTxForChainRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(ClientBackend).SubmitTxForChain(ctx, rq.Tx, rq.ChainID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSubmitTxForChain.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(*SubmitTxForChainRequest)
+		return nil, srv.(ClientBackend).SubmitTxForChain(ctx, r.Tx, r.ChainID)
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
+func handlerValidatorSetToGenesis( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).ValidatorSetToGenesis(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodValidatorSetToGenesis.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).ValidatorSetToGenesis(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerCheckTx( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(transaction.SignedTransaction)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(ClientBackend).CheckTx(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCheckTx.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(ClientBackend).CheckTx(ctx, req.(*transaction.SignedTransaction))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 func handlerStateToGenesis( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -254,6 +430,30 @@ func handlerGetSignerNonce( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerGetSignerNonceAtHeight( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(GetSignerNonceRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetSignerNonceAtHeight(ctx, rq, rq.Height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetSignerNonceAtHeight.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(*GetSignerNonceRequest)
+		return srv.(ClientBackend).GetSignerNonceAtHeight(ctx, r, r.Height)
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 func handlerGetEpoch( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -277,6 +477,29 @@ func handlerGetEpoch( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetEpochProgress( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetEpochProgress(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEpochProgress.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetEpochProgress(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
 func handlerWaitEpoch( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -300,6 +523,29 @@ func handlerWaitEpoch( // nolint: golint
 	return interceptor(ctx, epoch, info, handler)
 }
 
+func handlerWaitEpochBlock( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var epoch epochtime.EpochTime
+	if err := dec(&epoch); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).WaitEpochBlock(ctx, epoch)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodWaitEpochBlock.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).WaitEpochBlock(ctx, req.(epochtime.EpochTime))
+	}
+	return interceptor(ctx, epoch, info, handler)
+}
+
 func handlerGetBlock( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -346,6 +592,29 @@ func handlerGetTransactions( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetTransactionsWithHashes( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetTransactionsWithHashes(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTransactionsWithHashes.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetTransactionsWithHashes(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
 func handlerGetTransactionsWithResults( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -369,6 +638,121 @@ func handlerGetTransactionsWithResults( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetTransactionsWithResultsFiltered( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(GetTransactionsWithResultsRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetTransactionsWithResultsFiltered(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTransactionsWithResultsFiltered.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetTransactionsWithResultsFiltered(ctx, req.(*GetTransactionsWithResultsRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
+func handlerGetBlockSignatures( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetBlockSignatures(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetBlockSignatures.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetBlockSignatures(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerGetEventsAtHeight( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetEventsAtHeight(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEventsAtHeight.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetEventsAtHeight(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerGetDecodedBlockResults( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetDecodedBlockResults(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetDecodedBlockResults.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetDecodedBlockResults(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerGetBlockWithResults( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetBlockWithResults(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetBlockWithResults.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetBlockWithResults(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
 func handlerGetUnconfirmedTransactions( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -388,6 +772,48 @@ func handlerGetUnconfirmedTransactions( // nolint: golint
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerGetUnconfirmedTransactionsWithMeta( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(ClientBackend).GetUnconfirmedTransactionsWithMeta(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetUnconfirmedTransactionsWithMeta.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetUnconfirmedTransactionsWithMeta(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerGetBlockIntervalStats( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var window int
+	if err := dec(&window); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetBlockIntervalStats(ctx, window)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetBlockIntervalStats.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetBlockIntervalStats(ctx, req.(int))
+	}
+	return interceptor(ctx, window, info, handler)
+}
+
 func handlerGetGenesisDocument( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -454,6 +880,65 @@ func handlerWatchBlocks(srv interface{}, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerStreamTransactionsWithResults(srv interface{}, stream grpc.ServerStream) error {
+	var req StreamTransactionsWithResultsRequest
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, err := srv.(ClientBackend).StreamTransactionsWithResults(ctx, req.StartHeight, req.EndHeight)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case txs, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if txs.Err != nil {
+				return txs.Err
+			}
+
+			if err := stream.SendMsg(txs); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func handlerWatchUnconfirmedTransactions(srv interface{}, stream grpc.ServerStream) error {
+	if err := stream.RecvMsg(nil); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(ClientBackend).WatchUnconfirmedTransactions(ctx)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case txs, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(txs); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 func handlerGetLightBlock( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -704,6 +1189,25 @@ func (c *consensusClient) SubmitTx(ctx context.Context, tx *transaction.SignedTr
 	return c.conn.Invoke(ctx, methodSubmitTx.FullName(), tx, nil)
 }
 
+func (c *consensusClient) SubmitTxForChain(ctx context.Context, tx *transaction.SignedTransaction, chainID string) error {
+	return c.conn.Invoke(ctx, methodSubmitTxForChain.FullName(), &SubmitTxForChainRequest{
+		Tx:      tx,
+		ChainID: chainID,
+	}, nil)
+}
+
+func (c *consensusClient) ValidatorSetToGenesis(ctx context.Context, height int64) (*ValidatorSetGenesis, error) {
+	var rsp ValidatorSetGenesis
+	if err := c.conn.Invoke(ctx, methodValidatorSetToGenesis.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *consensusClient) CheckTx(ctx context.Context, tx *transaction.SignedTransaction) error {
+	return c.conn.Invoke(ctx, methodCheckTx.FullName(), tx, nil)
+}
+
 func (c *consensusClient) StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error) {
 	var rsp genesis.Document
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.FullName(), height, &rsp); err != nil {
@@ -728,10 +1232,29 @@ func (c *consensusClient) GetSignerNonce(ctx context.Context, req *GetSignerNonc
 	return nonce, nil
 }
 
+func (c *consensusClient) GetSignerNonceAtHeight(ctx context.Context, req *GetSignerNonceRequest, height int64) (uint64, error) {
+	rq := *req
+	rq.Height = height
+
+	var nonce uint64
+	if err := c.conn.Invoke(ctx, methodGetSignerNonceAtHeight.FullName(), &rq, &nonce); err != nil {
+		return nonce, err
+	}
+	return nonce, nil
+}
+
 func (c *consensusClient) WaitEpoch(ctx context.Context, epoch epochtime.EpochTime) error {
 	return c.conn.Invoke(ctx, methodWaitEpoch.FullName(), epoch, nil)
 }
 
+func (c *consensusClient) WaitEpochBlock(ctx context.Context, epoch epochtime.EpochTime) (int64, error) {
+	var height int64
+	if err := c.conn.Invoke(ctx, methodWaitEpochBlock.FullName(), epoch, &height); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
 func (c *consensusClient) GetEpoch(ctx context.Context, height int64) (epochtime.EpochTime, error) {
 	var epoch epochtime.EpochTime
 	if err := c.conn.Invoke(ctx, methodGetEpoch.FullName(), height, &epoch); err != nil {
@@ -740,6 +1263,14 @@ func (c *consensusClient) GetEpoch(ctx context.Context, height int64) (epochtime
 	return epoch, nil
 }
 
+func (c *consensusClient) GetEpochProgress(ctx context.Context, height int64) (*epochtime.EpochProgress, error) {
+	var rsp epochtime.EpochProgress
+	if err := c.conn.Invoke(ctx, methodGetEpochProgress.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) GetBlock(ctx context.Context, height int64) (*Block, error) {
 	var rsp Block
 	if err := c.conn.Invoke(ctx, methodGetBlock.FullName(), height, &rsp); err != nil {
@@ -756,6 +1287,14 @@ func (c *consensusClient) GetTransactions(ctx context.Context, height int64) ([]
 	return rsp, nil
 }
 
+func (c *consensusClient) GetTransactionsWithHashes(ctx context.Context, height int64) ([]TxWithHash, error) {
+	var rsp []TxWithHash
+	if err := c.conn.Invoke(ctx, methodGetTransactionsWithHashes.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *consensusClient) GetTransactionsWithResults(ctx context.Context, height int64) (*TransactionsWithResults, error) {
 	var rsp TransactionsWithResults
 	if err := c.conn.Invoke(ctx, methodGetTransactionsWithResults.FullName(), height, &rsp); err != nil {
@@ -764,6 +1303,49 @@ func (c *consensusClient) GetTransactionsWithResults(ctx context.Context, height
 	return &rsp, nil
 }
 
+func (c *consensusClient) GetTransactionsWithResultsFiltered(
+	ctx context.Context,
+	req *GetTransactionsWithResultsRequest,
+) (*TransactionsWithResults, error) {
+	var rsp TransactionsWithResults
+	if err := c.conn.Invoke(ctx, methodGetTransactionsWithResultsFiltered.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *consensusClient) GetBlockSignatures(ctx context.Context, height int64) ([]CommitSig, error) {
+	var rsp []CommitSig
+	if err := c.conn.Invoke(ctx, methodGetBlockSignatures.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *consensusClient) GetEventsAtHeight(ctx context.Context, height int64) ([]*results.Event, error) {
+	var rsp []*results.Event
+	if err := c.conn.Invoke(ctx, methodGetEventsAtHeight.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *consensusClient) GetDecodedBlockResults(ctx context.Context, height int64) (*DecodedBlockResults, error) {
+	var rsp DecodedBlockResults
+	if err := c.conn.Invoke(ctx, methodGetDecodedBlockResults.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *consensusClient) GetBlockWithResults(ctx context.Context, height int64) (*BlockWithResults, error) {
+	var rsp BlockWithResults
+	if err := c.conn.Invoke(ctx, methodGetBlockWithResults.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error) {
 	var rsp [][]byte
 	if err := c.conn.Invoke(ctx, methodGetUnconfirmedTransactions.FullName(), nil, &rsp); err != nil {
@@ -772,6 +1354,22 @@ func (c *consensusClient) GetUnconfirmedTransactions(ctx context.Context) ([][]b
 	return rsp, nil
 }
 
+func (c *consensusClient) GetUnconfirmedTransactionsWithMeta(ctx context.Context) ([]*MempoolTx, error) {
+	var rsp []*MempoolTx
+	if err := c.conn.Invoke(ctx, methodGetUnconfirmedTransactionsWithMeta.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *consensusClient) GetBlockIntervalStats(ctx context.Context, window int) (*BlockIntervalStats, error) {
+	var rsp BlockIntervalStats
+	if err := c.conn.Invoke(ctx, methodGetBlockIntervalStats.FullName(), window, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) GetGenesisDocument(ctx context.Context) (*genesis.Document, error) {
 	var rsp genesis.Document
 	if err := c.conn.Invoke(ctx, methodGetGenesisDocument.FullName(), nil, &rsp); err != nil {
@@ -823,6 +1421,89 @@ func (c *consensusClient) WatchBlocks(ctx context.Context) (<-chan *Block, pubsu
 	return ch, sub, nil
 }
 
+func (c *consensusClient) WatchUnconfirmedTransactions(ctx context.Context) (<-chan [][]byte, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[2], methodWatchUnconfirmedTransactions.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(nil); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan [][]byte)
+	go func() {
+		defer close(ch)
+
+		for {
+			var txs [][]byte
+			if serr := stream.RecvMsg(&txs); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- txs:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
+func (c *consensusClient) StreamTransactionsWithResults(
+	ctx context.Context,
+	startHeight int64,
+	endHeight int64,
+) (<-chan *HeightTransactions, error) {
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[1], methodStreamTransactionsWithResults.FullName())
+	if err != nil {
+		return nil, err
+	}
+	req := StreamTransactionsWithResultsRequest{
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+	}
+	if err = stream.SendMsg(&req); err != nil {
+		return nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *HeightTransactions)
+	go func() {
+		defer close(ch)
+
+		for {
+			var txs HeightTransactions
+			if serr := stream.RecvMsg(&txs); serr != nil {
+				if serr == io.EOF {
+					return
+				}
+				txs = HeightTransactions{Err: serr}
+			}
+
+			select {
+			case ch <- &txs:
+			case <-ctx.Done():
+				return
+			}
+
+			if txs.Err != nil {
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
 // NewConsensusClient creates a new gRPC consensus client service.
 func NewConsensusClient(c *grpc.ClientConn) ClientBackend {
 	return &consensusClient{
@@ -25,6 +25,9 @@ type LightClientBackend interface {
 	SubmitTxNoWait(ctx context.Context, tx *transaction.SignedTransaction) error
 
 	// SubmitEvidence submits evidence of misbehavior.
+	//
+	// In case the evidence is older than the maximum evidence age allowed by the consensus
+	// parameters, ErrEvidenceTooOld is returned and the evidence is not broadcast.
 	SubmitEvidence(ctx context.Context, evidence *Evidence) error
 }
 
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -18,6 +19,10 @@ import (
 const (
 	maxSubmissionRetryElapsedTime = 60 * time.Second
 	maxSubmissionRetryInterval    = 10 * time.Second
+
+	// maxSubmissionNonceRetries is the maximum number of times a submission is retried with a
+	// freshly fetched nonce after being rejected due to an invalid nonce.
+	maxSubmissionNonceRetries = 3
 )
 
 // PriceDiscovery is the consensus fee price discovery interface.
@@ -51,9 +56,16 @@ type SubmissionManager interface {
 	//
 	// It also automatically handles retries in case the nonce was incorrectly estimated.
 	SignAndSubmitTx(ctx context.Context, signer signature.Signer, tx *transaction.Transaction) error
+
+	// SetGasPrice replaces the configured price discovery mechanism with a static gas price.
+	//
+	// Submissions that are already in progress continue to use the previous price.
+	SetGasPrice(price uint64) error
 }
 
 type submissionManager struct {
+	sync.RWMutex
+
 	backend        ClientBackend
 	priceDiscovery PriceDiscovery
 	maxFee         quantity.Quantity
@@ -61,7 +73,35 @@ type submissionManager struct {
 	logger *logging.Logger
 }
 
-func (m *submissionManager) signAndSubmitTx(ctx context.Context, signer signature.Signer, tx *transaction.Transaction) error {
+func (m *submissionManager) getPriceDiscovery() PriceDiscovery {
+	m.RLock()
+	defer m.RUnlock()
+	return m.priceDiscovery
+}
+
+func (m *submissionManager) SetGasPrice(price uint64) error {
+	pd, err := NewStaticPriceDiscovery(price)
+	if err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.priceDiscovery = pd
+
+	m.logger.Info("submission gas price updated",
+		"gas_price", price,
+	)
+
+	return nil
+}
+
+func (m *submissionManager) signAndSubmitTx(
+	ctx context.Context,
+	pd PriceDiscovery,
+	signer signature.Signer,
+	tx *transaction.Transaction,
+) error {
 	// Update transaction nonce.
 	var err error
 	signerAddr := staking.NewAddress(signer.Public())
@@ -87,7 +127,7 @@ func (m *submissionManager) signAndSubmitTx(ctx context.Context, signer signatur
 
 		// Fetch current consensus gas price and compute the fee.
 		var amount *quantity.Quantity
-		amount, err = m.priceDiscovery.GasPrice(ctx)
+		amount, err = pd.GasPrice(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to determine gas price: %w", err)
 		}
@@ -142,8 +182,26 @@ func (m *submissionManager) SignAndSubmitTx(ctx context.Context, signer signatur
 	sched.MaxInterval = maxSubmissionRetryInterval
 	sched.MaxElapsedTime = maxSubmissionRetryElapsedTime
 
+	// Use the same price discovery mechanism for all attempts so that a concurrent gas price
+	// update does not affect a submission that is already in progress.
+	pd := m.getPriceDiscovery()
+
+	var nonceRetries int
 	return backoff.Retry(func() error {
-		return m.signAndSubmitTx(ctx, signer, tx)
+		err := m.signAndSubmitTx(ctx, pd, signer, tx)
+		if errors.Is(err, transaction.ErrInvalidNonce) {
+			// The nonce is re-fetched on each attempt, so a persistent invalid nonce is likely
+			// not caused by a transient race and should be reported.
+			nonceRetries++
+			if nonceRetries > maxSubmissionNonceRetries {
+				m.logger.Error("giving up transaction submission after repeated invalid nonce",
+					"account_address", staking.NewAddress(signer.Public()),
+					"retries", maxSubmissionNonceRetries,
+				)
+				return backoff.Permanent(err)
+			}
+		}
+		return err
 	}, backoff.WithContext(sched, ctx))
 }
 
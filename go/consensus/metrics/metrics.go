@@ -21,10 +21,36 @@ var (
 		},
 		[]string{"backend"},
 	)
+	BlockTxs = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "oasis_consensus_block_txs",
+			Help:    "Number of transactions in a block.",
+			Buckets: []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000},
+		},
+		[]string{"backend"},
+	)
+	BlockBytes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "oasis_consensus_block_bytes",
+			Help:    "Serialized size of a block (bytes).",
+			Buckets: prometheus.ExponentialBuckets(1024, 2, 14),
+		},
+		[]string{"backend"},
+	)
+	SubscribersWaitingStart = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_consensus_subscribers_waiting_start",
+			Help: "Number of subscribers blocked waiting for the consensus backend to start.",
+		},
+		[]string{"backend"},
+	)
 
 	consensusCollectors = []prometheus.Collector{
 		SignedBlocks,
 		ProposedBlocks,
+		BlockTxs,
+		BlockBytes,
+		SubscribersWaitingStart,
 	}
 
 	metricsOnce sync.Once
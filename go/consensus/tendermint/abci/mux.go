@@ -14,8 +14,10 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tendermint/tendermint/abci/types"
+	tmconfig "github.com/tendermint/tendermint/config"
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
@@ -30,6 +32,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -45,6 +48,10 @@ const (
 	// LogEventABCIStateSyncComplete is a log event value that signals an ABCI state syncing
 	// completed event.
 	LogEventABCIStateSyncComplete = "tendermint/abci/state_sync_complete"
+
+	// LogEventABCIHalted is a log event value that signals that the consensus layer has halted
+	// at the configured halt epoch.
+	LogEventABCIHalted = "tendermint/abci/halted"
 )
 
 var (
@@ -54,13 +61,34 @@ var (
 			Help: "Total size of the ABCI database (MiB).",
 		},
 	)
+	abciAppProcessTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "oasis_consensus_app_process_seconds",
+			Help: "Time spent by ABCI applications processing blocks and transactions (seconds).",
+		},
+		[]string{"app", "phase"},
+	)
+	abciHalted = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "oasis_consensus_halted",
+			Help: "Whether the consensus layer has halted at the configured halt epoch.",
+		},
+	)
 	abciCollectors = []prometheus.Collector{
 		abciSize,
+		abciHalted,
+		abciAppProcessTime,
 	}
 
 	metricsOnce sync.Once
 )
 
+// observeAppProcessTime records the time spent by an application in the given
+// processing phase.
+func observeAppProcessTime(app api.Application, phase string, start time.Time) {
+	abciAppProcessTime.With(prometheus.Labels{"app": app.Name(), "phase": phase}).Observe(time.Since(start).Seconds())
+}
+
 // ApplicationConfig is the configuration for the consensus application.
 type ApplicationConfig struct { // nolint: maligned
 	DataDir         string
@@ -135,6 +163,12 @@ func (a *ApplicationServer) Register(app api.Application) error {
 	return a.mux.doRegister(app)
 }
 
+// RegisteredApplications returns the names of all registered applications in registration
+// order.
+func (a *ApplicationServer) RegisteredApplications() []string {
+	return append([]string{}, a.mux.appNames...)
+}
+
 // RegisterHaltHook registers a function to be called when the
 // consensus Halt epoch height is reached.
 func (a *ApplicationServer) RegisterHaltHook(hook func(ctx context.Context, blockHeight int64, epoch epochtime.EpochTime)) {
@@ -170,12 +204,59 @@ func (a *ApplicationServer) TransactionAuthHandler() api.TransactionAuthHandler
 	return a.mux.state.txAuthHandler
 }
 
+// CreateCheckpoint immediately creates a state checkpoint for the given height
+// and returns once the checkpoint has been created.
+func (a *ApplicationServer) CreateCheckpoint(ctx context.Context, height int64) error {
+	if a.mux.state.checkpointer == nil {
+		return fmt.Errorf("mux: checkpointer is disabled")
+	}
+	return a.mux.state.checkpointer.ForceCheckpoint(ctx, uint64(height))
+}
+
+// GetMempoolTxMeta returns the metadata of a transaction that has been accepted
+// into the mempool via CheckTx and has not yet been included in a block or
+// invalidated by a re-check.
+func (a *ApplicationServer) GetMempoolTxMeta(txHash hash.Hash) (*MempoolTxMeta, bool) {
+	return a.mux.getMempoolTxMeta(txHash)
+}
+
 // WatchInvalidatedTx adds a watcher for when/if the transaction with given
-// hash becomes invalid due to a failed re-check.
+// hash becomes invalid due to a failed re-check. The error delivered on the
+// channel is an *api.InvalidatedTxError.
 func (a *ApplicationServer) WatchInvalidatedTx(txHash hash.Hash) (<-chan error, pubsub.ClosableSubscription, error) {
 	return a.mux.watchInvalidatedTx(txHash)
 }
 
+// WatchMempoolTxs returns a channel that produces raw transactions as they are accepted into
+// the mempool for the first time.
+func (a *ApplicationServer) WatchMempoolTxs() (<-chan []byte, pubsub.ClosableSubscription) {
+	typedCh := make(chan []byte)
+	sub := a.mux.mempoolNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub
+}
+
+// CheckTxDisabled returns true iff CheckTx is disabled for incoming transactions.
+func (a *ApplicationServer) CheckTxDisabled() bool {
+	return a.mux.state.disableCheckTx
+}
+
+// CheckTxDryRun checks the given raw transaction in the same way as CheckTx does, but without
+// adding it to the mempool or modifying the CheckTx state.
+func (a *ApplicationServer) CheckTxDryRun(rawTx []byte) error {
+	return a.mux.CheckTxDryRun(rawTx)
+}
+
+// GetApplicationState returns all key/value pairs stored under the state key prefix of the given
+// application at the given height.
+//
+// If the total size of returned keys and values would exceed maxSize bytes, the iteration is
+// aborted and api.ErrApplicationStateTooLarge is returned.
+func (a *ApplicationServer) GetApplicationState(ctx context.Context, name string, height int64, maxSize uint64) (writelog.WriteLog, error) {
+	return a.mux.getApplicationState(ctx, name, height, maxSize)
+}
+
 // EstimateGas calculates the amount of gas required to execute the given transaction.
 func (a *ApplicationServer) EstimateGas(caller signature.PublicKey, tx *transaction.Transaction) (transaction.Gas, error) {
 	return a.mux.EstimateGas(caller, tx)
@@ -216,6 +297,8 @@ type abciMux struct {
 	appsByMethod   map[transaction.MethodName]api.Application
 	appsByLexOrder []api.Application
 	appBlessed     api.Application
+	// appNames are the names of all registered applications in registration order.
+	appNames []string
 
 	lastBeginBlock int64
 	currentTime    time.Time
@@ -225,9 +308,26 @@ type abciMux struct {
 	// invalidatedTxs maps transaction hashes (hash.Hash) to a subscriber
 	// waiting for that transaction to become invalid.
 	invalidatedTxs sync.Map
+	// mempoolTxs maps transaction hashes (hash.Hash) to metadata (*MempoolTxMeta)
+	// about transactions that have been accepted into the mempool. It is bounded
+	// to the mempool size so entries for transactions that were evicted from the
+	// mempool without us being notified are eventually dropped.
+	mempoolTxs *lru.Cache
 	// debugExpiringTxs maps transaction hashes to the time at which they were created. This is only
 	// used in case CheckTx is disabled (for debug purposes only).
 	debugExpiringTxs map[hash.Hash]time.Time
+	// mempoolNotifier notifies subscribers about transactions newly accepted into the mempool.
+	mempoolNotifier *pubsub.Broker
+}
+
+// MempoolTxMeta is the metadata about a transaction that has been accepted into
+// the mempool.
+type MempoolTxMeta struct {
+	// FirstSeen is the (local) time at which the transaction was first accepted.
+	FirstSeen time.Time
+	// GasWanted is the amount of gas wanted by the transaction as reported by
+	// the most recent CheckTx.
+	GasWanted transaction.Gas
 }
 
 type invalidatedTxSubscription struct {
@@ -259,6 +359,34 @@ func (mux *abciMux) watchInvalidatedTx(txHash hash.Hash) (<-chan error, pubsub.C
 	return resultCh, sub, nil
 }
 
+func (mux *abciMux) getMempoolTxMeta(txHash hash.Hash) (*MempoolTxMeta, bool) {
+	meta, ok := mux.mempoolTxs.Peek(txHash)
+	if !ok {
+		return nil, false
+	}
+	return meta.(*MempoolTxMeta), true
+}
+
+func (mux *abciMux) updateMempoolTxMeta(txHash hash.Hash, gasWanted transaction.Gas) {
+	meta := &MempoolTxMeta{
+		FirstSeen: time.Now(),
+		GasWanted: gasWanted,
+	}
+	if existing, ok := mux.getMempoolTxMeta(txHash); ok {
+		meta.FirstSeen = existing.FirstSeen
+	}
+	_ = mux.mempoolTxs.Put(txHash, meta)
+}
+
+// notifyNewMempoolTx notifies subscribers about a transaction that has been accepted into the
+// mempool for the first time (i.e. not as part of a re-check).
+func (mux *abciMux) notifyNewMempoolTx(req types.RequestCheckTx) {
+	if req.Type != types.CheckTxType_New {
+		return
+	}
+	mux.mempoolNotifier.Broadcast(req.Tx)
+}
+
 func (mux *abciMux) registerHaltHook(hook func(context.Context, int64, epochtime.EpochTime)) {
 	mux.Lock()
 	defer mux.Unlock()
@@ -414,7 +542,9 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 		mux.logger.Info("BeginBlock: halt mode transition, emitting empty blocks.",
 			"block_height", blockHeight,
 			"epoch", mux.state.haltEpochHeight,
+			logging.LogEvent, LogEventABCIHalted,
 		)
+		abciHalted.Set(1)
 		mux.logger.Debug("Dispatching halt hooks")
 		for _, hook := range mux.haltHooks {
 			hook(mux.state.ctx, blockHeight, mux.state.haltEpochHeight)
@@ -437,7 +567,10 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 
 	// Dispatch BeginBlock to all applications.
 	for _, app := range mux.appsByLexOrder {
-		if err := app.BeginBlock(ctx, req); err != nil {
+		start := time.Now()
+		err := app.BeginBlock(ctx, req)
+		observeAppProcessTime(app, "begin_block", start)
+		if err != nil {
 			mux.logger.Error("BeginBlock: fatal error in application",
 				"err", err,
 				"app", app.Name(),
@@ -549,7 +682,17 @@ func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txS
 		"tx", tx,
 	)
 
-	if err := app.ExecuteTx(ctx, tx); err != nil {
+	// Only observe transaction processing time when transactions are actually delivered.
+	observe := func(app api.Application, start time.Time) {
+		if ctx.Mode() == api.ContextDeliverTx {
+			observeAppProcessTime(app, "deliver_tx", start)
+		}
+	}
+
+	start := time.Now()
+	err := app.ExecuteTx(ctx, tx)
+	observe(app, start)
+	if err != nil {
 		return err
 	}
 
@@ -560,7 +703,10 @@ func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txS
 			continue
 		}
 
-		if err := foreignApp.ForeignExecuteTx(ctx, app, tx); err != nil {
+		start = time.Now()
+		err = foreignApp.ForeignExecuteTx(ctx, app, tx)
+		observe(foreignApp, start)
+		if err != nil {
 			return err
 		}
 	}
@@ -580,6 +726,64 @@ func (mux *abciMux) executeTx(ctx *api.Context, rawTx []byte) error {
 	return mux.processTx(ctx, tx, len(rawTx))
 }
 
+func (mux *abciMux) CheckTxDryRun(rawTx []byte) error {
+	if mux.state.disableCheckTx {
+		// CheckTx would blindly accept all transactions.
+		return nil
+	}
+
+	// As with EstimateGas, this method can be called in parallel to the consensus layer so it
+	// runs against a separate tree at the latest committed block height.
+	ctx, closeTree := mux.state.NewCheckTxDryRunContext()
+	defer closeTree()
+	defer ctx.Close()
+
+	return mux.executeTx(ctx, rawTx)
+}
+
+func (mux *abciMux) getApplicationState(ctx context.Context, name string, height int64, maxSize uint64) (writelog.WriteLog, error) {
+	app := mux.appsByName[name]
+	if app == nil {
+		return nil, fmt.Errorf("mux: unknown application: %s", name)
+	}
+	// Application state keys are prefixed by a single byte whose high nibble is the application
+	// identifier, so applications with larger identifiers cannot store any state.
+	if app.ID() > 0x0f {
+		return nil, fmt.Errorf("mux: application %s does not have a state key prefix", name)
+	}
+
+	state, err := api.NewImmutableState(ctx, mux.state, height)
+	if err != nil {
+		return nil, err
+	}
+	defer state.Close()
+
+	it := state.NewIterator(ctx)
+	defer it.Close()
+
+	var (
+		wl   writelog.WriteLog
+		size uint64
+	)
+	for it.Seek([]byte{app.ID() << 4}); it.Valid(); it.Next() {
+		key := it.Key()
+		if key[0]>>4 != app.ID() {
+			break
+		}
+
+		size += uint64(len(key) + len(it.Value()))
+		if size > maxSize {
+			return nil, fmt.Errorf("%w: more than %d bytes", api.ErrApplicationStateTooLarge, maxSize)
+		}
+		wl = append(wl, writelog.LogEntry{Key: key, Value: it.Value()})
+	}
+	if err = it.Err(); err != nil {
+		return nil, fmt.Errorf("mux: failed to iterate application state: %w", err)
+	}
+
+	return wl, nil
+}
+
 func (mux *abciMux) EstimateGas(caller signature.PublicKey, tx *transaction.Transaction) (transaction.Gas, error) {
 	// As opposed to other transaction dispatch entry points (CheckTx/DeliverTx), this method can
 	// be called in parallel to the consensus layer and to other invocations.
@@ -634,9 +838,15 @@ func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 			// Check timestamp.
 			if ts, ok := mux.debugExpiringTxs[txHash]; ok && mux.currentTime.Sub(ts) > debugTxLifetime {
 				delete(mux.debugExpiringTxs, txHash)
+				mux.mempoolTxs.Remove(txHash)
 
 				err := fmt.Errorf("mux: transaction expired (debug only)")
-				mux.notifyInvalidatedCheckTx(txHash, err)
+				mux.notifyInvalidatedCheckTx(txHash, &api.InvalidatedTxError{
+					Height: mux.state.BlockHeight(),
+					Module: errors.UnknownModule,
+					Code:   1,
+					Err:    err,
+				})
 
 				return types.ResponseCheckTx{
 					Codespace: errors.UnknownModule,
@@ -647,6 +857,8 @@ func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 		} else {
 			mux.debugExpiringTxs[txHash] = mux.currentTime
 		}
+		mux.updateMempoolTxMeta(txHash, 0)
+		mux.notifyNewMempoolTx(req)
 
 		return types.ResponseCheckTx{
 			Code: types.CodeTypeOK,
@@ -656,6 +868,7 @@ func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 	ctx := mux.state.NewContext(api.ContextCheckTx, mux.currentTime)
 	defer ctx.Close()
 
+	txHash := hash.NewFromBytes(req.Tx)
 	if err := mux.executeTx(ctx, req.Tx); err != nil {
 		module, code := errors.Code(err)
 
@@ -666,9 +879,13 @@ func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 
 			// XXX: The Tendermint mempool should have provisions for this instead
 			//      of us hacking our way through this here.
-			txHash := hash.NewFromBytes(req.Tx)
-
-			mux.notifyInvalidatedCheckTx(txHash, err)
+			mux.mempoolTxs.Remove(txHash)
+			mux.notifyInvalidatedCheckTx(txHash, &api.InvalidatedTxError{
+				Height: mux.state.BlockHeight(),
+				Module: module,
+				Code:   code,
+				Err:    err,
+			})
 		}
 
 		return types.ResponseCheckTx{
@@ -680,6 +897,9 @@ func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 		}
 	}
 
+	mux.updateMempoolTxMeta(txHash, ctx.Gas().GasWanted())
+	mux.notifyNewMempoolTx(req)
+
 	return types.ResponseCheckTx{
 		Code:      types.CodeTypeOK,
 		GasWanted: int64(ctx.Gas().GasWanted()),
@@ -691,6 +911,9 @@ func (mux *abciMux) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverT
 	ctx := mux.state.NewContext(api.ContextDeliverTx, mux.currentTime)
 	defer ctx.Close()
 
+	// Once included in a block, the transaction is removed from the mempool.
+	mux.mempoolTxs.Remove(hash.NewFromBytes(req.Tx))
+
 	if err := mux.executeTx(ctx, req.Tx); err != nil {
 		if api.IsUnavailableStateError(err) {
 			// Make sure to not commit any transactions which include results based on unavailable
@@ -738,7 +961,9 @@ func (mux *abciMux) EndBlock(req types.RequestEndBlock) types.ResponseEndBlock {
 	// Dispatch EndBlock to all applications.
 	resp := mux.BaseApplication.EndBlock(req)
 	for _, app := range mux.appsByLexOrder {
+		start := time.Now()
 		newResp, err := app.EndBlock(ctx, req)
+		observeAppProcessTime(app, "end_block", start)
 		if err != nil {
 			mux.logger.Error("EndBlock: fatal error in application",
 				"err", err,
@@ -792,15 +1017,21 @@ func (mux *abciMux) Commit() types.ResponseCommit {
 }
 
 func (mux *abciMux) ListSnapshots(req types.RequestListSnapshots) types.ResponseListSnapshots {
-	// Get a list of all current checkpoints.
-	cps, err := mux.state.storage.Checkpointer().GetCheckpoints(mux.state.ctx, &checkpoint.GetCheckpointsRequest{
-		Version: 1,
-	})
-	if err != nil {
-		mux.logger.Error("failed to get checkpoints",
-			"err", err,
-		)
-		return types.ResponseListSnapshots{}
+	// Get a list of all current checkpoints in all supported formats so that clients which only
+	// support older formats are still able to sync.
+	var cps []*checkpoint.Metadata
+	for _, version := range checkpoint.SupportedVersions {
+		vcps, err := mux.state.storage.Checkpointer().GetCheckpoints(mux.state.ctx, &checkpoint.GetCheckpointsRequest{
+			Version: version,
+		})
+		if err != nil {
+			mux.logger.Error("failed to get checkpoints",
+				"err", err,
+				"version", version,
+			)
+			return types.ResponseListSnapshots{}
+		}
+		cps = append(cps, vcps...)
 	}
 
 	var rsp types.ResponseListSnapshots
@@ -823,7 +1054,9 @@ func (mux *abciMux) OfferSnapshot(req types.RequestOfferSnapshot) types.Response
 	if req.Snapshot == nil {
 		return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_REJECT}
 	}
-	if req.Snapshot.Format != 1 {
+	if req.Snapshot.Format > math.MaxUint16 || !checkpoint.IsVersionSupported(uint16(req.Snapshot.Format)) {
+		// Rejecting the format makes Tendermint skip all other snapshots using the same format and
+		// pick one using a format that we support instead.
 		mux.logger.Warn("received snapshot with unsupported version",
 			"version", req.Snapshot.Format,
 		)
@@ -857,6 +1090,15 @@ func (mux *abciMux) OfferSnapshot(req types.RequestOfferSnapshot) types.Response
 		return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_REJECT}
 	}
 
+	// Format version must match.
+	if uint32(cp.Version) != req.Snapshot.Format {
+		mux.logger.Warn("received snapshot with mismatching format version",
+			"expected_version", req.Snapshot.Format,
+			"version", cp.Version,
+		)
+		return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_REJECT}
+	}
+
 	// Number of chunks must match.
 	if int(req.Snapshot.Chunks) != len(cp.Chunks) {
 		mux.logger.Warn("received snapshot with mismatching number of chunks",
@@ -1037,6 +1279,7 @@ func (mux *abciMux) doRegister(app api.Application) error {
 		}
 		mux.appsByMethod[m] = app
 	}
+	mux.appNames = append(mux.appNames, name)
 	mux.rebuildAppLexOrdering() // Inefficient but not a lot of apps.
 
 	app.OnRegister(mux.state)
@@ -1082,13 +1325,22 @@ func newABCIMux(ctx context.Context, upgrader upgrade.Backend, cfg *ApplicationC
 		return nil, err
 	}
 
+	// Transactions still in the mempool are refreshed on every (re)check, so
+	// evicting the least-recently-used entries only drops stale metadata.
+	mempoolTxs, err := lru.New(lru.Capacity(uint64(tmconfig.DefaultMempoolConfig().Size), false))
+	if err != nil {
+		return nil, fmt.Errorf("mux: failed to create mempool metadata cache: %w", err)
+	}
+
 	mux := &abciMux{
-		logger:         logging.GetLogger("abci-mux"),
-		upgrader:       upgrader,
-		state:          state,
-		appsByName:     make(map[string]api.Application),
-		appsByMethod:   make(map[transaction.MethodName]api.Application),
-		lastBeginBlock: -1,
+		logger:          logging.GetLogger("abci-mux"),
+		upgrader:        upgrader,
+		state:           state,
+		appsByName:      make(map[string]api.Application),
+		appsByMethod:    make(map[transaction.MethodName]api.Application),
+		lastBeginBlock:  -1,
+		mempoolTxs:      mempoolTxs,
+		mempoolNotifier: pubsub.NewBroker(false),
 	}
 
 	// Create a map of expiring transactions if CheckTx is disabled (debug only).
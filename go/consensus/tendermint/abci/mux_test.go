@@ -0,0 +1,206 @@
+package abci
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	abciState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/abci/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+	"github.com/oasisprotocol/oasis-core/go/upgrade"
+)
+
+type testApp struct {
+	name string
+	id   uint8
+}
+
+func (app *testApp) Name() string {
+	return app.name
+}
+
+func (app *testApp) ID() uint8 {
+	return app.id
+}
+
+func (app *testApp) Methods() []transaction.MethodName {
+	return nil
+}
+
+func (app *testApp) Blessed() bool {
+	return false
+}
+
+func (app *testApp) Dependencies() []string {
+	return nil
+}
+
+func (app *testApp) QueryFactory() interface{} {
+	return nil
+}
+
+func (app *testApp) OnRegister(state api.ApplicationState) {
+}
+
+func (app *testApp) OnCleanup() {
+}
+
+func (app *testApp) ExecuteTx(ctx *api.Context, tx *transaction.Transaction) error {
+	return nil
+}
+
+func (app *testApp) ForeignExecuteTx(ctx *api.Context, other api.Application, tx *transaction.Transaction) error {
+	return nil
+}
+
+func (app *testApp) InitChain(ctx *api.Context, req types.RequestInitChain, doc *genesis.Document) error {
+	return nil
+}
+
+func (app *testApp) BeginBlock(ctx *api.Context, req types.RequestBeginBlock) error {
+	return nil
+}
+
+func (app *testApp) EndBlock(ctx *api.Context, req types.RequestEndBlock) (types.ResponseEndBlock, error) {
+	return types.ResponseEndBlock{}, nil
+}
+
+func newTestMux(t *testing.T) (*abciMux, func()) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "abci-mux.test")
+	require.NoError(err, "TempDir")
+
+	mux, err := newABCIMux(context.Background(), upgrade.NewDummyUpgradeManager(), &ApplicationConfig{
+		DataDir:             dir,
+		StorageBackend:      "badger",
+		HaltEpochHeight:     math.MaxUint64,
+		DisableCheckpointer: true,
+		MemoryOnlyStorage:   true,
+		InitialHeight:       1,
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+	}
+	require.NoError(err, "newABCIMux")
+
+	return mux, func() {
+		mux.doCleanup()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestRegisteredApplications(t *testing.T) {
+	require := require.New(t)
+
+	mux, cleanup := newTestMux(t)
+	defer cleanup()
+	srv := &ApplicationServer{mux: mux}
+
+	require.Empty(srv.RegisteredApplications(), "no applications should be registered initially")
+
+	for _, app := range []*testApp{
+		{name: "second", id: 0x02},
+		{name: "first", id: 0x01},
+	} {
+		err := srv.Register(app)
+		require.NoError(err, "Register")
+	}
+	err := srv.Register(&testApp{name: "first", id: 0x03})
+	require.Error(err, "registering a duplicate application should fail")
+
+	apps := srv.RegisteredApplications()
+	require.Equal([]string{"second", "first"}, apps, "applications should be returned in registration order")
+
+	// The returned slice must be a copy.
+	apps[0] = "modified"
+	require.Equal([]string{"second", "first"}, srv.RegisteredApplications(), "returned slice should be a copy")
+}
+
+func TestGetApplicationState(t *testing.T) {
+	require := require.New(t)
+
+	mux, cleanup := newTestMux(t)
+	defer cleanup()
+
+	for _, app := range []*testApp{
+		{name: "first", id: 0x01},
+		{name: "second", id: 0x02},
+		{name: "empty", id: 0x03},
+		{name: "noprefix", id: 0x10},
+	} {
+		err := mux.doRegister(app)
+		require.NoError(err, "doRegister")
+	}
+
+	// Populate state for the first two applications. Consensus parameters are required for the
+	// commit to succeed and live under a prefix that does not belong to any application.
+	abciCtx := mux.state.NewContext(api.ContextInitChain, time.Now())
+	defer abciCtx.Close()
+	tree := abciCtx.State()
+	err := abciState.NewMutableState(tree).SetConsensusParameters(abciCtx, &consensusGenesis.Parameters{})
+	require.NoError(err, "SetConsensusParameters")
+
+	firstState := writelog.WriteLog{
+		{Key: []byte{0x10}, Value: []byte("first root")},
+		{Key: []byte{0x10, 0x01}, Value: []byte("first a")},
+		{Key: []byte{0x1f, 0xff}, Value: []byte("first b")},
+	}
+	secondState := writelog.WriteLog{
+		{Key: []byte{0x20, 0x01}, Value: []byte("second a")},
+	}
+	for _, wl := range []writelog.WriteLog{firstState, secondState} {
+		for _, entry := range wl {
+			err = tree.Insert(abciCtx, entry.Key, entry.Value)
+			require.NoError(err, "Insert")
+		}
+	}
+	_, err = mux.state.doCommit(time.Now())
+	require.NoError(err, "doCommit")
+	height := mux.state.BlockHeight()
+
+	// Query the committed state the same way external queries do.
+	ctx := context.Background()
+
+	wl, err := mux.getApplicationState(ctx, "first", height, math.MaxUint64)
+	require.NoError(err, "getApplicationState(first)")
+	require.EqualValues(firstState, wl, "first application state should only include its own keys")
+
+	wl, err = mux.getApplicationState(ctx, "second", height, math.MaxUint64)
+	require.NoError(err, "getApplicationState(second)")
+	require.EqualValues(secondState, wl, "second application state should only include its own keys")
+
+	wl, err = mux.getApplicationState(ctx, "empty", height, math.MaxUint64)
+	require.NoError(err, "getApplicationState(empty)")
+	require.Empty(wl, "application without state should return an empty write log")
+
+	_, err = mux.getApplicationState(ctx, "unknown", height, math.MaxUint64)
+	require.Error(err, "getApplicationState should fail for unknown applications")
+
+	_, err = mux.getApplicationState(ctx, "noprefix", height, math.MaxUint64)
+	require.Error(err, "getApplicationState should fail for applications without a key prefix")
+
+	// Size of the first two entries of the first application's state.
+	var maxSize uint64
+	for _, entry := range firstState[:2] {
+		maxSize += uint64(len(entry.Key) + len(entry.Value))
+	}
+	_, err = mux.getApplicationState(ctx, "first", height, maxSize)
+	require.Error(err, "getApplicationState should fail when the state exceeds the size limit")
+	require.True(errors.Is(err, api.ErrApplicationStateTooLarge), "error should be ErrApplicationStateTooLarge")
+
+	wl, err = mux.getApplicationState(ctx, "second", height, maxSize)
+	require.NoError(err, "getApplicationState should succeed when the state fits the size limit")
+	require.EqualValues(secondState, wl, "second application state should be returned in full")
+}
@@ -145,6 +145,7 @@ func (p *genericPruner) doPrune(ctx context.Context, latestVersion uint64) error
 	)
 
 	preserveFrom := latestVersion - p.keepN
+PruneLoop:
 	for i := p.earliestVersion; i <= latestVersion; i++ {
 		if i >= preserveFrom {
 			p.earliestVersion = i
@@ -165,6 +166,13 @@ func (p *genericPruner) doPrune(ctx context.Context, latestVersion uint64) error
 				"version", i,
 			)
 			continue
+		case nodedb.ErrRootPinned:
+			// Pruning must proceed in version order, so stop here and retry later.
+			p.logger.Debug("Prune: version contains a pinned root, stopping",
+				"version", i,
+			)
+			p.earliestVersion = i
+			break PruneLoop
 		default:
 			return err
 		}
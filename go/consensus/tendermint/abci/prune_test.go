@@ -81,3 +81,68 @@ func TestPruneKeepN(t *testing.T) {
 	lastRetainedVersion = pruner.GetLastRetainedVersion()
 	require.EqualValues(9, lastRetainedVersion, "last retained version should be correct")
 }
+
+func TestPruneKeepNPinned(t *testing.T) {
+	require := require.New(t)
+
+	// Create a new random temporary directory under /tmp.
+	dir, err := ioutil.TempDir("", "abci-prune.test.badger")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	// Create a Badger-backed Node DB.
+	ndb, err := mkvsBadgerDB.New(&mkvsDB.Config{
+		DB:           dir,
+		NoFsync:      true,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	tree := mkvs.New(nil, ndb)
+
+	ctx := context.Background()
+	var pinnedRoot hash.Hash
+	for i := uint64(1); i <= 11; i++ {
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("key:%d", i)), []byte(fmt.Sprintf("value:%d", i)))
+		require.NoError(err, "Insert")
+
+		var rootHash hash.Hash
+		_, rootHash, err = tree.Commit(ctx, common.Namespace{}, i)
+		require.NoError(err, "Commit")
+		err = ndb.Finalize(ctx, i, []hash.Hash{rootHash})
+		require.NoError(err, "Finalize")
+
+		if i == 3 {
+			pinnedRoot = rootHash
+		}
+	}
+
+	err = ndb.PinRoot(ctx, pinnedRoot)
+	require.NoError(err, "PinRoot")
+
+	// Pruning should quietly stop at the version containing the pinned root.
+	pruner, err := newStatePruner(&PruneConfig{
+		Strategy: PruneKeepN,
+		NumKept:  2,
+	}, ndb, 10)
+	require.NoError(err, "newStatePruner failed")
+
+	err = pruner.Prune(ctx, 11)
+	require.NoError(err, "Prune")
+
+	earliestVersion, err := ndb.GetEarliestVersion(ctx)
+	require.NoError(err, "GetEarliestVersion")
+	require.EqualValues(3, earliestVersion, "earliest version should be correct")
+	require.EqualValues(3, pruner.GetLastRetainedVersion(), "last retained version should be correct")
+
+	// After unpinning, pruning should resume.
+	err = ndb.UnpinRoot(ctx, pinnedRoot)
+	require.NoError(err, "UnpinRoot")
+
+	err = pruner.Prune(ctx, 11)
+	require.NoError(err, "Prune")
+
+	earliestVersion, err = ndb.GetEarliestVersion(ctx)
+	require.NoError(err, "GetEarliestVersion")
+	require.EqualValues(9, earliestVersion, "earliest version should be correct")
+	require.EqualValues(9, pruner.GetLastRetainedVersion(), "last retained version should be correct")
+}
@@ -112,6 +112,29 @@ func (s *applicationState) NewContext(mode api.ContextMode, now time.Time) *api.
 	)
 }
 
+// NewCheckTxDryRunContext creates a new CheckTx context which is backed by a separate in-memory
+// tree at the latest committed block height so any changes are discarded.
+//
+// The caller must call the returned function after closing the context to release the tree.
+func (s *applicationState) NewCheckTxDryRunContext() (*api.Context, func()) {
+	s.blockLock.RLock()
+	defer s.blockLock.RUnlock()
+
+	tree := mkvs.NewWithRoot(nil, s.storage.NodeDB(), s.stateRoot, mkvs.WithoutWriteLog())
+	ctx := api.NewContext(
+		s.ctx,
+		api.ContextCheckTx,
+		s.blockTime,
+		api.NewNopGasAccountant(),
+		s,
+		tree,
+		int64(s.stateRoot.Version),
+		nil,
+		int64(s.initialHeight),
+	)
+	return ctx, tree.Close
+}
+
 func (s *applicationState) LastRetainedVersion() (int64, error) {
 	return int64(s.statePruner.GetLastRetainedVersion()), nil
 }
@@ -566,9 +589,10 @@ func newApplicationState(ctx context.Context, cfg *ApplicationConfig) (*applicat
 			GetParameters: func(ctx context.Context) (*checkpoint.CreationParameters, error) {
 				params := s.ConsensusParameters()
 				return &checkpoint.CreationParameters{
-					Interval:  params.StateCheckpointInterval,
-					NumKept:   params.StateCheckpointNumKept,
-					ChunkSize: params.StateCheckpointChunkSize,
+					Interval:       params.StateCheckpointInterval,
+					InitialVersion: cfg.InitialHeight,
+					NumKept:        params.StateCheckpointNumKept,
+					ChunkSize:      params.StateCheckpointChunkSize,
 				}, nil
 			},
 		}
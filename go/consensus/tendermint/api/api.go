@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/crypto"
 	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
 
 // BackendName is the consensus backend name.
@@ -34,6 +36,20 @@ const (
 	LogEventPeerExchangeDisabled = "tendermint/peer_exchange_disabled"
 )
 
+var (
+	// ErrHeightPruned is the error returned when the given height has been
+	// pruned and is no longer available locally.
+	ErrHeightPruned = errors.New("tendermint: height has been pruned")
+
+	// ErrHeightNotYetAvailable is the error returned when the given height
+	// has not yet been committed.
+	ErrHeightNotYetAvailable = errors.New("tendermint: height not yet available")
+
+	// ErrApplicationStateTooLarge is the error returned when the requested
+	// application state exceeds the maximum size that can be returned.
+	ErrApplicationStateTooLarge = errors.New("tendermint: application state too large")
+)
+
 // PublicKeyToValidatorUpdate converts an Oasis node public key to a
 // tendermint validator update.
 func PublicKeyToValidatorUpdate(id signature.PublicKey, power int64) types.ValidatorUpdate {
@@ -186,10 +202,27 @@ type Backend interface {
 	// registered.
 	RegisterApplication(Application) error
 
+	// RegisteredApplications returns the names of all registered ABCI multiplexer applications
+	// in registration order. Applications disabled via debug flags are not included.
+	RegisteredApplications() []string
+
+	// CancelAllSubscriptions cancels all outstanding internal Tendermint event
+	// bus subscriptions (e.g., ones used while waiting for transaction
+	// inclusion) so that their waiters are released.
+	CancelAllSubscriptions()
+
 	// SetTransactionAuthHandler configures the transaction fee handler for the
 	// ABCI multiplexer.
 	SetTransactionAuthHandler(TransactionAuthHandler) error
 
+	// SubmitTxAndWaitForState submits a signed consensus transaction, waits
+	// for the transaction to be included in a block and returns the height of
+	// the including block.
+	//
+	// Querying state at the returned height is guaranteed to observe the
+	// effects of the transaction.
+	SubmitTxAndWaitForState(ctx context.Context, tx *transaction.SignedTransaction) (int64, error)
+
 	// GetBlock returns the Tendermint block at the specified height.
 	GetTendermintBlock(ctx context.Context, height int64) (*tmtypes.Block, error)
 
@@ -197,6 +230,10 @@ type Backend interface {
 	// at a specific height.
 	GetBlockResults(ctx context.Context, height int64) (*tmrpctypes.ResultBlockResults, error)
 
+	// GetLatestBlockResults returns the ABCI results from processing the
+	// latest block.
+	GetLatestBlockResults(ctx context.Context) (*tmrpctypes.ResultBlockResults, error)
+
 	// WatchTendermintBlocks returns a stream of Tendermint blocks as they are
 	// returned via the `EventDataNewBlock` query.
 	WatchTendermintBlocks() (<-chan *tmtypes.Block, *pubsub.Subscription)
@@ -204,6 +241,33 @@ type Backend interface {
 	// GetLastRetainedVersion returns the earliest retained version the ABCI
 	// state.
 	GetLastRetainedVersion(ctx context.Context) (int64, error)
+
+	// WaitForValidator waits for the local node to become a member of the
+	// validator set.
+	WaitForValidator(ctx context.Context) error
+
+	// IsHeightAvailable checks whether the given height is available locally.
+	//
+	// In case the height is not available, the returned error describes the
+	// reason (ErrHeightPruned or ErrHeightNotYetAvailable), so callers can
+	// decide whether to fetch it from an archive node instead.
+	IsHeightAvailable(ctx context.Context, height int64) (bool, error)
+
+	// CreateCheckpoint immediately creates a state checkpoint for the given
+	// retained height (e.g., for serving state sync) and returns once the
+	// checkpoint has been created.
+	CreateCheckpoint(ctx context.Context, height int64) error
+
+	// GetApplicationState returns all key/value pairs stored under the state
+	// key prefix of the given ABCI application at the given retained height.
+	//
+	// In case the application state is larger than the configured limit,
+	// ErrApplicationStateTooLarge is returned.
+	GetApplicationState(ctx context.Context, app string, height int64) (writelog.WriteLog, error)
+
+	// SetSubmissionGasPrice sets the gas price used by the submission manager
+	// for the node's own transactions, overriding the configured value.
+	SetSubmissionGasPrice(price uint64) error
 }
 
 // TransactionAuthHandler is the interface for ABCI applications that handle
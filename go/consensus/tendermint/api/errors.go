@@ -38,3 +38,29 @@ func UnavailableStateError(err error) error {
 func IsUnavailableStateError(err error) bool {
 	return errors.Is(err, &errorUnavailableState{})
 }
+
+// InvalidatedTxError is the error delivered to watchers of a transaction that
+// was invalidated while being re-checked in the mempool.
+type InvalidatedTxError struct {
+	// Height is the height of the latest committed block when the transaction
+	// was invalidated.
+	Height int64
+	// Module is the codespace of the failed re-check.
+	Module string
+	// Code is the code of the failed re-check.
+	Code uint32
+	// Err is the error returned by the failed re-check.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *InvalidatedTxError) Error() string {
+	return fmt.Sprintf("transaction invalidated by recheck at height %d (module: %s code: %d): %s",
+		e.Height, e.Module, e.Code, e.Err,
+	)
+}
+
+// Unwrap returns the underlying error.
+func (e *InvalidatedTxError) Unwrap() error {
+	return e.Err
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
 )
 
 func TestErrors(t *testing.T) {
@@ -29,3 +31,26 @@ func TestErrors(t *testing.T) {
 	err = UnavailableStateError(nilInterface)
 	require.False(IsUnavailableStateError(err))
 }
+
+func TestInvalidatedTxError(t *testing.T) {
+	require := require.New(t)
+
+	innerErr := errors.New("test/invalidated", 1, "inner error")
+	var err error = &InvalidatedTxError{
+		Height: 42,
+		Module: "test/invalidated",
+		Code:   1,
+		Err:    innerErr,
+	}
+	require.True(errors.Is(err, innerErr), "invalidated tx error should unwrap")
+	require.Contains(err.Error(), "height 42")
+	require.Contains(err.Error(), "inner error")
+
+	module, code := errors.Code(err)
+	require.Equal("test/invalidated", module)
+	require.EqualValues(1, code)
+
+	var ite *InvalidatedTxError
+	require.True(errors.As(fmt.Errorf("wrapped: %w", err), &ite))
+	require.EqualValues(42, ite.Height)
+}
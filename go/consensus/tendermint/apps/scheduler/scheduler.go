@@ -265,6 +265,11 @@ func (app *schedulerApplication) EndBlock(ctx *api.Context, req types.RequestEnd
 	// the difference between the current validator set (tracked manually
 	// from InitChain), and the new validator set, which is a huge pain
 	// in the ass.
+	//
+	// Note that Tendermint retains the accumulated proposer priority of all
+	// validators that remain in the set (even if their voting power changes)
+	// and there is no way to reset it via ValidatorUpdate. Only newly added
+	// validators start with a fresh (penalized) priority.
 
 	resp.ValidatorUpdates = diffValidators(ctx.Logger(), currentValidators, pendingValidators)
 
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/eapache/channels"
 
@@ -26,8 +27,12 @@ type serviceClient struct {
 
 	logger *logging.Logger
 
+	backend  tmapi.Backend
 	notifier *pubsub.Broker
 
+	genesisHeight int64
+	blockInterval time.Duration
+
 	interval     int64
 	lastNotified api.EpochTime
 	epoch        api.EpochTime
@@ -58,6 +63,52 @@ func (sc *serviceClient) GetEpochBlock(ctx context.Context, epoch api.EpochTime)
 	return height, nil
 }
 
+func (sc *serviceClient) GetEpochProgress(ctx context.Context, height int64) (*api.EpochProgress, error) {
+	blk, err := sc.backend.GetBlock(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("epochtime/tendermint: failed to get block: %w", err)
+	}
+	epoch, err := sc.GetEpoch(ctx, blk.Height)
+	if err != nil {
+		return nil, err
+	}
+	startHeight, err := sc.GetEpochBlock(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	nextHeight, err := sc.GetEpochBlock(ctx, epoch+1)
+	if err != nil {
+		return nil, err
+	}
+	// The first epoch may have started before the genesis block.
+	if startHeight < sc.genesisHeight {
+		startHeight = sc.genesisHeight
+	}
+
+	startBlk := blk
+	if startHeight != blk.Height {
+		if startBlk, err = sc.backend.GetBlock(ctx, startHeight); err != nil {
+			return nil, fmt.Errorf("epochtime/tendermint: failed to get epoch start block: %w", err)
+		}
+	}
+
+	// Estimate the time of the next transition based on the average block
+	// time observed during the epoch, falling back to the configured block
+	// interval if there are no observations yet.
+	blockTime := sc.blockInterval
+	if elapsed := blk.Height - startHeight; elapsed > 0 {
+		blockTime = blk.Time.Sub(startBlk.Time) / time.Duration(elapsed)
+	}
+
+	return &api.EpochProgress{
+		Epoch:       epoch,
+		StartHeight: startHeight,
+		StartTime:   startBlk.Time,
+		NextHeight:  nextHeight,
+		NextTime:    blk.Time.Add(time.Duration(nextHeight-blk.Height) * blockTime),
+	}, nil
+}
+
 func (sc *serviceClient) WatchEpochs() (<-chan api.EpochTime, *pubsub.Subscription) {
 	typedCh := make(chan api.EpochTime)
 	sub := sc.notifier.Subscribe()
@@ -120,10 +171,13 @@ func New(ctx context.Context, backend tmapi.Backend, interval int64) (ServiceCli
 
 	base := genDoc.EpochTime.Base
 	sc := &serviceClient{
-		logger:   logging.GetLogger("epochtime/tendermint"),
-		interval: interval,
-		base:     base,
-		epoch:    base,
+		logger:        logging.GetLogger("epochtime/tendermint"),
+		backend:       backend,
+		genesisHeight: genDoc.Height,
+		blockInterval: genDoc.Consensus.Parameters.TimeoutCommit,
+		interval:      interval,
+		base:          base,
+		epoch:         base,
 	}
 	sc.notifier = pubsub.NewBrokerEx(func(ch channels.Channel) {
 		sc.RLock()
@@ -97,6 +97,34 @@ func (sc *serviceClient) GetEpochBlock(ctx context.Context, epoch api.EpochTime)
 	}
 }
 
+func (sc *serviceClient) GetEpochProgress(ctx context.Context, height int64) (*api.EpochProgress, error) {
+	q, err := sc.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query epoch: %w", err)
+	}
+
+	epoch, startHeight, err := q.Epoch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query epoch: %w", err)
+	}
+
+	// Mock epoch transitions are triggered manually, so there is no way to
+	// estimate when the next transition will happen.
+	progress := &api.EpochProgress{
+		Epoch:            epoch,
+		StartHeight:      startHeight,
+		ManualTransition: true,
+	}
+	if startHeight > 0 {
+		blk, err := sc.backend.GetBlock(ctx, startHeight)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get epoch start block: %w", err)
+		}
+		progress.StartTime = blk.Time
+	}
+	return progress, nil
+}
+
 func (sc *serviceClient) WatchEpochs() (<-chan api.EpochTime, *pubsub.Subscription) {
 	typedCh := make(chan api.EpochTime)
 	sub := sc.notifier.Subscribe()
@@ -4,18 +4,24 @@ package full
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
+	"net"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/prometheus/client_golang/prometheus"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	tmabcitypes "github.com/tendermint/tendermint/abci/types"
 	tmconfig "github.com/tendermint/tendermint/config"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	tmed "github.com/tendermint/tendermint/crypto/ed25519"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	tmlight "github.com/tendermint/tendermint/light"
 	tmmempool "github.com/tendermint/tendermint/mempool"
@@ -72,6 +78,7 @@ import (
 	roothashAPI "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	schedulerAPI "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	stakingAPI "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 	upgradeAPI "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -102,16 +109,52 @@ const (
 	// automatic corrupted WAL recovery during replay.
 	CfgDebugUnsafeReplayRecoverCorruptedWAL = "consensus.tendermint.debug.unsafe_replay_recover_corrupted_wal"
 
+	// CfgDebugConsensusEmptyBlockInterval overrides the empty block interval set in genesis.
+	//
+	// NOTE: The interval must be the same on all validators, otherwise their view of when empty
+	// blocks should be proposed diverges.
+	CfgDebugConsensusEmptyBlockInterval = "consensus.tendermint.consensus.empty_block_interval"
+
 	// CfgMinGasPrice configures the minimum gas price for this validator.
 	CfgMinGasPrice = "consensus.tendermint.min_gas_price"
 	// CfgDebugDisableCheckTx disables CheckTx.
 	CfgDebugDisableCheckTx = "consensus.tendermint.debug.disable_check_tx"
+	// CfgDebugDisableApps disables the given ABCI applications.
+	//
+	// NOTE: This produces an invalid chain and is strictly for isolating issues
+	// on test nodes.
+	CfgDebugDisableApps = "consensus.tendermint.debug.disable_apps"
 
 	// CfgSupplementarySanityEnabled is the supplementary sanity enabled flag.
 	CfgSupplementarySanityEnabled = "consensus.tendermint.supplementarysanity.enabled"
 	// CfgSupplementarySanityInterval configures the supplementary sanity check interval.
 	CfgSupplementarySanityInterval = "consensus.tendermint.supplementarysanity.interval"
 
+	// CfgLocalQueryMaxConcurrency configures the maximum number of concurrent
+	// queries served by the in-process Tendermint client.
+	CfgLocalQueryMaxConcurrency = "consensus.tendermint.local_query.max_concurrency"
+	// CfgBlockIntervalStatsMaxWindow configures the maximum number of blocks
+	// considered when computing block interval statistics.
+	CfgBlockIntervalStatsMaxWindow = "consensus.tendermint.block_interval_stats.max_window"
+	// CfgP2PPeerStaleThreshold configures the duration without any data received from a peer
+	// after which the peer is reported as stale.
+	CfgP2PPeerStaleThreshold = "consensus.tendermint.p2p.peer_stale_threshold"
+	// CfgStartupWaitTimeout configures the maximum time API methods wait for the consensus
+	// backend to start before failing.
+	CfgStartupWaitTimeout = "consensus.tendermint.startup_wait_timeout"
+	// CfgSubscribeWaitTimeout configures the maximum time subscriptions made before the consensus
+	// backend has started wait for it to start before failing.
+	CfgSubscribeWaitTimeout = "consensus.tendermint.subscribe_wait_timeout"
+	// CfgMempoolRecheck configures whether transactions remaining in the mempool are rechecked
+	// after each block.
+	//
+	// NOTE: Disabling recheck can leave stale transactions in the mempool until they are reaped
+	// and WatchInvalidatedTx watchers will not be notified about transactions becoming invalid.
+	CfgMempoolRecheck = "consensus.tendermint.mempool.recheck"
+	// CfgRequireValidator enables checking that the node is part of the validator set after the
+	// first full epoch following the initial sync.
+	CfgRequireValidator = "consensus.tendermint.require_validator"
+
 	// CfgConsensusStateSyncEnabled enabled consensus state sync.
 	CfgConsensusStateSyncEnabled = "consensus.tendermint.state_sync.enabled"
 	// CfgConsensusStateSyncConsensusNode specifies nodes exposing public consensus services which
@@ -123,6 +166,9 @@ const (
 	CfgConsensusStateSyncTrustHeight = "consensus.tendermint.state_sync.trust_height"
 	// CfgConsensusStateSyncTrustHash is the known trusted block header hash for the light client.
 	CfgConsensusStateSyncTrustHash = "consensus.tendermint.state_sync.trust_hash"
+	// CfgConsensusStateSyncMaxAttempts is the number of attempts at setting up state sync before
+	// falling back to fast sync from genesis (0 disables the fallback).
+	CfgConsensusStateSyncMaxAttempts = "consensus.tendermint.state_sync.max_attempts"
 )
 
 const (
@@ -132,9 +178,16 @@ const (
 	// NOTE: this is only used during the initial sync.
 	syncWorkerLastBlockTimeDiffThreshold = 1 * time.Minute
 
+	// stateSyncRetryInterval is the interval between state sync setup attempts.
+	stateSyncRetryInterval = 10 * time.Second
+
 	// tmSubscriberID is the subscriber identifier used for all internal Tendermint pubsub
 	// subscriptions. If any other subscriber IDs need to be derived they will be under this prefix.
 	tmSubscriberID = "oasis-core"
+
+	// maxApplicationStateSize is the maximum total size (in bytes) of keys and values that can
+	// be returned by a single GetApplicationState call.
+	maxApplicationStateSize = 16 * 1024 * 1024
 )
 
 var (
@@ -157,9 +210,20 @@ type fullService struct { // nolint: maligned
 	mux           *abci.ApplicationServer
 	node          *tmnode.Node
 	client        *tmcli.Local
+	localQuerySem chan struct{}
 	blockNotifier *pubsub.Broker
 	failMonitor   *failMonitor
 
+	blockIntervalStatsMaxWindow int
+	peerStaleThreshold          time.Duration
+	startupWaitTimeout          time.Duration
+	subscribeWaitTimeout        time.Duration
+	requireValidator            bool
+
+	// validatorCheckFailed is non-zero in case the node is required to be a validator but was not
+	// part of the validator set during the last check. It must only be accessed atomically.
+	validatorCheckFailed uint32
+
 	stateStore tmstate.Store
 
 	beacon        beaconAPI.Backend
@@ -175,6 +239,7 @@ type fullService struct { // nolint: maligned
 	serviceClientsWg sync.WaitGroup
 
 	genesis                  *genesisAPI.Document
+	genesisHash              hash.Hash
 	genesisProvider          genesisAPI.Provider
 	identity                 *identity.Identity
 	dataDir                  string
@@ -184,7 +249,14 @@ type fullService struct { // nolint: maligned
 
 	startFn func() error
 
+	disabledApps map[string]bool
+	pruneCfg     abci.PruneConfig
+
 	nextSubscriberID uint64
+	subscribersLock  sync.Mutex
+	// subscribers maps subscriber identifiers issued by newSubscriberID to their buffered
+	// subscriptions. The subscription is nil until subscribe succeeds.
+	subscribers map[string]*tendermintPubsubBuffer
 }
 
 func (t *fullService) initialized() bool {
@@ -228,6 +300,10 @@ func (t *fullService) Start() error {
 		go t.syncWorker()
 		// Start block notifier.
 		go t.blockNotifierWorker()
+		// Optionally start validator checker.
+		if t.requireValidator {
+			go t.requireValidatorWorker()
+		}
 		// Optionally start metrics updater.
 		if cmmetrics.Enabled() {
 			go t.metrics()
@@ -409,19 +485,87 @@ func (t *fullService) RegisterHaltHook(hook func(context.Context, int64, epochti
 	t.mux.RegisterHaltHook(hook)
 }
 
+func (t *fullService) ValidatorSetToGenesis(ctx context.Context, blockHeight int64) (*consensusAPI.ValidatorSetGenesis, error) {
+	blk, err := t.GetTendermintBlock(ctx, blockHeight)
+	if err != nil {
+		t.Logger.Error("failed to get tendermint block",
+			"err", err,
+			"block_height", blockHeight,
+		)
+		return nil, err
+	}
+	if blk == nil {
+		return nil, consensusAPI.ErrNoCommittedBlocks
+	}
+	blockHeight = blk.Header.Height
+
+	genesisDoc, err := t.GetGenesisDocument(ctx)
+	if err != nil {
+		t.Logger.Error("failed getting genesis document",
+			"err", err,
+		)
+		return nil, err
+	}
+
+	schedulerGenesis, err := t.scheduler.StateToGenesis(ctx, blockHeight)
+	if err != nil {
+		t.Logger.Error("scheduler StateToGenesis failure",
+			"err", err,
+			"block_height", blockHeight,
+		)
+		return nil, err
+	}
+
+	return &consensusAPI.ValidatorSetGenesis{
+		Height:    blockHeight,
+		Scheduler: *schedulerGenesis,
+		Consensus: genesisDoc.Consensus,
+	}, nil
+}
+
 func (t *fullService) SubmitTx(ctx context.Context, tx *transaction.SignedTransaction) error {
+	_, err := t.submitTx(ctx, tx)
+	return err
+}
+
+func (t *fullService) SubmitTxForChain(ctx context.Context, tx *transaction.SignedTransaction, chainID string) error {
+	if chainID != t.genesis.ChainID {
+		return fmt.Errorf("%w: expected chain %s, node is part of chain %s",
+			consensusAPI.ErrWrongChain,
+			chainID,
+			t.genesis.ChainID,
+		)
+	}
+	return t.SubmitTx(ctx, tx)
+}
+
+func (t *fullService) CheckTx(ctx context.Context, tx *transaction.SignedTransaction) error {
+	if err := t.ensureStarted(ctx); err != nil {
+		return err
+	}
+
+	return t.mux.CheckTxDryRun(cbor.Marshal(tx))
+}
+
+func (t *fullService) SubmitTxAndWaitForState(ctx context.Context, tx *transaction.SignedTransaction) (int64, error) {
+	return t.submitTx(ctx, tx)
+}
+
+// submitTx submits the transaction and waits for it to be included in a block,
+// returning the height of the including block.
+func (t *fullService) submitTx(ctx context.Context, tx *transaction.SignedTransaction) (int64, error) {
 	// Subscribe to the transaction being included in a block.
 	data := cbor.Marshal(tx)
 	query := tmtypes.EventQueryTxFor(data)
 	subID := t.newSubscriberID()
 	txSub, err := t.subscribe(subID, query)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if ptrSub, ok := txSub.(*tendermintPubsubBuffer).tmSubscription.(*tmpubsub.Subscription); ok && ptrSub == nil {
 		t.Logger.Debug("broadcastTx: service has shut down. Cancel our context to recover")
 		<-ctx.Done()
-		return ctx.Err()
+		return 0, ctx.Err()
 	}
 
 	defer t.unsubscribe(subID, query) // nolint: errcheck
@@ -431,33 +575,34 @@ func (t *fullService) SubmitTx(ctx context.Context, tx *transaction.SignedTransa
 
 	recheckCh, recheckSub, err := t.mux.WatchInvalidatedTx(txHash)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer recheckSub.Close()
 
 	// First try to broadcast.
 	if err := t.broadcastTxRaw(data); err != nil {
-		return err
+		return 0, err
 	}
 
 	// Wait for the transaction to be included in a block.
 	select {
 	case v := <-recheckCh:
-		return v
+		return 0, v
 	case v := <-txSub.Out():
-		if result := v.Data().(tmtypes.EventDataTx).Result; !result.IsOK() {
+		txResult := v.Data().(tmtypes.EventDataTx).TxResult
+		if result := txResult.Result; !result.IsOK() {
 			err := errors.FromCode(result.GetCodespace(), result.GetCode())
 			if err == nil {
 				// Fallback to an ordinary error.
 				err = fmt.Errorf(result.GetLog())
 			}
-			return err
+			return 0, err
 		}
-		return nil
+		return txResult.Height, nil
 	case <-txSub.Cancelled():
-		return context.Canceled
+		return 0, context.Canceled
 	case <-ctx.Done():
-		return ctx.Err()
+		return 0, ctx.Err()
 	}
 }
 
@@ -494,8 +639,73 @@ func (t *fullService) broadcastTxRaw(data []byte) error {
 	return nil
 }
 
+// acquireLocalQuery reserves a slot for a query using the in-process Tendermint
+// client. Returns consensusAPI.ErrBusy in case the concurrency limit has been
+// reached, otherwise the caller must call the returned release function once
+// the query is done.
+func (t *fullService) acquireLocalQuery() (func(), error) {
+	if t.localQuerySem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case t.localQuerySem <- struct{}{}:
+		return func() { <-t.localQuerySem }, nil
+	default:
+		return nil, consensusAPI.ErrBusy
+	}
+}
+
 func (t *fullService) newSubscriberID() string {
-	return fmt.Sprintf("%s/subscriber-%d", tmSubscriberID, atomic.AddUint64(&t.nextSubscriberID, 1))
+	subID := fmt.Sprintf("%s/subscriber-%d", tmSubscriberID, atomic.AddUint64(&t.nextSubscriberID, 1))
+
+	t.subscribersLock.Lock()
+	defer t.subscribersLock.Unlock()
+	t.subscribers[subID] = nil
+
+	return subID
+}
+
+// trackSubscription records the subscription of a subscriber issued by newSubscriberID. It
+// returns false in case the subscriber is no longer tracked (e.g., because all subscriptions
+// have been cancelled in the meantime).
+func (t *fullService) trackSubscription(subscriber string, sub *tendermintPubsubBuffer) bool {
+	t.subscribersLock.Lock()
+	defer t.subscribersLock.Unlock()
+
+	if _, ok := t.subscribers[subscriber]; !ok {
+		return false
+	}
+	t.subscribers[subscriber] = sub
+	return true
+}
+
+func (t *fullService) forgetSubscriber(subscriber string) {
+	t.subscribersLock.Lock()
+	defer t.subscribersLock.Unlock()
+
+	delete(t.subscribers, subscriber)
+}
+
+func (t *fullService) CancelAllSubscriptions() {
+	t.subscribersLock.Lock()
+	subscribers := t.subscribers
+	t.subscribers = make(map[string]*tendermintPubsubBuffer)
+	t.subscribersLock.Unlock()
+
+	for subID, sub := range subscribers {
+		if t.started() {
+			if err := t.node.EventBus().UnsubscribeAll(t.ctx, subID); err != nil {
+				t.Logger.Debug("failed to unsubscribe subscriber",
+					"err", err,
+					"subscriber", subID,
+				)
+			}
+		}
+		if sub != nil {
+			sub.Close()
+		}
+	}
 }
 
 func (t *fullService) SubmitEvidence(ctx context.Context, evidence *consensusAPI.Evidence) error {
@@ -509,13 +719,66 @@ func (t *fullService) SubmitEvidence(ctx context.Context, evidence *consensusAPI
 		return fmt.Errorf("tendermint: malformed evidence while converting: %w", err)
 	}
 
-	if _, err := t.client.BroadcastEvidence(ctx, ev); err != nil {
+	release, err := t.acquireLocalQuery()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err = t.checkEvidenceAge(ctx, ev); err != nil {
+		return err
+	}
+
+	if _, err = t.client.BroadcastEvidence(ctx, ev); err != nil {
 		return fmt.Errorf("tendermint: broadcast evidence failed: %w", err)
 	}
 
 	return nil
 }
 
+// checkEvidenceAge checks the evidence against the evidence age limits in the current consensus
+// parameters so that evidence which would be rejected as expired is not broadcast.
+//
+// Note: The caller must hold a local query slot.
+func (t *fullService) checkEvidenceAge(ctx context.Context, ev tmtypes.Evidence) error {
+	latestHeight := t.mux.State().BlockHeight()
+	if latestHeight == 0 {
+		return consensusAPI.ErrNoCommittedBlocks
+	}
+
+	params, err := t.client.ConsensusParams(ctx, &latestHeight)
+	if err != nil {
+		return fmt.Errorf("tendermint: consensus params query failed: %w", err)
+	}
+	evParams := params.ConsensusParams.Evidence
+
+	// Same as in Tendermint, evidence is only expired when it exceeds both limits.
+	ageNumBlocks := latestHeight - ev.Height()
+	if ageNumBlocks <= evParams.MaxAgeNumBlocks {
+		return nil
+	}
+
+	blockStore := t.node.BlockStore()
+	latestMeta := blockStore.LoadBlockMeta(latestHeight)
+	evMeta := blockStore.LoadBlockMeta(ev.Height())
+	if latestMeta == nil || evMeta == nil {
+		// Unable to determine the evidence age locally, leave it to Tendermint.
+		return nil
+	}
+	ageDuration := latestMeta.Header.Time.Sub(evMeta.Header.Time)
+	if ageDuration <= evParams.MaxAgeDuration {
+		return nil
+	}
+
+	return fmt.Errorf("%w: evidence height %d, latest height %d, max age %d blocks/%s",
+		consensusAPI.ErrEvidenceTooOld,
+		ev.Height(),
+		latestHeight,
+		evParams.MaxAgeNumBlocks,
+		evParams.MaxAgeDuration,
+	)
+}
+
 func (t *fullService) EstimateGas(ctx context.Context, req *consensusAPI.EstimateGasRequest) (transaction.Gas, error) {
 	return t.mux.EstimateGas(req.Signer, req.Transaction)
 }
@@ -528,14 +791,23 @@ func (t *fullService) subscribe(subscriber string, query tmpubsub.Query) (tmtype
 	subFn := func() (tmtypes.Subscription, error) {
 		sub, err := t.node.EventBus().SubscribeUnbuffered(t.ctx, subscriber, query)
 		if err != nil {
+			t.forgetSubscriber(subscriber)
 			return nil, err
 		}
 		// Oh yes, this can actually return a nil subscription even though the
 		// error was also nil if the node is just shutting down.
 		if sub == (*tmpubsub.Subscription)(nil) {
+			t.forgetSubscriber(subscriber)
 			return nil, context.Canceled
 		}
-		return newTendermintPubsubBuffer(sub), nil
+		buf := newTendermintPubsubBuffer(sub)
+		if !t.trackSubscription(subscriber, buf) {
+			// All subscriptions have been cancelled while we were subscribing.
+			_ = t.node.EventBus().Unsubscribe(t.ctx, subscriber, query)
+			buf.Close()
+			return nil, context.Canceled
+		}
+		return buf, nil
 	}
 
 	if t.started() {
@@ -551,16 +823,20 @@ func (t *fullService) subscribe(subscriber string, query tmpubsub.Query) (tmtype
 
 	// XXX/yawning: As far as I can tell just blocking here is safe as
 	// ever single consumer of the API subscribes from a go routine.
-	select {
-	case <-t.startedCh:
-	case <-t.ctx.Done():
-		return nil, t.ctx.Err()
+	metrics.SubscribersWaitingStart.With(labelTendermint).Inc()
+	err := t.ensureStartedTimeout(context.Background(), t.subscribeWaitTimeout)
+	metrics.SubscribersWaitingStart.With(labelTendermint).Dec()
+	if err != nil {
+		t.forgetSubscriber(subscriber)
+		return nil, err
 	}
 
 	return subFn()
 }
 
 func (t *fullService) unsubscribe(subscriber string, query tmpubsub.Query) error {
+	t.forgetSubscriber(subscriber)
+
 	if t.started() {
 		return t.node.EventBus().Unsubscribe(t.ctx, subscriber, query)
 	}
@@ -569,9 +845,19 @@ func (t *fullService) unsubscribe(subscriber string, query tmpubsub.Query) error
 }
 
 func (t *fullService) RegisterApplication(app api.Application) error {
+	if t.disabledApps[app.Name()] {
+		t.Logger.Warn("not registering disabled ABCI application (UNSAFE)",
+			"app", app.Name(),
+		)
+		return nil
+	}
 	return t.mux.Register(app)
 }
 
+func (t *fullService) RegisteredApplications() []string {
+	return t.mux.RegisteredApplications()
+}
+
 func (t *fullService) SetTransactionAuthHandler(handler api.TransactionAuthHandler) error {
 	return t.mux.SetTransactionAuthHandler(handler)
 }
@@ -584,6 +870,10 @@ func (t *fullService) SubmissionManager() consensusAPI.SubmissionManager {
 	return t.submissionMgr
 }
 
+func (t *fullService) SetSubmissionGasPrice(price uint64) error {
+	return t.submissionMgr.SetGasPrice(price)
+}
+
 func (t *fullService) EpochTime() epochtimeAPI.Backend {
 	return t.epochtime
 }
@@ -619,6 +909,13 @@ func (t *fullService) GetEpoch(ctx context.Context, height int64) (epochtimeAPI.
 	return t.epochtime.GetEpoch(ctx, height)
 }
 
+func (t *fullService) GetEpochProgress(ctx context.Context, height int64) (*epochtimeAPI.EpochProgress, error) {
+	if t.epochtime == nil {
+		return nil, consensusAPI.ErrUnsupported
+	}
+	return t.epochtime.GetEpochProgress(ctx, height)
+}
+
 func (t *fullService) WaitEpoch(ctx context.Context, epoch epochtimeAPI.EpochTime) error {
 	if t.epochtime == nil {
 		return consensusAPI.ErrUnsupported
@@ -642,6 +939,13 @@ func (t *fullService) WaitEpoch(ctx context.Context, epoch epochtimeAPI.EpochTim
 	}
 }
 
+func (t *fullService) WaitEpochBlock(ctx context.Context, epoch epochtimeAPI.EpochTime) (int64, error) {
+	if err := t.WaitEpoch(ctx, epoch); err != nil {
+		return 0, err
+	}
+	return t.epochtime.GetEpochBlock(ctx, epoch)
+}
+
 func (t *fullService) GetBlock(ctx context.Context, height int64) (*consensusAPI.Block, error) {
 	blk, err := t.GetTendermintBlock(ctx, height)
 	if err != nil {
@@ -658,6 +962,27 @@ func (t *fullService) GetSignerNonce(ctx context.Context, req *consensusAPI.GetS
 	return t.mux.TransactionAuthHandler().GetSignerNonce(ctx, req)
 }
 
+func (t *fullService) GetSignerNonceAtHeight(
+	ctx context.Context,
+	req *consensusAPI.GetSignerNonceRequest,
+	height int64,
+) (uint64, error) {
+	if height == consensusAPI.HeightLatest {
+		return 0, fmt.Errorf("tendermint: GetSignerNonceAtHeight requires an explicit height")
+	}
+	if _, err := t.IsHeightAvailable(ctx, height); err != nil {
+		if errors.Is(err, api.ErrHeightPruned) {
+			// Report pruned heights using an error that is preserved across gRPC.
+			return 0, consensusAPI.ErrVersionNotFound
+		}
+		return 0, err
+	}
+
+	rq := *req
+	rq.Height = height
+	return t.mux.TransactionAuthHandler().GetSignerNonce(ctx, &rq)
+}
+
 func (t *fullService) GetTransactions(ctx context.Context, height int64) ([][]byte, error) {
 	blk, err := t.GetTendermintBlock(ctx, height)
 	if err != nil {
@@ -674,8 +999,35 @@ func (t *fullService) GetTransactions(ctx context.Context, height int64) ([][]by
 	return txs, nil
 }
 
+func (t *fullService) GetTransactionsWithHashes(ctx context.Context, height int64) ([]consensusAPI.TxWithHash, error) {
+	blk, err := t.GetTendermintBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if blk == nil {
+		return nil, consensusAPI.ErrNoCommittedBlocks
+	}
+
+	txs := make([]consensusAPI.TxWithHash, 0, len(blk.Data.Txs))
+	for _, v := range blk.Data.Txs {
+		txs = append(txs, consensusAPI.TxWithHash{
+			Tx:   v[:],
+			Hash: hash.NewFromBytes(v),
+		})
+	}
+	return txs, nil
+}
+
 func (t *fullService) GetTransactionsWithResults(ctx context.Context, height int64) (*consensusAPI.TransactionsWithResults, error) {
+	return t.GetTransactionsWithResultsFiltered(ctx, &consensusAPI.GetTransactionsWithResultsRequest{Height: height})
+}
+
+func (t *fullService) GetTransactionsWithResultsFiltered(
+	ctx context.Context,
+	req *consensusAPI.GetTransactionsWithResultsRequest,
+) (*consensusAPI.TransactionsWithResults, error) {
 	var txsWithResults consensusAPI.TransactionsWithResults
+	height := req.Height
 
 	blk, err := t.GetTendermintBlock(ctx, height)
 	if err != nil {
@@ -729,10 +1081,11 @@ func (t *fullService) GetTransactionsWithResults(ctx context.Context, height int
 		}
 
 		// Transaction roothash events.
-		roothashEvents, err := tmroothash.EventsFromTendermint(
+		roothashEvents, err := tmroothash.EventsFromTendermintForRuntime(
 			txsWithResults.Transactions[txIdx],
 			blk.Height,
 			rs.Events,
+			req.RuntimeID,
 		)
 		if err != nil {
 			return nil, err
@@ -745,6 +1098,245 @@ func (t *fullService) GetTransactionsWithResults(ctx context.Context, height int
 	return &txsWithResults, nil
 }
 
+func (t *fullService) GetBlockSignatures(ctx context.Context, height int64) ([]consensusAPI.CommitSig, error) {
+	if err := t.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+
+	if height == consensusAPI.HeightLatest {
+		height = t.mux.State().BlockHeight()
+		if height == 0 {
+			return nil, consensusAPI.ErrNoCommittedBlocks
+		}
+	}
+
+	release, err := t.acquireLocalQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// This returns the LastCommit of the block at height+1 in case it exists and the seen commit
+	// otherwise (e.g., for the latest height).
+	commit, err := t.client.Commit(ctx, &height)
+	if err != nil {
+		return nil, fmt.Errorf("%w: tendermint: commit query failed: %s", consensusAPI.ErrVersionNotFound, err.Error())
+	}
+	if commit.Commit == nil {
+		return nil, fmt.Errorf("tendermint: commit is nil")
+	}
+
+	// Don't use the client as that imposes stupid pagination. Access the state database directly.
+	vals, err := t.stateStore.LoadValidators(height)
+	if err != nil {
+		return nil, consensusAPI.ErrVersionNotFound
+	}
+
+	sigs := make([]consensusAPI.CommitSig, 0, len(commit.Commit.Signatures))
+	for idx, sig := range commit.Commit.Signatures {
+		// Absent votes don't include the validator address, but signatures are ordered in the
+		// same way as the validator set.
+		if idx >= vals.Size() {
+			return nil, fmt.Errorf("tendermint: commit has more signatures than validators")
+		}
+		_, val := vals.GetByIndex(int32(idx))
+		if !sig.Absent() && !bytes.Equal(sig.ValidatorAddress, val.Address) {
+			return nil, fmt.Errorf("tendermint: commit signature %d has unexpected validator address", idx)
+		}
+		tmPk, ok := val.PubKey.(tmed.PubKey)
+		if !ok {
+			return nil, fmt.Errorf("tendermint: unsupported validator public key type: %T", val.PubKey)
+		}
+
+		sigs = append(sigs, consensusAPI.CommitSig{
+			PublicKey: crypto.PublicKeyFromTendermint(&tmPk),
+			Absent:    sig.Absent(),
+			Nil:       sig.BlockIDFlag == tmtypes.BlockIDFlagNil,
+		})
+	}
+	return sigs, nil
+}
+
+// decodeEvents decodes all staking, registry and roothash events from the given Tendermint
+// events emitted at the given height.
+func decodeEvents(tx []byte, height int64, tmEvents []tmabcitypes.Event) ([]*results.Event, error) {
+	var events []*results.Event
+
+	stakingEvents, err := tmstaking.EventsFromTendermint(tx, height, tmEvents)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range stakingEvents {
+		events = append(events, &results.Event{Staking: e})
+	}
+
+	registryEvents, _, err := tmregistry.EventsFromTendermint(tx, height, tmEvents)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range registryEvents {
+		events = append(events, &results.Event{Registry: e})
+	}
+
+	roothashEvents, err := tmroothash.EventsFromTendermint(tx, height, tmEvents)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range roothashEvents {
+		events = append(events, &results.Event{RootHash: e})
+	}
+	return events, nil
+}
+
+func (t *fullService) GetEventsAtHeight(ctx context.Context, height int64) ([]*results.Event, error) {
+	res, err := t.GetDecodedBlockResults(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	events := res.BeginBlockEvents
+	for _, txResult := range res.TxResults {
+		events = append(events, txResult.Result.Events...)
+	}
+	events = append(events, res.EndBlockEvents...)
+	return events, nil
+}
+
+func (t *fullService) GetDecodedBlockResults(ctx context.Context, height int64) (*consensusAPI.DecodedBlockResults, error) {
+	res, err := t.GetBlockResults(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBlockResults(res, nil)
+}
+
+func (t *fullService) GetBlockWithResults(ctx context.Context, height int64) (*consensusAPI.BlockWithResults, error) {
+	// Fetch the block first and use its height for the results, so that both are for the same
+	// height even when the latest height is requested.
+	blk, err := t.GetTendermintBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if blk == nil {
+		return nil, consensusAPI.ErrNoCommittedBlocks
+	}
+
+	res, err := t.GetBlockResults(ctx, blk.Height)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.TxsResults) != len(blk.Data.Txs) {
+		return nil, fmt.Errorf("tendermint: number of transactions %d and results %d do not match", len(blk.Data.Txs), len(res.TxsResults))
+	}
+	decoded, err := decodeBlockResults(res, blk.Data.Txs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &consensusAPI.BlockWithResults{
+		Block:   api.NewBlock(blk),
+		Results: decoded,
+	}, nil
+}
+
+// decodeBlockResults decodes the given block results. In case the block transactions are given,
+// they are used to set the TxHash field of transaction events.
+func decodeBlockResults(res *tmrpctypes.ResultBlockResults, txs tmtypes.Txs) (*consensusAPI.DecodedBlockResults, error) {
+	var err error
+	decoded := consensusAPI.DecodedBlockResults{
+		Height: res.Height,
+	}
+	if decoded.BeginBlockEvents, err = decodeEvents(nil, res.Height, res.BeginBlockEvents); err != nil {
+		return nil, err
+	}
+	for txIdx, rs := range res.TxsResults {
+		var tx []byte
+		if txs != nil {
+			tx = txs[txIdx]
+		}
+		events, err := decodeEvents(tx, res.Height, rs.Events)
+		if err != nil {
+			return nil, err
+		}
+		decoded.TxResults = append(decoded.TxResults, &consensusAPI.DecodedTxResult{
+			Result: &results.Result{
+				Error: results.Error{
+					Module:  rs.GetCodespace(),
+					Code:    rs.GetCode(),
+					Message: rs.GetLog(),
+				},
+				Events: events,
+			},
+			GasWanted: transaction.Gas(rs.GetGasWanted()),
+			GasUsed:   transaction.Gas(rs.GetGasUsed()),
+		})
+	}
+	if decoded.EndBlockEvents, err = decodeEvents(nil, res.Height, res.EndBlockEvents); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+func (t *fullService) StreamTransactionsWithResults(
+	ctx context.Context,
+	startHeight int64,
+	endHeight int64,
+) (<-chan *consensusAPI.HeightTransactions, error) {
+	if err := t.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+	if startHeight <= 0 || endHeight < startHeight {
+		return nil, fmt.Errorf("tendermint: invalid height range [%d, %d]", startHeight, endHeight)
+	}
+
+	ch := make(chan *consensusAPI.HeightTransactions)
+	go func() {
+		defer close(ch)
+
+		send := func(txs *consensusAPI.HeightTransactions) bool {
+			select {
+			case ch <- txs:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for height := startHeight; height <= endHeight; height++ {
+			if ctx.Err() != nil {
+				return
+			}
+
+			// Make sure the height has not been pruned.
+			if height < t.node.BlockStore().Base() {
+				_ = send(&consensusAPI.HeightTransactions{
+					Height: height,
+					Err:    fmt.Errorf("tendermint: height %d has been pruned: %w", height, consensusAPI.ErrVersionNotFound),
+				})
+				return
+			}
+
+			txs, err := t.GetTransactionsWithResults(ctx, height)
+			if err != nil {
+				_ = send(&consensusAPI.HeightTransactions{
+					Height: height,
+					Err:    err,
+				})
+				return
+			}
+
+			if !send(&consensusAPI.HeightTransactions{
+				TransactionsWithResults: *txs,
+				Height:                  height,
+			}) {
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
 func (t *fullService) GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error) {
 	mempoolTxs := t.node.Mempool().ReapMaxTxs(-1)
 	txs := make([][]byte, 0, len(mempoolTxs))
@@ -754,6 +1346,83 @@ func (t *fullService) GetUnconfirmedTransactions(ctx context.Context) ([][]byte,
 	return txs, nil
 }
 
+func (t *fullService) GetUnconfirmedTransactionsWithMeta(ctx context.Context) ([]*consensusAPI.MempoolTx, error) {
+	mempoolTxs := t.node.Mempool().ReapMaxTxs(-1)
+	now := time.Now()
+	txs := make([]*consensusAPI.MempoolTx, 0, len(mempoolTxs))
+	for _, v := range mempoolTxs {
+		tx := &consensusAPI.MempoolTx{
+			Hash: hash.NewFromBytes(v),
+			Size: uint64(len(v)),
+		}
+		if meta, ok := t.mux.GetMempoolTxMeta(tx.Hash); ok {
+			tx.TimeInMempool = now.Sub(meta.FirstSeen)
+			tx.GasWanted = meta.GasWanted
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func (t *fullService) GetBlockIntervalStats(ctx context.Context, window int) (*consensusAPI.BlockIntervalStats, error) {
+	if err := t.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+
+	if window > t.blockIntervalStatsMaxWindow {
+		window = t.blockIntervalStatsMaxWindow
+	}
+	if window < 2 {
+		return nil, fmt.Errorf("tendermint: block interval window must include at least 2 blocks")
+	}
+
+	latestHeight := t.mux.State().BlockHeight()
+	if latestHeight == 0 {
+		return nil, consensusAPI.ErrNoCommittedBlocks
+	}
+	blockStore := t.node.BlockStore()
+	fromHeight := latestHeight - int64(window) + 1
+	if base := blockStore.Base(); fromHeight < base {
+		fromHeight = base
+	}
+	if fromHeight >= latestHeight {
+		return nil, fmt.Errorf("tendermint: not enough blocks available for block interval statistics")
+	}
+
+	stats := &consensusAPI.BlockIntervalStats{
+		FromHeight: fromHeight,
+		ToHeight:   latestHeight,
+	}
+	var (
+		prevTime time.Time
+		total    time.Duration
+	)
+	for height := fromHeight; height <= latestHeight; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		meta := blockStore.LoadBlockMeta(height)
+		if meta == nil {
+			return nil, fmt.Errorf("tendermint: block meta at height %d not available", height)
+		}
+		if height > fromHeight {
+			interval := meta.Header.Time.Sub(prevTime)
+			if height == fromHeight+1 || interval < stats.Min {
+				stats.Min = interval
+			}
+			if interval > stats.Max {
+				stats.Max = interval
+			}
+			total += interval
+		}
+		prevTime = meta.Header.Time
+	}
+	stats.Mean = total / time.Duration(latestHeight-fromHeight)
+
+	return stats, nil
+}
+
 func (t *fullService) GetStatus(ctx context.Context) (*consensusAPI.Status, error) {
 	status := &consensusAPI.Status{
 		ConsensusVersion: version.ConsensusProtocol.String(),
@@ -762,17 +1431,17 @@ func (t *fullService) GetStatus(ctx context.Context) (*consensusAPI.Status, erro
 	}
 
 	status.GenesisHeight = t.genesis.Height
+	status.GenesisHash = t.genesisHash[:]
+	status.PruneStrategy = t.pruneCfg.Strategy.String()
+	if t.pruneCfg.Strategy != abci.PruneNone {
+		status.PruneNumKept = t.pruneCfg.NumKept
+	}
+	if t.mux != nil {
+		status.CheckTxDisabled = t.mux.CheckTxDisabled()
+	}
 	if t.started() {
 		// Only attempt to fetch blocks in case the consensus service has started as otherwise
 		// requests will block.
-		genBlk, err := t.GetBlock(ctx, t.genesis.Height)
-		switch err {
-		case nil:
-			status.GenesisHash = genBlk.Hash
-		default:
-			// We may not be able to fetch the genesis block in case it has been pruned.
-		}
-
 		lastRetainedHeight, err := t.GetLastRetainedVersion(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get last retained height: %w", err)
@@ -808,26 +1477,157 @@ func (t *fullService) GetStatus(ctx context.Context) (*consensusAPI.Status, erro
 	// List of consensus peers.
 	tmpeers := t.node.Switch().Peers().List()
 	peers := make([]string, 0, len(tmpeers))
+	peersInfo := make([]*consensusAPI.PeerInfo, 0, len(tmpeers))
+	now := time.Now()
 	for _, tmpeer := range tmpeers {
 		p := string(tmpeer.ID()) + "@" + tmpeer.RemoteAddr().String()
 		peers = append(peers, p)
+
+		// Idle is the time since any data was last received from the peer.
+		idle := tmpeer.Status().RecvMonitor.Idle
+		peersInfo = append(peersInfo, &consensusAPI.PeerInfo{
+			Address:      p,
+			LastReceived: now.Add(-idle),
+			Stale:        idle > t.peerStaleThreshold,
+		})
 	}
 	status.NodePeers = peers
+	status.NodePeersInfo = peersInfo
+
+	// P2P addresses as configured in the Tendermint node.
+	p2pCfg := t.node.Config().P2P
+	status.P2PListenAddress = p2pCfg.ListenAddress
+	status.P2PExternalAddress = p2pCfg.ExternalAddress
 
 	// Check if the local node is in the validator set for the latest (uncommitted) block.
-	valSetHeight := status.LatestHeight + 1
-	if valSetHeight < status.GenesisHeight {
-		valSetHeight = status.GenesisHeight
+	isValidator, err := t.isValidatorAt(status.LatestHeight + 1)
+	if err != nil {
+		return nil, err
+	}
+	status.IsValidator = isValidator
+	status.ValidatorCheckFailed = atomic.LoadUint32(&t.validatorCheckFailed) != 0
+
+	return status, nil
+}
+
+// isValidatorAt checks whether the local node is in the validator set at the
+// given height.
+func (t *fullService) isValidatorAt(height int64) (bool, error) {
+	if height < t.genesis.Height {
+		height = t.genesis.Height
 	}
-	vals, err := t.stateStore.LoadValidators(valSetHeight)
+	vals, err := t.stateStore.LoadValidators(height)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load validator set: %w", err)
+		return false, fmt.Errorf("failed to load validator set: %w", err)
 	}
 	consensusPk := t.identity.ConsensusSigner.Public()
 	consensusAddr := []byte(crypto.PublicKeyToTendermint(&consensusPk).Address())
-	status.IsValidator = vals.HasAddress(consensusAddr)
+	return vals.HasAddress(consensusAddr), nil
+}
 
-	return status, nil
+// requireValidatorWorker checks whether the local node is in the validator set on each epoch
+// transition, starting after the first full epoch following the initial sync.
+func (t *fullService) requireValidatorWorker() {
+	select {
+	case <-t.node.Quit():
+		return
+	case <-t.syncedCh:
+	}
+
+	ch, sub := t.epochtime.WatchEpochs()
+	defer sub.Close()
+
+	t.checkValidatorOnEpochs(t.node.Quit(), ch, func() (bool, int64, error) {
+		height := t.mux.State().BlockHeight()
+		isValidator, err := t.isValidatorAt(height + 1)
+		return isValidator, height, err
+	})
+}
+
+// checkValidatorOnEpochs runs the validator check on each epoch received from epochCh until
+// quitCh is closed. The isValidator function reports whether the local node is in the validator
+// set at the latest height.
+func (t *fullService) checkValidatorOnEpochs(
+	quitCh <-chan struct{},
+	epochCh <-chan epochtimeAPI.EpochTime,
+	isValidator func() (bool, int64, error),
+) {
+	var firstEpoch *epochtimeAPI.EpochTime
+	for {
+		var epoch epochtimeAPI.EpochTime
+		select {
+		case <-quitCh:
+			return
+		case epoch = <-epochCh:
+		}
+
+		// The first epoch received may already be in progress, wait for the following one to
+		// fully elapse before checking.
+		if firstEpoch == nil {
+			firstEpoch = &epoch
+		}
+		if epoch < *firstEpoch+2 {
+			continue
+		}
+
+		ok, height, err := isValidator()
+		if err != nil {
+			t.Logger.Error("failed to check whether the node is a validator",
+				"err", err,
+				"height", height,
+			)
+			continue
+		}
+
+		if ok {
+			if atomic.SwapUint32(&t.validatorCheckFailed, 0) != 0 {
+				t.Logger.Info("node is part of the validator set again",
+					"epoch", epoch,
+				)
+			}
+			continue
+		}
+
+		atomic.StoreUint32(&t.validatorCheckFailed, 1)
+		t.Logger.Error("NODE IS NOT PART OF THE VALIDATOR SET, BUT IS REQUIRED TO BE A VALIDATOR",
+			"epoch", epoch,
+			"height", height,
+		)
+	}
+}
+
+func (t *fullService) WaitForValidator(ctx context.Context) error {
+	if err := t.ensureStarted(ctx); err != nil {
+		return err
+	}
+
+	ch, sub := t.WatchTendermintBlocks()
+	defer sub.Close()
+
+	// Check if the local node is already in the validator set for the latest
+	// (uncommitted) block.
+	isValidator, err := t.isValidatorAt(t.mux.State().BlockHeight() + 1)
+	if err != nil {
+		return err
+	}
+
+	for !isValidator {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case blk, ok := <-ch:
+			if !ok {
+				return context.Canceled
+			}
+			if isValidator, err = t.isValidatorAt(blk.Height + 1); err != nil {
+				return err
+			}
+		}
+	}
+
+	t.Logger.Info("local node is a validator")
+
+	return nil
 }
 
 func (t *fullService) WatchBlocks(ctx context.Context) (<-chan *consensusAPI.Block, pubsub.ClosableSubscription, error) {
@@ -853,15 +1653,57 @@ func (t *fullService) WatchBlocks(ctx context.Context) (<-chan *consensusAPI.Blo
 	return mapCh, sub, nil
 }
 
+func (t *fullService) WatchUnconfirmedTransactions(ctx context.Context) (<-chan [][]byte, pubsub.ClosableSubscription, error) {
+	ch, sub := t.mux.WatchMempoolTxs()
+	mapCh := make(chan [][]byte)
+	go func() {
+		defer close(mapCh)
+
+		for {
+			select {
+			case tx, ok := <-ch:
+				if !ok {
+					return
+				}
+
+				select {
+				case mapCh <- [][]byte{tx}:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return mapCh, sub, nil
+}
+
 func (t *fullService) ensureStarted(ctx context.Context) error {
+	return t.ensureStartedTimeout(ctx, t.startupWaitTimeout)
+}
+
+// ensureStartedTimeout waits for the Tendermint service to start for at most
+// the given duration. In case the duration is zero, it waits indefinitely.
+func (t *fullService) ensureStartedTimeout(ctx context.Context, timeout time.Duration) error {
 	// Make sure that the Tendermint service has started so that we
 	// have the client interface available.
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
 	select {
 	case <-t.startedCh:
 	case <-t.ctx.Done():
 		return t.ctx.Err()
 	case <-ctx.Done():
 		return ctx.Err()
+	case <-timeoutCh:
+		return consensusAPI.ErrNotStarted
 	}
 
 	return nil
@@ -981,6 +1823,58 @@ func (t *fullService) GetLastRetainedVersion(ctx context.Context) (int64, error)
 	return t.mux.State().LastRetainedVersion()
 }
 
+func (t *fullService) IsHeightAvailable(ctx context.Context, height int64) (bool, error) {
+	latestHeight := t.mux.State().BlockHeight()
+	if height == consensusAPI.HeightLatest {
+		if latestHeight == 0 {
+			return false, consensusAPI.ErrNoCommittedBlocks
+		}
+		return true, nil
+	}
+	if height > latestHeight {
+		return false, fmt.Errorf("%w: height %d (latest height: %d)", api.ErrHeightNotYetAvailable, height, latestHeight)
+	}
+
+	lastRetainedHeight, err := t.GetLastRetainedVersion(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get last retained height: %w", err)
+	}
+	// Some pruning configurations return 0 instead of a valid block height. Clamp those to the genesis height.
+	if lastRetainedHeight < t.genesis.Height {
+		lastRetainedHeight = t.genesis.Height
+	}
+	if height < lastRetainedHeight {
+		return false, fmt.Errorf("%w: height %d (last retained height: %d)", api.ErrHeightPruned, height, lastRetainedHeight)
+	}
+
+	return true, nil
+}
+
+func (t *fullService) CreateCheckpoint(ctx context.Context, height int64) error {
+	if height == consensusAPI.HeightLatest {
+		height = t.mux.State().BlockHeight()
+	}
+	if _, err := t.IsHeightAvailable(ctx, height); err != nil {
+		return err
+	}
+
+	return t.mux.CreateCheckpoint(ctx, height)
+}
+
+func (t *fullService) GetApplicationState(ctx context.Context, app string, height int64) (writelog.WriteLog, error) {
+	if height == consensusAPI.HeightLatest {
+		height = t.mux.State().BlockHeight()
+	}
+	if _, err := t.IsHeightAvailable(ctx, height); err != nil {
+		if errors.Is(err, api.ErrHeightPruned) {
+			return nil, consensusAPI.ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	return t.mux.GetApplicationState(ctx, app, height, maxApplicationStateSize)
+}
+
 func (t *fullService) GetTendermintBlock(ctx context.Context, height int64) (*tmtypes.Block, error) {
 	if err := t.ensureStarted(ctx); err != nil {
 		return nil, err
@@ -999,6 +1893,12 @@ func (t *fullService) GetTendermintBlock(ctx context.Context, height int64) (*tm
 	} else {
 		tmHeight = height
 	}
+	release, err := t.acquireLocalQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	result, err := t.client.Block(ctx, &tmHeight)
 	if err != nil {
 		return nil, fmt.Errorf("tendermint: block query failed: %w", err)
@@ -1024,6 +1924,12 @@ func (t *fullService) GetBlockResults(ctx context.Context, height int64) (*tmrpc
 		tmHeight = height
 	}
 
+	release, err := t.acquireLocalQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	result, err := t.client.BlockResults(ctx, &tmHeight)
 	if err != nil {
 		return nil, fmt.Errorf("tendermint: block results query failed: %w", err)
@@ -1032,6 +1938,17 @@ func (t *fullService) GetBlockResults(ctx context.Context, height int64) (*tmrpc
 	return result, nil
 }
 
+func (t *fullService) GetLatestBlockResults(ctx context.Context) (*tmrpctypes.ResultBlockResults, error) {
+	// Use our mux notion of latest height, see GetTendermintBlock.
+	height := t.mux.State().BlockHeight()
+	if height == 0 {
+		// No committed blocks yet.
+		return nil, consensusAPI.ErrNoCommittedBlocks
+	}
+
+	return t.GetBlockResults(ctx, height)
+}
+
 func (t *fullService) WatchTendermintBlocks() (<-chan *tmtypes.Block, *pubsub.Subscription) {
 	typedCh := make(chan *tmtypes.Block)
 	sub := t.blockNotifier.Subscribe()
@@ -1086,6 +2003,7 @@ func (t *fullService) lazyInit() error {
 		return err
 	}
 	pruneCfg.NumKept = viper.GetUint64(CfgABCIPruneNumKept)
+	t.pruneCfg = pruneCfg
 
 	appConfig := &abci.ApplicationConfig{
 		DataDir:                   filepath.Join(t.dataDir, tmcommon.StateDir),
@@ -1118,11 +2036,19 @@ func (t *fullService) lazyInit() error {
 	tenderConfig.SetRoot(tendermintDataDir)
 	timeoutCommit := t.genesis.Consensus.Parameters.TimeoutCommit
 	emptyBlockInterval := t.genesis.Consensus.Parameters.EmptyBlockInterval
+	if override := viper.GetDuration(CfgDebugConsensusEmptyBlockInterval); override > 0 && cmflags.DebugDontBlameOasis() {
+		t.Logger.Warn("overriding empty block interval from genesis, this must match across all validators",
+			"genesis_empty_block_interval", emptyBlockInterval,
+			"empty_block_interval", override,
+		)
+		emptyBlockInterval = override
+	}
 	tenderConfig.Consensus.TimeoutCommit = timeoutCommit
 	tenderConfig.Consensus.SkipTimeoutCommit = t.genesis.Consensus.Parameters.SkipTimeoutCommit
 	tenderConfig.Consensus.CreateEmptyBlocks = true
 	tenderConfig.Consensus.CreateEmptyBlocksInterval = emptyBlockInterval
 	tenderConfig.Consensus.DebugUnsafeReplayRecoverCorruptedWAL = viper.GetBool(CfgDebugUnsafeReplayRecoverCorruptedWAL) && cmflags.DebugDontBlameOasis()
+	tenderConfig.Mempool.Recheck = viper.GetBool(CfgMempoolRecheck)
 	tenderConfig.Instrumentation.Prometheus = true
 	tenderConfig.Instrumentation.PrometheusListenAddr = ""
 	tenderConfig.TxIndex.Indexer = "null"
@@ -1160,12 +2086,10 @@ func (t *fullService) lazyInit() error {
 		// Append upstream addresses to persistent, private and unconditional peers.
 		tenderConfig.P2P.PersistentPeers += "," + strings.ToLower(strings.Join(sentryUpstreamAddrs, ","))
 
+		// NOTE: Addresses have already been validated in New.
 		var sentryUpstreamIDs []string
 		for _, addr := range sentryUpstreamAddrs {
-			parts := strings.Split(addr, "@")
-			if len(parts) != 2 {
-				return fmt.Errorf("malformed sentry upstream address: %s", addr)
-			}
+			parts := strings.SplitN(addr, "@", 2)
 			sentryUpstreamIDs = append(sentryUpstreamIDs, parts[0])
 		}
 
@@ -1252,11 +2176,36 @@ func (t *fullService) lazyInit() error {
 
 			cfg.ConsensusNodes = append(cfg.ConsensusNodes, addr)
 		}
-		if stateProvider, err = newStateProvider(t.ctx, cfg); err != nil {
+		maxAttempts := viper.GetUint(CfgConsensusStateSyncMaxAttempts)
+		for attempt := uint(1); ; attempt++ {
+			if stateProvider, err = newStateProvider(t.ctx, cfg); err == nil {
+				break
+			}
 			t.Logger.Error("failed to create state sync state provider",
 				"err", err,
+				"attempt", attempt,
+				"max_attempts", maxAttempts,
 			)
-			return fmt.Errorf("failed to create state sync state provider: %w", err)
+			if maxAttempts == 0 {
+				return fmt.Errorf("failed to create state sync state provider: %w", err)
+			}
+			if attempt >= maxAttempts {
+				// Fall back to fast sync from genesis so that a transient snapshot outage does
+				// not prevent the node from starting.
+				t.Logger.Error("STATE SYNC FAILED, FALLING BACK TO FAST SYNC FROM GENESIS",
+					"err", err,
+					"attempts", attempt,
+				)
+				tenderConfig.StateSync.Enable = false
+				stateProvider = nil
+				break
+			}
+
+			select {
+			case <-time.After(stateSyncRetryInterval):
+			case <-t.ctx.Done():
+				return t.ctx.Err()
+			}
 		}
 	}
 
@@ -1405,6 +2354,9 @@ func (t *fullService) metrics() {
 		case blk = <-ch:
 		}
 
+		metrics.BlockTxs.With(labelTendermint).Observe(float64(len(blk.Data.Txs)))
+		metrics.BlockBytes.With(labelTendermint).Observe(float64(blk.Size()))
+
 		// Was block proposed by our node.
 		if bytes.Equal(myAddr, blk.ProposerAddress) {
 			metrics.ProposedBlocks.With(labelTendermint).Inc()
@@ -1427,6 +2379,39 @@ func (t *fullService) metrics() {
 	}
 }
 
+// validateSentryUpstreamAddress validates a sentry upstream address of the form
+// ID@host:port.
+func validateSentryUpstreamAddress(addr string) error {
+	parts := strings.Split(addr, "@")
+	if len(parts) != 2 {
+		return fmt.Errorf("expected ID@host:port")
+	}
+	id, err := hex.DecodeString(parts[0])
+	if err != nil || len(id) != tmcrypto.AddressSize {
+		return fmt.Errorf("malformed node ID")
+	}
+	_, port, err := net.SplitHostPort(parts[1])
+	if err != nil {
+		return fmt.Errorf("malformed host:port: %w", err)
+	}
+	if _, err = strconv.ParseUint(port, 10, 16); err != nil {
+		return fmt.Errorf("malformed port: %w", err)
+	}
+	return nil
+}
+
+// validateSentryUpstreamAddresses validates all configured sentry upstream
+// addresses, reporting all malformed addresses at once.
+func validateSentryUpstreamAddresses(addrs []string) error {
+	var errs error
+	for _, addr := range addrs {
+		if err := validateSentryUpstreamAddress(addr); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("malformed sentry upstream address '%s': %w", addr, err))
+		}
+	}
+	return errs
+}
+
 // New creates a new Tendermint consensus backend.
 func New(
 	ctx context.Context,
@@ -1435,6 +2420,11 @@ func New(
 	upgrader upgradeAPI.Backend,
 	genesisProvider genesisAPI.Provider,
 ) (consensusAPI.Backend, error) {
+	// Validate the configuration early, before constructing anything.
+	if err := validateSentryUpstreamAddresses(viper.GetStringSlice(CfgSentryUpstreamAddress)); err != nil {
+		return nil, fmt.Errorf("tendermint: %w", err)
+	}
+
 	// Retrieve the genesis document early so that it is possible to
 	// use it while initializing other things.
 	genesisDoc, err := genesisProvider.GetGenesisDocument()
@@ -1457,12 +2447,28 @@ func New(
 		blockNotifier:         pubsub.NewBroker(false),
 		identity:              identity,
 		genesis:               genesisDoc,
+		genesisHash:           genesisDoc.Hash(),
 		genesisProvider:       genesisProvider,
 		ctx:                   ctx,
 		dataDir:               dataDir,
 		startedCh:             make(chan struct{}),
+		subscribers:           make(map[string]*tendermintPubsubBuffer),
 		syncedCh:              make(chan struct{}),
 	}
+	if disabledApps := viper.GetStringSlice(CfgDebugDisableApps); len(disabledApps) > 0 && cmflags.DebugDontBlameOasis() {
+		t.disabledApps = make(map[string]bool)
+		for _, name := range disabledApps {
+			t.disabledApps[name] = true
+		}
+	}
+	t.blockIntervalStatsMaxWindow = viper.GetInt(CfgBlockIntervalStatsMaxWindow)
+	t.peerStaleThreshold = viper.GetDuration(CfgP2PPeerStaleThreshold)
+	t.startupWaitTimeout = viper.GetDuration(CfgStartupWaitTimeout)
+	t.subscribeWaitTimeout = viper.GetDuration(CfgSubscribeWaitTimeout)
+	t.requireValidator = viper.GetBool(CfgRequireValidator)
+	if maxConcurrency := viper.GetUint(CfgLocalQueryMaxConcurrency); maxConcurrency > 0 {
+		t.localQuerySem = make(chan struct{}, maxConcurrency)
+	}
 
 	t.Logger.Info("starting a full consensus node")
 
@@ -1486,22 +2492,35 @@ func init() {
 	Flags.StringSlice(CfgP2PUnconditionalPeerIDs, []string{}, "Tendermint unconditional peer IDs")
 	Flags.Bool(CfgP2PDisablePeerExchange, false, "Disable Tendermint's peer-exchange reactor")
 	Flags.Duration(CfgP2PPersistenPeersMaxDialPeriod, 0*time.Second, "Tendermint max timeout when redialing a persistent peer (default: unlimited)")
+	Flags.Duration(CfgP2PPeerStaleThreshold, 1*time.Minute, "duration without received data after which a peer is reported as stale")
 	Flags.Uint64(CfgMinGasPrice, 0, "minimum gas price")
 	Flags.Bool(CfgDebugDisableCheckTx, false, "do not perform CheckTx on incoming transactions (UNSAFE)")
+	Flags.StringSlice(CfgDebugDisableApps, []string{}, "do not register the given ABCI applications, producing an invalid chain (UNSAFE)")
 	Flags.Bool(CfgDebugUnsafeReplayRecoverCorruptedWAL, false, "Enable automatic recovery from corrupted WAL during replay (UNSAFE).")
+	Flags.Duration(CfgDebugConsensusEmptyBlockInterval, 0, "override the genesis empty block interval, must match across all validators (0 = use genesis)")
 
 	Flags.Bool(CfgSupplementarySanityEnabled, false, "enable supplementary sanity checks (slows down consensus)")
 	Flags.Uint64(CfgSupplementarySanityInterval, 10, "supplementary sanity check interval (in blocks)")
 
+	Flags.Uint(CfgLocalQueryMaxConcurrency, 0, "maximum number of concurrent local consensus queries (0 = unlimited)")
+	Flags.Int(CfgBlockIntervalStatsMaxWindow, 1000, "maximum number of blocks considered for block interval statistics")
+	Flags.Duration(CfgStartupWaitTimeout, 0, "maximum time API methods wait for consensus to start (0 = unlimited)")
+	Flags.Duration(CfgSubscribeWaitTimeout, 0, "maximum time subscriptions wait for consensus to start (0 = unlimited)")
+	Flags.Bool(CfgMempoolRecheck, true, "recheck mempool transactions after each block")
+	Flags.Bool(CfgRequireValidator, false, "report an error if the node is not a validator after the first full epoch")
+
 	// State sync.
 	Flags.Bool(CfgConsensusStateSyncEnabled, false, "enable state sync")
 	Flags.StringSlice(CfgConsensusStateSyncConsensusNode, []string{}, "state sync: consensus node to use for syncing the light client")
 	Flags.Duration(CfgConsensusStateSyncTrustPeriod, 24*time.Hour, "state sync: light client trust period")
 	Flags.Uint64(CfgConsensusStateSyncTrustHeight, 0, "state sync: light client trusted height")
 	Flags.String(CfgConsensusStateSyncTrustHash, "", "state sync: light client trusted consensus header hash")
+	Flags.Uint(CfgConsensusStateSyncMaxAttempts, 0, "state sync: number of attempts before falling back to fast sync (0 = no fallback)")
 
 	_ = Flags.MarkHidden(CfgDebugDisableCheckTx)
+	_ = Flags.MarkHidden(CfgDebugDisableApps)
 	_ = Flags.MarkHidden(CfgDebugUnsafeReplayRecoverCorruptedWAL)
+	_ = Flags.MarkHidden(CfgDebugConsensusEmptyBlockInterval)
 
 	_ = Flags.MarkHidden(CfgSupplementarySanityEnabled)
 	_ = Flags.MarkHidden(CfgSupplementarySanityInterval)
@@ -0,0 +1,90 @@
+package full
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	epochtimeAPI "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+)
+
+const recvTimeout = 5 * time.Second
+
+type validatorCheckResult struct {
+	isValidator bool
+	err         error
+}
+
+func TestCheckValidatorOnEpochs(t *testing.T) {
+	require := require.New(t)
+
+	var svc fullService
+	svc.Logger = logging.GetLogger("consensus/tendermint/full/test")
+
+	quitCh := make(chan struct{})
+	epochCh := make(chan epochtimeAPI.EpochTime)
+	resultCh := make(chan validatorCheckResult)
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		svc.checkValidatorOnEpochs(quitCh, epochCh, func() (bool, int64, error) {
+			select {
+			case res := <-resultCh:
+				return res.isValidator, 42, res.err
+			case <-quitCh:
+				return true, 42, nil
+			}
+		})
+	}()
+
+	// Sending an epoch only completes once the previous one has been fully processed.
+	sendEpoch := func(epoch epochtimeAPI.EpochTime) {
+		select {
+		case epochCh <- epoch:
+		case <-time.After(recvTimeout):
+			t.Fatalf("epoch %d was not received", epoch)
+		}
+	}
+	sendResult := func(res validatorCheckResult) {
+		select {
+		case resultCh <- res:
+		case <-time.After(recvTimeout):
+			t.Fatalf("validator check was not performed")
+		}
+	}
+	checkFailed := func() bool {
+		return atomic.LoadUint32(&svc.validatorCheckFailed) != 0
+	}
+
+	// No checks should be performed until the first full epoch has elapsed.
+	sendEpoch(10)
+	sendEpoch(11)
+	require.False(checkFailed(), "check should not fail before the first full epoch")
+
+	// Not being a validator should fail the check.
+	sendEpoch(12)
+	sendResult(validatorCheckResult{isValidator: false})
+	sendEpoch(13)
+	require.True(checkFailed(), "check should fail when not a validator")
+
+	// Errors should not change the check status.
+	sendResult(validatorCheckResult{err: fmt.Errorf("failed to load validator set")})
+	sendEpoch(14)
+	require.True(checkFailed(), "check status should not change on errors")
+
+	// Becoming a validator again should clear the failure.
+	sendResult(validatorCheckResult{isValidator: true})
+	sendEpoch(15)
+	require.False(checkFailed(), "check should pass when a validator")
+
+	close(quitCh)
+	select {
+	case <-doneCh:
+	case <-time.After(recvTimeout):
+		t.Fatalf("validator check should terminate")
+	}
+}
@@ -18,6 +18,12 @@ func (t *fullService) GetLightBlock(ctx context.Context, height int64) (*consens
 		return nil, err
 	}
 
+	release, err := t.acquireLocalQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	commit, err := t.client.Commit(ctx, &height)
 	if err != nil {
 		return nil, fmt.Errorf("%w: tendermint: header query failed: %s", consensusAPI.ErrVersionNotFound, err.Error())
@@ -58,6 +64,12 @@ func (t *fullService) GetParameters(ctx context.Context, height int64) (*consens
 		return nil, err
 	}
 
+	release, err := t.acquireLocalQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	params, err := t.client.ConsensusParams(ctx, &height)
 	if err != nil {
 		return nil, fmt.Errorf("%w: tendermint: consensus params query failed: %s", consensusAPI.ErrVersionNotFound, err.Error())
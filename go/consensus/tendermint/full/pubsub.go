@@ -1,6 +1,8 @@
 package full
 
 import (
+	"sync"
+
 	"github.com/eapache/channels"
 
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
@@ -17,6 +19,9 @@ type tendermintPubsubBuffer struct {
 	tmSubscription tmtypes.Subscription
 	outCh          chan tmpubsub.Message
 	cancelCh       chan struct{}
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
 }
 
 func newTendermintPubsubBuffer(tmSubscription tmtypes.Subscription) *tendermintPubsubBuffer {
@@ -25,6 +30,7 @@ func newTendermintPubsubBuffer(tmSubscription tmtypes.Subscription) *tendermintP
 		tmSubscription: tmSubscription,
 		outCh:          make(chan tmpubsub.Message),
 		cancelCh:       make(chan struct{}),
+		closeCh:        make(chan struct{}),
 	}
 
 	go ps.reader()
@@ -45,6 +51,14 @@ func (ps *tendermintPubsubBuffer) Err() error {
 	return ps.tmSubscription.Err()
 }
 
+// Close stops forwarding messages from the underlying subscription and marks
+// the wrapper as cancelled.
+func (ps *tendermintPubsubBuffer) Close() {
+	ps.closeOnce.Do(func() {
+		close(ps.closeCh)
+	})
+}
+
 func (ps *tendermintPubsubBuffer) reader() {
 	defer close(ps.cancelCh)
 	defer ps.messageBuffer.Close()
@@ -58,6 +72,8 @@ func (ps *tendermintPubsubBuffer) reader() {
 			ps.messageBuffer.In() <- &msg
 		case <-ps.tmSubscription.Cancelled():
 			return
+		case <-ps.closeCh:
+			return
 		}
 	}
 }
@@ -566,12 +566,29 @@ func (sc *serviceClient) processFinalizedEvent(
 	return nil
 }
 
-// EventsFromTendermint extracts staking events from tendermint events.
+// EventsFromTendermint extracts roothash events from tendermint events.
 func EventsFromTendermint(
 	tx tmtypes.Tx,
 	height int64,
 	tmEvents []tmabcitypes.Event,
 ) ([]*api.Event, error) {
+	return EventsFromTendermintForRuntime(tx, height, tmEvents, nil)
+}
+
+// EventsFromTendermintForRuntime extracts roothash events from tendermint events.
+//
+// If runtimeID is non-nil, only events for the given runtime are decoded and returned.
+func EventsFromTendermintForRuntime(
+	tx tmtypes.Tx,
+	height int64,
+	tmEvents []tmabcitypes.Event,
+	runtimeID *common.Namespace,
+) ([]*api.Event, error) {
+	var runtimeIDValue []byte
+	if runtimeID != nil {
+		runtimeIDValue = app.ValueRuntimeID(*runtimeID)
+	}
+
 	var txHash hash.Hash
 	switch tx {
 	case nil:
@@ -587,6 +604,10 @@ func EventsFromTendermint(
 		if tmEv.GetType() != app.EventType {
 			continue
 		}
+		// Ignore events that don't relate to the requested runtime, without decoding them.
+		if runtimeIDValue != nil && !eventHasRuntimeID(tmEv, runtimeIDValue) {
+			continue
+		}
 
 		for _, pair := range tmEv.GetAttributes() {
 			key := pair.GetKey()
@@ -633,6 +654,17 @@ func EventsFromTendermint(
 	return events, errs
 }
 
+// eventHasRuntimeID checks whether the given roothash event is tagged with the given runtime ID
+// attribute value.
+func eventHasRuntimeID(tmEv tmabcitypes.Event, runtimeIDValue []byte) bool {
+	for _, pair := range tmEv.GetAttributes() {
+		if bytes.Equal(pair.GetKey(), app.KeyRuntimeID) {
+			return bytes.Equal(pair.GetValue(), runtimeIDValue)
+		}
+	}
+	return false
+}
+
 // New constructs a new tendermint-based root hash backend.
 func New(
 	ctx context.Context,
@@ -25,6 +25,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	tmcommon "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/common"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/crypto"
@@ -59,6 +60,7 @@ type seedService struct {
 
 	doc *genesis.Document
 
+	p2pCfg    *config.P2PConfig
 	addr      *p2p.NetAddress
 	transport *p2p.MultiplexTransport
 	addrBook  pex.AddrBook
@@ -143,6 +145,8 @@ func (srv *seedService) GetStatus(ctx context.Context) (*consensus.Status, error
 		peers = append(peers, p)
 	}
 	status.NodePeers = peers
+	status.P2PListenAddress = srv.p2pCfg.ListenAddress
+	status.P2PExternalAddress = srv.p2pCfg.ExternalAddress
 
 	return status, nil
 }
@@ -178,6 +182,11 @@ func (srv *seedService) SubmitTx(ctx context.Context, tx *transaction.SignedTran
 	return consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) SubmitTxForChain(ctx context.Context, tx *transaction.SignedTransaction, chainID string) error {
+	return consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error) {
 	return nil, consensus.ErrUnsupported
@@ -193,11 +202,21 @@ func (srv *seedService) WaitEpoch(ctx context.Context, epoch epochtime.EpochTime
 	return consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) WaitEpochBlock(ctx context.Context, epoch epochtime.EpochTime) (int64, error) {
+	return 0, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetEpoch(ctx context.Context, height int64) (epochtime.EpochTime, error) {
 	return 0, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) GetEpochProgress(ctx context.Context, height int64) (*epochtime.EpochProgress, error) {
+	return nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetBlock(ctx context.Context, height int64) (*consensus.Block, error) {
 	return nil, consensus.ErrUnsupported
@@ -208,26 +227,102 @@ func (srv *seedService) GetTransactions(ctx context.Context, height int64) ([][]
 	return nil, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) GetTransactionsWithHashes(ctx context.Context, height int64) ([]consensus.TxWithHash, error) {
+	return nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetTransactionsWithResults(ctx context.Context, height int64) (*consensus.TransactionsWithResults, error) {
 	return nil, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) ValidatorSetToGenesis(ctx context.Context, height int64) (*consensus.ValidatorSetGenesis, error) {
+	return nil, consensus.ErrUnsupported
+}
+
+// Implements Backend.
+func (srv *seedService) CheckTx(ctx context.Context, tx *transaction.SignedTransaction) error {
+	return consensus.ErrUnsupported
+}
+
+// Implements Backend.
+func (srv *seedService) GetBlockSignatures(ctx context.Context, height int64) ([]consensus.CommitSig, error) {
+	return nil, consensus.ErrUnsupported
+}
+
+// Implements Backend.
+func (srv *seedService) GetEventsAtHeight(ctx context.Context, height int64) ([]*results.Event, error) {
+	return nil, consensus.ErrUnsupported
+}
+
+// Implements Backend.
+func (srv *seedService) GetDecodedBlockResults(ctx context.Context, height int64) (*consensus.DecodedBlockResults, error) {
+	return nil, consensus.ErrUnsupported
+}
+
+// Implements Backend.
+func (srv *seedService) GetBlockWithResults(ctx context.Context, height int64) (*consensus.BlockWithResults, error) {
+	return nil, consensus.ErrUnsupported
+}
+
+// Implements Backend.
+func (srv *seedService) GetTransactionsWithResultsFiltered(
+	ctx context.Context,
+	req *consensus.GetTransactionsWithResultsRequest,
+) (*consensus.TransactionsWithResults, error) {
+	return nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error) {
 	return nil, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) GetUnconfirmedTransactionsWithMeta(ctx context.Context) ([]*consensus.MempoolTx, error) {
+	return nil, consensus.ErrUnsupported
+}
+
+// Implements Backend.
+func (srv *seedService) GetBlockIntervalStats(ctx context.Context, window int) (*consensus.BlockIntervalStats, error) {
+	return nil, consensus.ErrUnsupported
+}
+
+// Implements Backend.
+func (srv *seedService) StreamTransactionsWithResults(
+	ctx context.Context,
+	startHeight int64,
+	endHeight int64,
+) (<-chan *consensus.HeightTransactions, error) {
+	return nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) WatchBlocks(ctx context.Context) (<-chan *consensus.Block, pubsub.ClosableSubscription, error) {
 	return nil, nil, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) WatchUnconfirmedTransactions(ctx context.Context) (<-chan [][]byte, pubsub.ClosableSubscription, error) {
+	return nil, nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetSignerNonce(ctx context.Context, req *consensus.GetSignerNonceRequest) (uint64, error) {
 	return 0, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) GetSignerNonceAtHeight(
+	ctx context.Context,
+	req *consensus.GetSignerNonceRequest,
+	height int64,
+) (uint64, error) {
+	return 0, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetLightBlock(ctx context.Context, height int64) (*consensus.LightBlock, error) {
 	return nil, consensus.ErrUnsupported
@@ -321,6 +416,7 @@ func New(dataDir string, identity *identity.Identity, genesisProvider genesis.Pr
 	p2pCfg := config.DefaultP2PConfig()
 	p2pCfg.SeedMode = true
 	p2pCfg.Seeds = strings.ToLower(strings.Join(viper.GetStringSlice(tmcommon.CfgP2PSeed), ","))
+	p2pCfg.ListenAddress = viper.GetString(tmcommon.CfgCoreListenAddress)
 	p2pCfg.ExternalAddress = viper.GetString(tmcommon.CfgCoreExternalAddress)
 	p2pCfg.MaxNumInboundPeers = viper.GetInt(tmcommon.CfgP2PMaxNumInboundPeers)
 	p2pCfg.MaxNumOutboundPeers = viper.GetInt(tmcommon.CfgP2PMaxNumOutboundPeers)
@@ -328,6 +424,7 @@ func New(dataDir string, identity *identity.Identity, genesisProvider genesis.Pr
 	p2pCfg.RecvRate = viper.GetInt64(tmcommon.CfgP2PRecvRate)
 	p2pCfg.AddrBookStrict = !(viper.GetBool(tmcommon.CfgDebugP2PAddrBookLenient) && cmflags.DebugDontBlameOasis())
 	p2pCfg.AllowDuplicateIP = viper.GetBool(tmcommon.CfgDebugP2PAllowDuplicateIP) && cmflags.DebugDontBlameOasis()
+	srv.p2pCfg = p2pCfg
 
 	nodeKey := &p2p.NodeKey{PrivKey: crypto.SignerToTendermint(identity.P2PSigner)}
 
@@ -344,7 +441,7 @@ func New(dataDir string, identity *identity.Identity, genesisProvider genesis.Pr
 			version.TendermintAppVersion,
 		),
 		DefaultNodeID: nodeKey.ID(),
-		ListenAddr:    viper.GetString(tmcommon.CfgCoreListenAddress),
+		ListenAddr:    p2pCfg.ListenAddress,
 		Network:       doc.ChainContext()[:types.MaxChainIDLen],
 		Version:       tmversion.TMCoreSemVer,
 		Channels:      []byte{pex.PexChannel},
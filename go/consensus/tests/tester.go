@@ -10,6 +10,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
@@ -45,8 +48,11 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	require.NoError(err, "GetStatus")
 	require.NotNil(status, "returned status should not be nil")
 	require.EqualValues(1, status.GenesisHeight, "genesis height must be 1")
+	genHash := genDoc.Hash()
+	require.EqualValues(genHash[:], status.GenesisHash, "genesis hash must match the genesis document")
 	// We run this test without pruning. All we check is that we retain everything as configured.
 	require.EqualValues(1, status.LastRetainedHeight, "last retained height must be 1")
+	require.NotEmpty(status.P2PListenAddress, "P2P listen address must be reported")
 
 	blk, err = backend.GetBlock(ctx, status.LatestHeight)
 	require.NoError(err, "GetBlock")
@@ -58,6 +64,14 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	txs, err := backend.GetTransactions(ctx, status.LatestHeight)
 	require.NoError(err, "GetTransactions")
 
+	txsWithHashes, err := backend.GetTransactionsWithHashes(ctx, status.LatestHeight)
+	require.NoError(err, "GetTransactionsWithHashes")
+	require.Len(txsWithHashes, len(txs), "GetTransactionsWithHashes length missmatch")
+	for i, tx := range txsWithHashes {
+		require.EqualValues(txs[i], tx.Tx, "GetTransactionsWithHashes transaction missmatch")
+		require.Equal(hash.NewFromBytes(txs[i]), tx.Hash, "GetTransactionsWithHashes hash missmatch")
+	}
+
 	txsWithResults, err := backend.GetTransactionsWithResults(ctx, status.LatestHeight)
 	require.NoError(err, "GetTransactionsWithResults")
 	require.Len(
@@ -71,6 +85,56 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 		"GetTransactionsWithResults.Results length missmatch",
 	)
 
+	// Filtering by a runtime should never affect the transactions themselves.
+	var unknownRuntime common.Namespace
+	txsWithFilteredResults, err := backend.GetTransactionsWithResultsFiltered(ctx, &consensus.GetTransactionsWithResultsRequest{
+		Height:    status.LatestHeight,
+		RuntimeID: &unknownRuntime,
+	})
+	require.NoError(err, "GetTransactionsWithResultsFiltered")
+	require.Len(
+		txsWithFilteredResults.Results,
+		len(txs),
+		"GetTransactionsWithResultsFiltered.Results length missmatch",
+	)
+	for _, res := range txsWithFilteredResults.Results {
+		for _, ev := range res.Events {
+			require.Nil(ev.RootHash, "GetTransactionsWithResultsFiltered should not return events for other runtimes")
+		}
+	}
+
+	sigs, err := backend.GetBlockSignatures(ctx, status.LatestHeight)
+	require.NoError(err, "GetBlockSignatures")
+	require.NotEmpty(sigs, "GetBlockSignatures should return signatures")
+
+	_, err = backend.GetEventsAtHeight(ctx, status.LatestHeight)
+	require.NoError(err, "GetEventsAtHeight")
+
+	decodedResults, err := backend.GetDecodedBlockResults(ctx, status.LatestHeight)
+	require.NoError(err, "GetDecodedBlockResults")
+	require.EqualValues(status.LatestHeight, decodedResults.Height, "GetDecodedBlockResults height")
+	require.Len(decodedResults.TxResults, len(txs), "GetDecodedBlockResults.TxResults length missmatch")
+
+	blkWithResults, err := backend.GetBlockWithResults(ctx, status.LatestHeight)
+	require.NoError(err, "GetBlockWithResults")
+	require.EqualValues(status.LatestHeight, blkWithResults.Block.Height, "GetBlockWithResults block height")
+	require.EqualValues(status.LatestHeight, blkWithResults.Results.Height, "GetBlockWithResults results height")
+	require.Len(blkWithResults.Results.TxResults, len(txs), "GetBlockWithResults.Results.TxResults length missmatch")
+
+	txsStream, err := backend.StreamTransactionsWithResults(ctx, status.LatestHeight, status.LatestHeight)
+	require.NoError(err, "StreamTransactionsWithResults")
+	heightTxs, ok := <-txsStream
+	require.True(ok, "StreamTransactionsWithResults should produce an item")
+	require.NoError(heightTxs.Err, "StreamTransactionsWithResults item error")
+	require.EqualValues(status.LatestHeight, heightTxs.Height, "StreamTransactionsWithResults height")
+	require.Len(
+		heightTxs.Transactions,
+		len(txs),
+		"StreamTransactionsWithResults.Transactions length missmatch",
+	)
+	_, ok = <-txsStream
+	require.False(ok, "StreamTransactionsWithResults should close the stream after the last height")
+
 	_, err = backend.GetUnconfirmedTransactions(ctx)
 	require.NoError(err, "GetUnconfirmedTransactions")
 
@@ -94,6 +158,20 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	require.NoError(err, "GetEpoch")
 	require.True(epoch > 0, "epoch height should be greater than zero")
 
+	epochHeight, err := backend.WaitEpochBlock(ctx, epoch)
+	require.NoError(err, "WaitEpochBlock")
+	epochAtHeight, err := backend.GetEpoch(ctx, epochHeight)
+	require.NoError(err, "GetEpoch")
+	require.Equal(epoch, epochAtHeight, "WaitEpochBlock should return a height within the epoch")
+
+	progress, err := backend.GetEpochProgress(ctx, epochHeight)
+	require.NoError(err, "GetEpochProgress")
+	require.Equal(epoch, progress.Epoch, "GetEpochProgress should return the epoch at the given height")
+	require.Equal(epochHeight, progress.StartHeight, "GetEpochProgress should return the epoch start height")
+	if !progress.ManualTransition {
+		require.True(progress.NextHeight > progress.StartHeight, "next epoch should start after the current one")
+	}
+
 	_, err = backend.EstimateGas(ctx, &consensus.EstimateGasRequest{
 		Signer:      memorySigner.NewTestSigner("estimate gas signer").Public(),
 		Transaction: transaction.NewTransaction(0, nil, staking.MethodTransfer, &staking.Transfer{}),
@@ -109,6 +187,14 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	require.NoError(err, "GetSignerNonce")
 	require.Equal(uint64(0), nonce, "Nonce should be zero")
 
+	nonce, err = backend.GetSignerNonceAtHeight(ctx, &consensus.GetSignerNonceRequest{
+		AccountAddress: staking.NewAddress(
+			signature.NewPublicKey("badfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+		),
+	}, blk.Height)
+	require.NoError(err, "GetSignerNonceAtHeight")
+	require.Equal(uint64(0), nonce, "Nonce should be zero")
+
 	// Light client API.
 	shdr, err := backend.GetLightBlock(ctx, blk.Height)
 	require.NoError(err, "GetLightBlock")
@@ -120,6 +206,10 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	require.Equal(params.Height, blk.Height, "returned parameters height should be correct")
 	require.NotNil(params.Meta, "returned parameters should contain metadata")
 
+	vsGenesis, err := backend.ValidatorSetToGenesis(ctx, blk.Height)
+	require.NoError(err, "ValidatorSetToGenesis")
+	require.Equal(blk.Height, vsGenesis.Height, "returned validator set genesis height should be correct")
+
 	err = backend.SubmitTxNoWait(ctx, &transaction.SignedTransaction{})
 	require.Error(err, "SubmitTxNoWait should fail with invalid transaction")
 
@@ -127,9 +217,31 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	testSigner := memorySigner.NewTestSigner(fmt.Sprintf("consensus tests tx signer: %T", backend))
 	testSigTx, err := transaction.Sign(testSigner, testTx)
 	require.NoError(err, "transaction.Sign")
+
+	err = backend.CheckTx(ctx, &transaction.SignedTransaction{})
+	require.Error(err, "CheckTx should fail with invalid transaction")
+	err = backend.CheckTx(ctx, testSigTx)
+	require.NoError(err, "CheckTx")
+
+	mempoolCh, mempoolSub, err := backend.WatchUnconfirmedTransactions(ctx)
+	require.NoError(err, "WatchUnconfirmedTransactions")
+	defer mempoolSub.Close()
+
 	err = backend.SubmitTxNoWait(ctx, testSigTx)
 	require.NoError(err, "SubmitTxNoWait")
 
+	select {
+	case mempoolTxs := <-mempoolCh:
+		require.Len(mempoolTxs, 1, "WatchUnconfirmedTransactions should produce the submitted transaction")
+		require.EqualValues(cbor.Marshal(testSigTx), mempoolTxs[0], "WatchUnconfirmedTransactions transaction missmatch")
+	case <-time.After(recvTimeout):
+		t.Fatalf("failed to receive unconfirmed transaction")
+	}
+
+	err = backend.SubmitTxForChain(ctx, testSigTx, genDoc.ChainID+"-wrong")
+	require.Error(err, "SubmitTxForChain should fail with wrong chain ID")
+	require.True(errors.Is(err, consensus.ErrWrongChain), "SubmitTxForChain should return ErrWrongChain on wrong chain ID")
+
 	err = backend.SubmitEvidence(ctx, &consensus.Evidence{})
 	require.Error(err, "SubmitEvidence should fail with invalid evidence")
 
@@ -4,6 +4,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
@@ -33,6 +34,12 @@ type Backend interface {
 	// epoch.
 	GetEpochBlock(context.Context, EpochTime) (int64, error)
 
+	// GetEpochProgress returns the progress of the epoch at the specified
+	// block height, including an estimate of when the next epoch transition
+	// will happen. Calling this method with height `0`, should return the
+	// progress of the epoch of latest known block.
+	GetEpochProgress(context.Context, int64) (*EpochProgress, error)
+
 	// WatchEpochs returns a channel that produces a stream of messages
 	// on epoch transitions.
 	//
@@ -50,6 +57,27 @@ type Backend interface {
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 }
 
+// EpochProgress is the progress of an epoch.
+type EpochProgress struct {
+	// Epoch is the epoch.
+	Epoch EpochTime `json:"epoch"`
+
+	// StartHeight is the block height at the start of the epoch.
+	StartHeight int64 `json:"start_height"`
+	// StartTime is the time of the block at the start of the epoch.
+	StartTime time.Time `json:"start_time"`
+
+	// NextHeight is the estimated block height of the next epoch transition.
+	NextHeight int64 `json:"next_height,omitempty"`
+	// NextTime is the estimated time of the next epoch transition.
+	NextTime time.Time `json:"next_time,omitempty"`
+
+	// ManualTransition is true iff epoch transitions are triggered manually
+	// instead of being based on the epoch interval, in which case the next
+	// transition can not be estimated.
+	ManualTransition bool `json:"manual_transition,omitempty"`
+}
+
 // SetableBackend is a Backend that supports setting the current epoch.
 type SetableBackend interface {
 	Backend
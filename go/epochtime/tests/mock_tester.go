@@ -67,6 +67,12 @@ func EpochtimeSetableImplementationTest(t *testing.T, backend api.Backend) {
 	e, err = timeSource.GetEpoch(context.Background(), consensus.HeightLatest)
 	require.NoError(err, "GetEpoch after set")
 	require.Equal(epoch, e, "GetEpoch after set, epoch")
+
+	progress, err := timeSource.GetEpochProgress(context.Background(), consensus.HeightLatest)
+	require.NoError(err, "GetEpochProgress after set")
+	require.Equal(epoch, progress.Epoch, "GetEpochProgress after set, epoch")
+	require.True(progress.ManualTransition, "GetEpochProgress after set, manual transition")
+	require.NotZero(progress.StartHeight, "GetEpochProgress after set, start height")
 }
 
 // MustAdvanceEpoch advances the epoch by the specified increment, and returns
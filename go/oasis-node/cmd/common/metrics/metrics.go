@@ -29,7 +29,9 @@ const (
 	CfgMetricsJobName  = "metrics.job_name"
 	CfgMetricsInterval = "metrics.interval"
 
-	MetricUp = "oasis_up"
+	MetricUp                      = "oasis_up"
+	MetricPeakRSSBytes            = "oasis_peak_rss_bytes"
+	MetricTimeToFirstBlockSeconds = "oasis_time_to_first_block_seconds"
 
 	MetricsJobTestRunner = "oasis-test-runner"
 
@@ -54,6 +56,20 @@ var (
 			Help: "Is oasis-test-runner active for specific scenario.",
 		},
 	)
+
+	PeakRSSGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: MetricPeakRSSBytes,
+			Help: "Peak total resident set size of all nodes during specific scenario.",
+		},
+	)
+
+	TimeToFirstBlockGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: MetricTimeToFirstBlockSeconds,
+			Help: "Time from network creation to the first committed consensus block during specific scenario (seconds).",
+		},
+	)
 )
 
 type stubService struct {
@@ -291,6 +307,10 @@ func GetDefaultPushLabels(ti *env.ScenarioInstanceInfo) map[string]string {
 		ti.ParameterSet.VisitAll(func(f *flag.Flag) {
 			labels[EscapeLabelCharacters(f.Name)] = f.Value.String()
 		})
+		// Add scenario-specific labels.
+		for k, v := range ti.MetricsLabels {
+			labels[EscapeLabelCharacters(k)] = v
+		}
 		// Override any labels passed to oasis-test-runner via CLI.
 		for k, v := range viper.GetStringMapString(CfgMetricsLabels) {
 			labels[k] = v
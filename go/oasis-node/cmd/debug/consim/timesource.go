@@ -33,6 +33,22 @@ func (b *simTimeSource) GetEpochBlock(ctx context.Context, epoch api.EpochTime)
 	return height, nil
 }
 
+func (b *simTimeSource) GetEpochProgress(ctx context.Context, height int64) (*api.EpochProgress, error) {
+	epoch, _ := b.GetEpoch(ctx, height)
+	startHeight, err := b.GetEpochBlock(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	// The simulation has no notion of block time, so only the heights
+	// are provided.
+	return &api.EpochProgress{
+		Epoch:       epoch,
+		StartHeight: startHeight,
+		NextHeight:  startHeight + b.interval,
+	}, nil
+}
+
 func (b *simTimeSource) WatchEpochs() (<-chan api.EpochTime, *pubsub.Subscription) {
 	panic("consim/epochtime: WatchEpochs not supported")
 }
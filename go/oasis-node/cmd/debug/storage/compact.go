@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+)
+
+const cfgCompactWorkers = "storage.compact.workers"
+
+var (
+	storageCompactCmd = &cobra.Command{
+		Use:   "compact runtime-id (hex)...",
+		Short: "compact the local storage database of the given runtimes (node must be stopped)",
+		Args: func(cmd *cobra.Command, args []string) error {
+			nrFn := cobra.MinimumNArgs(1)
+			if err := nrFn(cmd, args); err != nil {
+				return err
+			}
+			for _, arg := range args {
+				if err := ValidateRuntimeIDStr(arg); err != nil {
+					return fmt.Errorf("malformed runtime id '%v': %w", arg, err)
+				}
+			}
+
+			return nil
+		},
+		Run: doCompact,
+	}
+
+	storageCompactFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+// compactor is a storage backend that supports on-demand compaction.
+type compactor interface {
+	Compact(ctx context.Context, workers int) error
+}
+
+func doCompact(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, arg := range args {
+		var id common.Namespace
+		if err := id.UnmarshalHex(arg); err != nil {
+			logger.Error("failed to decode runtime id",
+				"err", err,
+			)
+			failed = true
+			continue
+		}
+
+		if err := compactRuntime(dataDir, id); err != nil {
+			failed = true
+			continue
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func compactRuntime(dataDir string, id common.Namespace) error {
+	dataDir = filepath.Join(dataDir, runtimeRegistry.RuntimesDir, id.String())
+
+	storageBackend, err := newDirectStorageBackend(dataDir, id)
+	if err != nil {
+		logger.Error("failed to construct storage backend",
+			"err", err,
+		)
+		return err
+	}
+	<-storageBackend.Initialized()
+	defer storageBackend.Cleanup()
+
+	c, ok := storageBackend.(compactor)
+	if !ok {
+		logger.Error("storage backend does not support compaction",
+			"runtime_id", id,
+		)
+		return fmt.Errorf("storage: backend does not support compaction")
+	}
+
+	logger.Info("compacting storage",
+		"runtime_id", id,
+	)
+
+	if err = c.Compact(context.Background(), viper.GetInt(cfgCompactWorkers)); err != nil {
+		logger.Error("failed to compact storage",
+			"err", err,
+			"runtime_id", id,
+		)
+		return err
+	}
+	return nil
+}
+
+func init() {
+	storageCompactFlags.Int(cfgCompactWorkers, 1, "number of compaction workers")
+	_ = viper.BindPFlags(storageCompactFlags)
+}
@@ -286,9 +286,14 @@ func Register(parentCmd *cobra.Command) {
 
 	storageBenchmarkCmd.Flags().AddFlagSet(storageBenchmarkFlags)
 
+	storageCompactCmd.Flags().AddFlagSet(storage.Flags)
+	storageCompactCmd.Flags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
+	storageCompactCmd.Flags().AddFlagSet(storageCompactFlags)
+
 	storageCmd.AddCommand(storageCheckRootsCmd)
 	storageCmd.AddCommand(storageForceFinalizeCmd)
 	storageCmd.AddCommand(storageExportCmd)
 	storageCmd.AddCommand(storageBenchmarkCmd)
+	storageCmd.AddCommand(storageCompactCmd)
 	parentCmd.AddCommand(storageCmd)
 }
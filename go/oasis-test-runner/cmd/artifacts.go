@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+)
+
+const cfgFailureArtifactsDir = "failure_artifacts.dir"
+
+// copyFailureArtifacts copies the artifacts needed to debug a failed scenario
+// instance (currently the genesis document used by the scenario network) into
+// the given scenario instance subdirectory of the failure artifacts directory.
+//
+// Returns the path to the copied genesis document or an empty string in case
+// there was nothing to copy.
+func copyFailureArtifacts(scInfo *env.ScenarioInstanceInfo, artifactsDir, name string) (string, error) {
+	if artifactsDir == "" || scInfo == nil || scInfo.GenesisFile == "" {
+		return "", nil
+	}
+
+	genesis, err := ioutil.ReadFile(scInfo.GenesisFile)
+	if err != nil {
+		return "", fmt.Errorf("root: failed to read genesis document: %w", err)
+	}
+
+	dir := filepath.Join(artifactsDir, name)
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("root: failed to create failure artifacts directory: %w", err)
+	}
+	dst := filepath.Join(dir, "genesis.json")
+	if err = ioutil.WriteFile(dst, genesis, 0o600); err != nil {
+		return "", fmt.Errorf("root: failed to copy genesis document: %w", err)
+	}
+	return dst, nil
+}
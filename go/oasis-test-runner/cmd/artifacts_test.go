@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+)
+
+func TestCopyFailureArtifacts(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-test-runner-artifacts")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	genesisPath := filepath.Join(dir, "genesis.json")
+	require.NoError(ioutil.WriteFile(genesisPath, []byte(`{"chain_id":"test"}`), 0o600))
+	scInfo := &env.ScenarioInstanceInfo{GenesisFile: genesisPath}
+	artifactsDir := filepath.Join(dir, "artifacts")
+
+	// Nothing should be copied when disabled or when there is no genesis document.
+	path, err := copyFailureArtifacts(scInfo, "", "e2e/test/0")
+	require.NoError(err, "copyFailureArtifacts")
+	require.Empty(path)
+	path, err = copyFailureArtifacts(&env.ScenarioInstanceInfo{}, artifactsDir, "e2e/test/0")
+	require.NoError(err, "copyFailureArtifacts")
+	require.Empty(path)
+
+	path, err = copyFailureArtifacts(scInfo, artifactsDir, "e2e/test/0")
+	require.NoError(err, "copyFailureArtifacts")
+	require.Equal(filepath.Join(artifactsDir, "e2e", "test", "0", "genesis.json"), path)
+	copied, err := ioutil.ReadFile(path)
+	require.NoError(err, "ReadFile")
+	require.Equal(`{"chain_id":"test"}`, string(copied))
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+const (
+	cfgBudget = "budget"
+
+	// budgetExceededExitCode is the exit code used in case the run has been
+	// aborted due to exceeding the wall-clock budget.
+	budgetExceededExitCode = 3
+)
+
+// errBudgetExceeded is the error returned when the run is aborted due to
+// exceeding the wall-clock budget.
+var errBudgetExceeded = errors.New("root: budget exceeded")
+
+// watchBudget cancels the given context once the wall-clock budget has been
+// exceeded. A zero budget disables the budget.
+//
+// Returns a channel that is closed once the budget has been exceeded.
+func watchBudget(ctx context.Context, cancel context.CancelFunc, budget time.Duration, logger *logging.Logger) <-chan struct{} {
+	exceededCh := make(chan struct{})
+	if budget <= 0 {
+		return exceededCh
+	}
+
+	go func() {
+		timer := time.NewTimer(budget)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			logger.Error("budget exceeded, aborting the in-flight scenario",
+				"budget", budget,
+			)
+			close(exceededCh)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return exceededCh
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+func TestWatchBudget(t *testing.T) {
+	require := require.New(t)
+	logger := logging.GetLogger("test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	exceededCh := watchBudget(ctx, cancel, 10*time.Millisecond, logger)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("context should be cancelled once the budget is exceeded")
+	}
+	require.True(isInterrupted(exceededCh), "budget should be reported as exceeded")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	exceededCh = watchBudget(ctx, cancel, 0, logger)
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(ctx.Err(), "zero budget should never cancel the context")
+	cancel()
+	require.False(isInterrupted(exceededCh), "zero budget should never be exceeded")
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+)
+
+const (
+	cfgCleanupPolicy = "cleanup.policy"
+
+	// cleanupPolicyAlways removes the data directories of all scenarios.
+	cleanupPolicyAlways = "always"
+	// cleanupPolicyOnSuccess removes the data directories of passing
+	// scenarios only.
+	cleanupPolicyOnSuccess = "on-success"
+	// cleanupPolicyNever retains the data directories of all scenarios.
+	cleanupPolicyNever = "never"
+)
+
+// cleanupPolicy decides which scenario data directories are retained after
+// the scenario completes.
+type cleanupPolicy struct {
+	logger *logging.Logger
+
+	policy   string
+	retained []string
+}
+
+// retains returns true iff the policy retains any data directories.
+func (p *cleanupPolicy) retains() bool {
+	return p.policy != cleanupPolicyAlways
+}
+
+// doCleanup tears down the child environment and removes its data directory
+// unless the policy mandates it to be retained.
+func (p *cleanupPolicy) doCleanup(childEnv *env.Env, passed bool) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("root: panic caught cleaning up scenario: %v, %s", r, debug.Stack())
+		}
+	}()
+
+	dir := childEnv.Dir()
+	childEnv.Cleanup()
+
+	switch {
+	case p.policy == cleanupPolicyOnSuccess && passed:
+		if err = os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("root: failed to remove scenario data directory: %w", err)
+		}
+	case p.retains():
+		p.retained = append(p.retained, dir)
+		if size, sizeErr := dirSize(dir); sizeErr == nil {
+			p.logger.Warn("retaining scenario data directory",
+				"path", dir,
+				"disk_usage_bytes", size,
+			)
+		}
+	}
+
+	return
+}
+
+// printRetained prints the paths of all retained data directories so they can
+// be inspected or garbage-collected manually.
+func (p *cleanupPolicy) printRetained() {
+	if p.policy != cleanupPolicyNever || len(p.retained) == 0 {
+		return
+	}
+
+	var total int64
+	fmt.Printf("Retained scenario data directories:\n")
+	for _, dir := range p.retained {
+		fmt.Printf("  * %s\n", dir)
+		if size, err := dirSize(dir); err == nil {
+			total += size
+		}
+	}
+	p.logger.Warn("scenario data directories retained",
+		"count", len(p.retained),
+		"disk_usage_bytes", total,
+	)
+}
+
+// newCleanupPolicy creates a new cleanup policy and configures the root
+// directory so that retained data directories survive the root cleanup.
+func newCleanupPolicy(policy string, rootDir *env.Dir, logger *logging.Logger) (*cleanupPolicy, error) {
+	switch policy {
+	case cleanupPolicyAlways:
+	case cleanupPolicyOnSuccess, cleanupPolicyNever:
+		rootDir.SetNoCleanup(true)
+	default:
+		return nil, fmt.Errorf("root: invalid cleanup policy: %s", policy)
+	}
+
+	return &cleanupPolicy{
+		logger: logger,
+		policy: policy,
+	}, nil
+}
+
+// dirSize returns the total size of all regular files under the given path.
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -38,7 +38,12 @@ provided, it compares the most recent batches in the corresponding branches.
 cmp compares all metrics provided by --metrics parameter and computes ratio
 source/target of metric values. If any of the metrics exceeds
 max_threshold.<metric>.{avg|max}_ratio or doesn't reach
-min_threshold.<metric>.{avg|max}_ratio, ba exits with error code 1.`,
+min_threshold.<metric>.{avg|max}_ratio, ba exits with error code 1.
+
+If --durations.baseline and --durations.candidate are provided, cmp instead
+compares the scenario durations recorded in the given run summaries (see
+--summary.file) and exits with error code 1 if the average duration of any
+scenario regressed by more than --durations.max_regression percent.`,
 		Run: runCmp,
 	}
 
@@ -63,6 +68,11 @@ min_threshold.<metric>.{avg|max}_ratio, ba exits with error code 1.`,
 			maxThresholdAvgRatio: 1.1,
 			maxThresholdMaxRatio: 1.1,
 		},
+		"peak_mem": {
+			getter:               getPeakRSS,
+			maxThresholdAvgRatio: 1.1,
+			maxThresholdMaxRatio: 1.1,
+		},
 		"cpu": {
 			getter:               getCPUTime,
 			maxThresholdAvgRatio: 1.05,
@@ -195,6 +205,23 @@ func getRssAnonMemory(
 	return getSummableMetric(ctx, metrics.MetricMemRssAnonBytes, scenario, bi)
 }
 
+// getPeakRSS returns average and maximum peak total resident set size of all
+// nodes sampled by the test runner for the given coarse benchmark instance
+// ("oasis_up" metric).
+func getPeakRSS(
+	ctx context.Context,
+	scenario string,
+	bi *model.SampleStream,
+) (float64, float64, error) {
+	labels := bi.Metric.Clone()
+	// The peak is reported by the "oasis-test-runner" worker only, so the job
+	// label is retained. We will average metric over all runs.
+	delete(labels, "run")
+
+	query := fmt.Sprintf("max by (run) (%s %s)", metrics.MetricPeakRSSBytes, labels.String())
+	return queryRunsAtEnd(ctx, query, scenario, bi)
+}
+
 // getCPUTime returns average and maximum sum of utime and stime for all workers
 // of the given coarse benchmark instance ("oasis_up" metric).
 func getCPUTime(
@@ -221,8 +248,6 @@ func getSummableMetric(
 	metric, scenario string,
 	bi *model.SampleStream,
 ) (float64, float64, error) {
-	instance := string(bi.Metric[metrics.MetricsLabelInstance])
-
 	labels := bi.Metric.Clone()
 	// Existing job denotes the "oasis-test-runner" worker only. We want to sum
 	// disk space across all workers.
@@ -230,9 +255,20 @@ func getSummableMetric(
 	// We will average metric over all runs.
 	delete(labels, "run")
 
-	v1api := prometheusAPI.NewAPI(client)
-
 	query := fmt.Sprintf("sum by (run) (%s %s)", metric, labels.String())
+	return queryRunsAtEnd(ctx, query, scenario, bi)
+}
+
+// queryRunsAtEnd evaluates the given per-run query at the end of the given
+// coarse benchmark instance and returns average and maximum values of all runs.
+func queryRunsAtEnd(
+	ctx context.Context,
+	query, scenario string,
+	bi *model.SampleStream,
+) (float64, float64, error) {
+	instance := string(bi.Metric[metrics.MetricsLabelInstance])
+
+	v1api := prometheusAPI.NewAPI(client)
 
 	// Fetch value at last recorded time.
 	// Some metrics might not be available anymore, if prometheus was shut down.
@@ -249,7 +285,7 @@ func getSummableMetric(
 	}
 	if len(result.(model.Vector)) == 0 {
 		return 0, 0, fmt.Errorf(
-			"getSummableMetric: no time series matched scenario: %s and instance: %s",
+			"queryRunsAtEnd: no time series matched scenario: %s and instance: %s",
 			scenario, instance,
 		)
 	}
@@ -526,6 +562,13 @@ func runCmp(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if viper.GetString(cfgDurationsBaseline) != "" || viper.GetString(cfgDurationsCandidate) != "" {
+		if !runDurationCmp() {
+			os.Exit(1)
+		}
+		return
+	}
+
 	var err error
 	client, err = api.NewClient(api.Config{
 		Address: viper.GetString(metrics.CfgMetricsAddr),
@@ -674,6 +717,10 @@ func Register(parentCmd *cobra.Command) {
 	)
 	cmpFlags.String(cfgMetricsNetDevice, "lo", "network device traffic to compare")
 
+	cmpFlags.String(cfgDurationsBaseline, "", "(optional) baseline run summary for comparing scenario durations")
+	cmpFlags.String(cfgDurationsCandidate, "", "(optional) candidate run summary for comparing scenario durations")
+	cmpFlags.Float64(cfgDurationsMaxRegression, 10, "maximum allowed scenario duration regression (percent)")
+
 	_ = viper.BindPFlags(cmpFlags)
 	cmpCmd.Flags().AddFlagSet(cmpFlags)
 
@@ -0,0 +1,151 @@
+package cmp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	cfgDurationsBaseline      = "durations.baseline"
+	cfgDurationsCandidate     = "durations.candidate"
+	cfgDurationsMaxRegression = "durations.max_regression"
+
+	// summaryResultPassed is the result of a passed scenario instance in the run summary.
+	summaryResultPassed = "passed"
+)
+
+// durationRegression is a scenario whose duration regressed between the baseline and the
+// candidate run.
+type durationRegression struct {
+	scenario  string
+	baseline  time.Duration
+	candidate time.Duration
+	percent   float64
+}
+
+// parseScenarioDurations parses a run summary as written by the test runner (see --summary.file)
+// and returns the average duration of all passed runs of each scenario.
+func parseScenarioDurations(r io.Reader) (map[string]time.Duration, error) {
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int64)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		// Skip the header, the totals line and anything else that is not a result row.
+		if len(fields) != 4 || fields[0] == "SCENARIO" {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		if fields[2] != summaryResultPassed {
+			continue
+		}
+
+		d, err := time.ParseDuration(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("malformed duration on line %d: %w", line, err)
+		}
+		totals[fields[0]] += d
+		counts[fields[0]]++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	durations := make(map[string]time.Duration, len(totals))
+	for sc, total := range totals {
+		durations[sc] = total / time.Duration(counts[sc])
+	}
+	return durations, nil
+}
+
+// readScenarioDurations reads the run summary from the given file and returns the average
+// duration of all passed runs of each scenario.
+func readScenarioDurations(path string) (map[string]time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	durations, err := parseScenarioDurations(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return durations, nil
+}
+
+// compareDurations returns all scenarios present in both runs whose candidate duration exceeds
+// the baseline duration by more than maxPercent percent, ordered by scenario name.
+func compareDurations(baseline, candidate map[string]time.Duration, maxPercent float64) []*durationRegression {
+	var regressions []*durationRegression
+	for sc, b := range baseline {
+		c, ok := candidate[sc]
+		if !ok || b <= 0 {
+			continue
+		}
+
+		percent := (float64(c)/float64(b) - 1) * 100
+		if percent > maxPercent {
+			regressions = append(regressions, &durationRegression{
+				scenario:  sc,
+				baseline:  b,
+				candidate: c,
+				percent:   percent,
+			})
+		}
+	}
+	sort.Slice(regressions, func(i, j int) bool { return regressions[i].scenario < regressions[j].scenario })
+
+	return regressions
+}
+
+// runDurationCmp compares scenario durations of the configured baseline and candidate run
+// summaries.
+//
+// Returns false if any scenario duration regressed beyond the configured percentage or if
+// the summaries cannot be read. Otherwise, returns true.
+func runDurationCmp() bool {
+	baseline, err := readScenarioDurations(viper.GetString(cfgDurationsBaseline))
+	if err != nil {
+		cmpLogger.Error("error reading baseline run summary", "err", err)
+		return false
+	}
+	candidate, err := readScenarioDurations(viper.GetString(cfgDurationsCandidate))
+	if err != nil {
+		cmpLogger.Error("error reading candidate run summary", "err", err)
+		return false
+	}
+
+	for sc := range candidate {
+		if _, ok := baseline[sc]; !ok {
+			cmpLogger.Info("scenario does not have a baseline duration to compare, ignoring",
+				"scenario", sc,
+			)
+		}
+	}
+
+	maxPercent := viper.GetFloat64(cfgDurationsMaxRegression)
+	regressions := compareDurations(baseline, candidate, maxPercent)
+	for _, r := range regressions {
+		cmpLogger.Error("scenario duration regressed beyond max allowed percentage",
+			"scenario", r.scenario,
+			"baseline", r.baseline,
+			"candidate", r.candidate,
+			"regression_percent", r.percent,
+			"max_allowed_regression_percent", maxPercent,
+		)
+	}
+
+	return len(regressions) == 0
+}
@@ -0,0 +1,51 @@
+package cmp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareDurations(t *testing.T) {
+	require := require.New(t)
+
+	baselineSummary := `SCENARIO     RUN ID  RESULT   DURATION
+e2e/slow     0       passed   1m0s
+e2e/fast     0       passed   10s
+e2e/fast     1       passed   20s
+e2e/broken   0       failed   5s
+Total: 3 passed, 1 failed, 0 skipped
+`
+	candidateSummary := `SCENARIO     RUN ID  RESULT   DURATION
+e2e/slow     0       passed   1m5s
+e2e/fast     0       passed   20s
+e2e/new      0       passed   1s
+e2e/broken   0       failed   1h0m0s
+Total: 3 passed, 1 failed, 0 skipped
+`
+
+	baseline, err := parseScenarioDurations(strings.NewReader(baselineSummary))
+	require.NoError(err, "parseScenarioDurations(baseline)")
+	require.Equal(map[string]time.Duration{
+		"e2e/slow": time.Minute,
+		"e2e/fast": 15 * time.Second,
+	}, baseline, "only passed runs should be averaged")
+
+	candidate, err := parseScenarioDurations(strings.NewReader(candidateSummary))
+	require.NoError(err, "parseScenarioDurations(candidate)")
+
+	regressions := compareDurations(baseline, candidate, 10)
+	require.Len(regressions, 1, "only e2e/fast should regress by more than 10%")
+	require.Equal("e2e/fast", regressions[0].scenario)
+	require.Equal(15*time.Second, regressions[0].baseline)
+	require.Equal(20*time.Second, regressions[0].candidate)
+	require.InDelta(33.33, regressions[0].percent, 0.01)
+
+	require.Len(compareDurations(baseline, candidate, 5), 2)
+	require.Empty(compareDurations(baseline, candidate, 50))
+
+	_, err = parseScenarioDurations(strings.NewReader("e2e/slow 0 passed forever\n"))
+	require.Error(err, "malformed durations should be rejected")
+}
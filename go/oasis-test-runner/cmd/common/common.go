@@ -15,6 +15,10 @@ const (
 	CfgScenarioRegex      = "scenario"
 	CfgScenarioRegexShort = "s"
 	CfgScenarioSkipRegex  = "skip"
+	// CfgScenarioExcludeRegex is the flag for regexp patterns matching names of
+	// scenarios to exclude. In contrast to CfgScenarioSkipRegex, exclusions are
+	// applied after scenarios are partitioned between parallel jobs.
+	CfgScenarioExcludeRegex = "exclude"
 
 	// ScenarioParamsMask is the form of parameters passed to specific scenario.
 	//
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+const (
+	cfgFailFast           = "fail_fast"
+	cfgFailFastSignalFile = "fail_fast.signal_file"
+
+	failFastPollInterval = 1 * time.Second
+)
+
+// failFastSignal is a failure signal shared between parallel jobs.
+//
+// The signal is a file on a shared filesystem. Any job whose scenario fails
+// creates the file, and all jobs watching the same file abort their in-flight
+// scenario as soon as the file appears.
+type failFastSignal struct {
+	logger *logging.Logger
+
+	path string
+}
+
+// raise creates the shared signal file, notifying all other jobs that a
+// scenario has failed.
+func (s *failFastSignal) raise(scenario string, jobIndex int) {
+	if s == nil || s.path == "" {
+		return
+	}
+
+	data := fmt.Sprintf("job %d: scenario %s failed\n", jobIndex, scenario)
+	if err := ioutil.WriteFile(s.path, []byte(data), 0o600); err != nil {
+		s.logger.Error("failed to raise fail-fast signal",
+			"err", err,
+			"path", s.path,
+		)
+	}
+}
+
+// isRaised returns true iff the shared signal file exists.
+func (s *failFastSignal) isRaised() bool {
+	if s == nil || s.path == "" {
+		return false
+	}
+
+	_, err := os.Stat(s.path)
+	return err == nil
+}
+
+// watch polls for the shared signal file and cancels the given context once
+// it appears.
+func (s *failFastSignal) watch(ctx context.Context, cancel context.CancelFunc) {
+	if s == nil || s.path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(failFastPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if s.isRaised() {
+			s.logger.Warn("fail-fast signal raised by another job, aborting",
+				"path", s.path,
+			)
+			cancel()
+			return
+		}
+	}
+}
+
+// newFailFastSignal creates a new fail-fast signal based on the configuration.
+//
+// Returns nil when fail-fast mode is disabled. Fails in case the signal file
+// already exists, as a file left over by an earlier run would otherwise abort
+// the run on the first poll.
+func newFailFastSignal(logger *logging.Logger) (*failFastSignal, error) {
+	if !viper.GetBool(cfgFailFast) {
+		return nil, nil
+	}
+
+	path := viper.GetString(cfgFailFastSignalFile)
+	if path == "" {
+		return nil, fmt.Errorf("%s flag is required with %s", cfgFailFastSignalFile, cfgFailFast)
+	}
+	switch _, err := os.Stat(path); {
+	case err == nil:
+		return nil, fmt.Errorf("fail-fast signal file %s already exists, remove it before starting a new run", path)
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to check fail-fast signal file: %w", err)
+	}
+
+	return &failFastSignal{
+		logger: logger,
+		path:   path,
+	}, nil
+}
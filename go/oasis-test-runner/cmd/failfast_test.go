@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+func TestNewFailFastSignal(t *testing.T) {
+	require := require.New(t)
+	logger := logging.GetLogger("test")
+
+	// NOTE: The flags must be set via the flag set as the signal file key is
+	//       nested under the fail-fast key.
+	flags := rootCmd.Flags()
+	defer func() {
+		_ = flags.Set(cfgFailFast, "false")
+		_ = flags.Set(cfgFailFastSignalFile, "")
+	}()
+
+	s, err := newFailFastSignal(logger)
+	require.NoError(err, "newFailFastSignal should succeed when disabled")
+	require.Nil(s, "newFailFastSignal should return nil when disabled")
+
+	err = flags.Set(cfgFailFast, "true")
+	require.NoError(err, "Set")
+	_, err = newFailFastSignal(logger)
+	require.Error(err, "newFailFastSignal should fail without a signal file")
+
+	dir, err := ioutil.TempDir("", "oasis-test-runner-failfast")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signal")
+
+	err = flags.Set(cfgFailFastSignalFile, path)
+	require.NoError(err, "Set")
+	s, err = newFailFastSignal(logger)
+	require.NoError(err, "newFailFastSignal should succeed with a signal file")
+	require.Equal(path, s.path, "signal file path should be set")
+	require.False(s.isRaised(), "signal should not be raised initially")
+
+	// A leftover signal file should be rejected.
+	s.raise("test", 0)
+	require.True(s.isRaised(), "signal should be raised")
+	_, err = newFailFastSignal(logger)
+	require.Error(err, "newFailFastSignal should fail with a leftover signal file")
+}
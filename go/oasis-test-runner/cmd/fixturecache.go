@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+)
+
+const cfgFixtureCache = "fixture.cache"
+
+// fixtureCacheEntry is a prepared genesis document for a given fixture
+// fingerprint.
+type fixtureCacheEntry struct {
+	fingerprint hash.Hash
+	genesisPath string
+}
+
+// fixtureCache caches the genesis document generated by the last passing
+// scenario so that a consecutive scenario with an identical fixture can reuse
+// it instead of regenerating it.
+//
+// Only fixtures with deterministic identities are cached, as only in that case
+// all of the entity and node keys referenced by the genesis document are
+// regenerated identically. Fixtures with key managers are never cached as
+// generating the genesis document also provisions key manager state.
+type fixtureCache struct {
+	logger *logging.Logger
+
+	dir   *env.Dir
+	entry *fixtureCacheEntry
+}
+
+func isFixtureCacheable(fixture *oasis.NetworkFixture) bool {
+	return fixture.Network.DeterministicIdentities &&
+		fixture.Network.GenesisFile == "" &&
+		len(fixture.Keymanagers) == 0 &&
+		len(fixture.KeymanagerPolicies) == 0
+}
+
+// apply configures the fixture to use the cached genesis document in case the
+// fixture fingerprint matches the cache entry.
+//
+// Returns the fixture fingerprint (computed before any modification) and
+// whether the cached genesis document is used.
+func (fc *fixtureCache) apply(fixture *oasis.NetworkFixture) (hash.Hash, bool, error) {
+	if fc == nil || fixture == nil || !isFixtureCacheable(fixture) {
+		fc.invalidate()
+		return hash.Hash{}, false, nil
+	}
+
+	fp, err := fixture.Fingerprint()
+	if err != nil {
+		return hash.Hash{}, false, err
+	}
+	if fc.entry == nil || !fc.entry.fingerprint.Equal(&fp) {
+		fc.invalidate()
+		return fp, false, nil
+	}
+
+	fc.logger.Info("reusing cached genesis document",
+		"fingerprint", fp,
+		"genesis", fc.entry.genesisPath,
+	)
+	fixture.Network.GenesisFile = fc.entry.genesisPath
+
+	return fp, true, nil
+}
+
+// store records the genesis document of a passing scenario.
+func (fc *fixtureCache) store(fixture *oasis.NetworkFixture, fp hash.Hash, cached bool, net *oasis.Network) error {
+	if fc == nil || fixture == nil || net == nil {
+		return nil
+	}
+
+	// Make sure the fixture was not mutated while the scenario was running.
+	fixtureCopy := *fixture
+	if cached {
+		fixtureCopy.Network.GenesisFile = ""
+	}
+	if !isFixtureCacheable(&fixtureCopy) {
+		fc.invalidate()
+		return nil
+	}
+	newFp, err := fixtureCopy.Fingerprint()
+	if err != nil {
+		return err
+	}
+	if !newFp.Equal(&fp) {
+		fc.logger.Info("fixture mutated during scenario, invalidating cache",
+			"fingerprint", fp,
+			"new_fingerprint", newFp,
+		)
+		fc.invalidate()
+		return nil
+	}
+	if cached {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(net.GenesisPath())
+	if err != nil {
+		return fmt.Errorf("root: failed to read genesis document: %w", err)
+	}
+	genesisPath := filepath.Join(fc.dir.String(), fp.String()+".json")
+	if err = ioutil.WriteFile(genesisPath, b, 0o600); err != nil {
+		return fmt.Errorf("root: failed to cache genesis document: %w", err)
+	}
+	fc.entry = &fixtureCacheEntry{
+		fingerprint: fp,
+		genesisPath: genesisPath,
+	}
+
+	return nil
+}
+
+// invalidate drops the cache entry.
+func (fc *fixtureCache) invalidate() {
+	if fc == nil {
+		return
+	}
+	fc.entry = nil
+}
+
+func newFixtureCache(rootEnv *env.Env, logger *logging.Logger) (*fixtureCache, error) {
+	dir, err := rootEnv.NewSubDir("fixture_cache")
+	if err != nil {
+		return nil, fmt.Errorf("root: failed to create fixture cache directory: %w", err)
+	}
+
+	return &fixtureCache{
+		logger: logger,
+		dir:    dir,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+)
+
+const cfgInteractive = "interactive"
+
+// printConnectionDetails prints the details needed to attach oasis-node CLI
+// tools to the nodes of the given network.
+func printConnectionDetails(w io.Writer, dataDir string, net *oasis.Network) {
+	fmt.Fprintf(w, "Scenario data directory: %s\n", dataDir)
+	if net == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "Nodes:\n")
+	for _, node := range net.Nodes() {
+		fmt.Fprintf(w, "  * %s\n", node.Name)
+		fmt.Fprintf(w, "      socket:   unix:%s\n", node.SocketPath())
+		fmt.Fprintf(w, "      data dir: %s\n", node.DataDir())
+		fmt.Fprintf(w, "      log:      %s\n", node.LogPath())
+	}
+	if nodes := net.Nodes(); len(nodes) > 0 {
+		fmt.Fprintf(w, "Example: oasis-node control status -a unix:%s\n", nodes[0].SocketPath())
+	}
+}
+
+// waitInteractive prints the connection details of the given network and
+// blocks until a line is read from r or the context is canceled (e.g., on
+// SIGINT).
+func waitInteractive(ctx context.Context, w io.Writer, r io.Reader, dataDir string, net *oasis.Network) {
+	printConnectionDetails(w, dataDir, net)
+	fmt.Fprintf(w, "Press Enter to continue with cleanup (or Ctrl+C to abort)...\n")
+
+	lineCh := make(chan struct{})
+	go func() {
+		// The reader is abandoned in case the context is canceled first.
+		_, _ = bufio.NewReader(r).ReadString('\n')
+		close(lineCh)
+	}()
+
+	select {
+	case <-lineCh:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitInteractive(t *testing.T) {
+	require := require.New(t)
+
+	// Enter should resume the run.
+	var out bytes.Buffer
+	waitInteractive(context.Background(), &out, strings.NewReader("\n"), "/tmp/scenario", nil)
+	require.Contains(out.String(), "Scenario data directory: /tmp/scenario")
+
+	// Canceling the context (e.g., on SIGINT) should resume the run even without any input.
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	doneCh := make(chan struct{})
+	go func() {
+		waitInteractive(ctx, ioutil.Discard, pr, "/tmp/scenario", nil)
+		close(doneCh)
+	}()
+	cancel()
+
+	select {
+	case <-doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("waitInteractive did not return after the context was canceled")
+	}
+}
@@ -2,9 +2,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime/debug"
@@ -35,8 +39,15 @@ const (
 	cfgConfigFile       = "config"
 	cfgLogNoStdout      = "log.no_stdout"
 	cfgNumRuns          = "num_runs"
+	cfgCountOnly        = "count_only"
 	cfgParallelJobCount = "parallel.job_count"
 	cfgParallelJobIndex = "parallel.job_index"
+	cfgShuffle          = "shuffle"
+	cfgShuffleSeed      = "shuffle.seed"
+
+	// cfgScenarioLogLevel is the per-scenario log level override, passed as
+	// --<scenario_name>.log_level.
+	cfgScenarioLogLevel = "log_level"
 )
 
 var (
@@ -58,9 +69,11 @@ var (
 
 	oasisTestRunnerCollectors = []prometheus.Collector{
 		metrics.UpGauge,
+		metrics.PeakRSSGauge,
 	}
 
 	pusher              *push.Pusher
+	fixtures            *fixtureCache
 	oasisTestRunnerOnce sync.Once
 )
 
@@ -76,6 +89,9 @@ func RootCmd() *cobra.Command {
 // Execute spawns the main entry point after handing the config file.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		if errors.Is(err, errBudgetExceeded) {
+			os.Exit(budgetExceededExitCode)
+		}
 		os.Exit(1)
 	}
 }
@@ -101,7 +117,9 @@ func RegisterScenarioParams(name string, p *env.ParameterFlagSet) {
 	fs := flag.NewFlagSet(name, flag.ContinueOnError)
 	p.VisitAll(func(f *flag.Flag) {
 		fs.StringSlice(name+"."+f.Name, []string{f.Value.String()}, f.Usage)
+		scenarioParams[name] = append(scenarioParams[name], f.Name)
 	})
+	fs.String(name+"."+cfgScenarioLogLevel, "", "test runner log level override while running the scenario (does not affect child nodes)")
 	rootCmd.Flags().AddFlagSet(fs)
 	_ = viper.BindPFlags(fs)
 }
@@ -109,11 +127,28 @@ func RegisterScenarioParams(name string, p *env.ParameterFlagSet) {
 // parseScenarioParams parses --<scenario_name>.<key1>=<val1>,<val2>... flags
 // combinations, clones provided proto-scenarios, and populates them so that
 // each scenario instance has a unique parameter set.
-// Returns a mapping: scenario name -> list of scenario instances.
+// Returns a mapping: scenario name -> list of scenario instances, and a mapping
+// of scenario name -> log level for scenarios with a log level override.
 // NOTE: Golang maps are unordered so ordering of scenarios is not preserved.
-func parseScenarioParams(toRun []scenario.Scenario) (map[string][]scenario.Scenario, error) {
+func parseScenarioParams(toRun []scenario.Scenario) (map[string][]scenario.Scenario, map[string]logging.Level, error) {
 	scListsToRun := make(map[string][]scenario.Scenario)
+	scLogLevels := make(map[string]logging.Level)
 	for _, sc := range toRun {
+		// Use the log level override for the most specific (generalized)
+		// scenario, if any.
+		for _, genName := range generalizedScenarioName(sc.Name()) {
+			lvlStr := viper.GetString(fmt.Sprintf(common.ScenarioParamsMask, genName, cfgScenarioLogLevel))
+			if lvlStr == "" {
+				continue
+			}
+			var lvl logging.Level
+			if err := lvl.Set(lvlStr); err != nil {
+				return nil, nil, fmt.Errorf("parseScenarioParams: bad log level for scenario %s: %w", sc.Name(), err)
+			}
+			scLogLevels[sc.Name()] = lvl
+			break
+		}
+
 		zippedParams := make(map[string][]string)
 		sc.Parameters().VisitAll(func(f *flag.Flag) {
 			// Default to parameter values that were registered as defaults for
@@ -143,7 +178,9 @@ func parseScenarioParams(toRun []scenario.Scenario) (map[string][]scenario.Scena
 			sCloned := sc.Clone()
 			for param, val := range paramSet {
 				if err := sCloned.Parameters().Set(param, val); err != nil {
-					return nil, fmt.Errorf("parseScenarioParams: error setting viper parameter: %w", err)
+					return nil, nil, fmt.Errorf("parseScenarioParams: bad value for parameter '%s' of scenario %s: %w",
+						param, sc.Name(), err,
+					)
 				}
 			}
 			scListsToRun[sc.Name()] = append(scListsToRun[sc.Name()], sCloned)
@@ -156,7 +193,7 @@ func parseScenarioParams(toRun []scenario.Scenario) (map[string][]scenario.Scena
 		}
 	}
 
-	return scListsToRun, nil
+	return scListsToRun, scLogLevels, nil
 }
 
 // generalizedScenarioNames returns list of generalized scenario names from the
@@ -282,6 +319,22 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 	defer rootEnv.Cleanup()
 	logger := logging.GetLogger("test-runner")
 
+	cleanup, err := newCleanupPolicy(viper.GetString(cfgCleanupPolicy), env.GetRootDir(), logger)
+	if err != nil {
+		return err
+	}
+	defer cleanup.printRetained()
+
+	summary := &runSummary{}
+	defer summary.print(viper.GetString(cfgSummaryFile), logger)
+
+	fixtures = nil
+	if viper.GetBool(cfgFixtureCache) {
+		if fixtures, err = newFixtureCache(rootEnv, logger); err != nil {
+			return err
+		}
+	}
+
 	// Enumerate requested scenarios.
 	toRun := common.GetDefaultScenarios() // Run all default scenarios if not set.
 	if scNameRegexes := viper.GetStringSlice(common.CfgScenarioRegex); len(scNameRegexes) > 0 {
@@ -346,6 +399,25 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 			excludeMap[strings.ToLower(v)] = true
 		}
 	}
+	var excludeRegexes []*regexp.Regexp
+	for _, excludeNameRegex := range viper.GetStringSlice(common.CfgScenarioExcludeRegex) {
+		var regex *regexp.Regexp
+		if regex, err = regexp.Compile(fmt.Sprintf("^%s$", excludeNameRegex)); err != nil {
+			return fmt.Errorf("root: bad exclude scenario regexp: %w", err)
+		}
+		excludeRegexes = append(excludeRegexes, regex)
+	}
+	isExcluded := func(sc scenario.Scenario) bool {
+		if excludeMap[strings.ToLower(sc.Name())] {
+			return true
+		}
+		for _, regex := range excludeRegexes {
+			if regex.MatchString(sc.Name()) {
+				return true
+			}
+		}
+		return false
+	}
 
 	// Get parallel job execution parameters.
 	parallelJobCount := viper.GetInt(cfgParallelJobCount)
@@ -357,13 +429,76 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 		)
 	}
 
+	// Shuffle requested scenarios to surface order-dependent failures. All
+	// parallel jobs must use the same seed to keep partitioning consistent.
+	if viper.GetBool(cfgShuffle) {
+		seed := viper.GetInt64(cfgShuffleSeed)
+		if !viper.IsSet(cfgShuffleSeed) {
+			if parallelJobCount > 1 {
+				return fmt.Errorf("root: %s flag is required with multiple parallel jobs", cfgShuffleSeed)
+			}
+			seed = time.Now().UnixNano()
+		}
+		logger.Info("shuffling scenarios",
+			"seed", seed,
+		)
+		// Always print the seed, as the default log level would hide it.
+		fmt.Printf("Shuffling scenarios with seed %d (reproduce with --%s %d).\n", seed, cfgShuffleSeed, seed)
+		rng := rand.New(rand.NewSource(seed)) // nolint: gosec
+		rng.Shuffle(len(toRun), func(i, j int) { toRun[i], toRun[j] = toRun[j], toRun[i] })
+	}
+
+	// Set up the fail-fast signal shared with other parallel jobs, if enabled.
+	failFast, err := newFailFastSignal(logger)
+	if err != nil {
+		return fmt.Errorf("root: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go failFast.watch(ctx, cancel)
+
+	// Abort the in-flight scenario on SIGINT/SIGTERM.
+	interruptCh := watchSignals(ctx, cancel, logger)
+
+	// Abort the in-flight scenario once the wall-clock budget is exceeded.
+	budgetCh := watchBudget(ctx, cancel, viper.GetDuration(cfgBudget), logger)
+
 	// Expand the list of scenarios to run with the passed scenario parameters.
-	var toRunExploded map[string][]scenario.Scenario
-	toRunExploded, err = parseScenarioParams(toRun)
+	var (
+		toRunExploded map[string][]scenario.Scenario
+		logLevels     map[string]logging.Level
+	)
+	toRunExploded, logLevels, err = parseScenarioParams(toRun)
 	if err != nil {
 		return fmt.Errorf("root: failed to parse scenario parameters: %w", err)
 	}
 
+	// Report the number of scenario instances that will be executed. This is always printed, as
+	// the default log level would hide it.
+	fmt.Printf("Scenario instances:\n")
+	total := 0
+	for _, sc := range toRun {
+		n := len(toRunExploded[sc.Name()]) * numRuns
+		total += n
+		logger.Info("scenario instances to run",
+			"scenario", sc.Name(),
+			"param_sets", len(toRunExploded[sc.Name()]),
+			"num_runs", numRuns,
+			"instances", n,
+		)
+		fmt.Printf("  * %s: %d\n", sc.Name(), n)
+	}
+	logger.Info("total scenario instances to run",
+		"instances", total,
+	)
+	fmt.Printf("Total scenario instances: %d\n", total)
+	if viper.GetBool(cfgCountOnly) {
+		return nil
+	}
+	if viper.GetBool(cfgInteractive) && (total != 1 || parallelJobCount != 1) {
+		return fmt.Errorf("root: %s flag requires exactly one scenario instance (selected: %d)", cfgInteractive, total)
+	}
+
 	// Run all requested scenarios.
 	index := 0
 	for run := 0; run < numRuns; run++ {
@@ -390,23 +525,40 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 					continue
 				}
 
-				if excludeMap[strings.ToLower(v.Name())] {
-					logger.Info("skipping scenario (excluded by environment)",
+				if isExcluded(v) {
+					logger.Info("skipping scenario (excluded)",
 						"scenario", name, "run_id", runID,
 					)
+					summary.add(name, runID, resultSkipped, 0)
 					index++
 					continue
 				}
 
+				if ctx.Err() != nil {
+					summary.add(name, runID, resultSkipped, 0)
+					if isInterrupted(budgetCh) {
+						logger.Error("not running scenario (budget exceeded)",
+							"scenario", name, "run_id", runID,
+						)
+						return errBudgetExceeded
+					}
+					logger.Error("not running scenario (run aborted)",
+						"scenario", name, "run_id", runID,
+					)
+					return fmt.Errorf("root: run aborted")
+				}
+
 				logger.Info("running scenario",
 					"scenario", name, "run_id", runID,
 				)
 
 				childEnv, err := rootEnv.NewChild(n, &env.ScenarioInstanceInfo{
-					Scenario:     v.Name(),
-					Instance:     filepath.Base(rootEnv.Dir()),
-					ParameterSet: v.Parameters(),
-					Run:          run,
+					Scenario:      v.Name(),
+					Instance:      filepath.Base(rootEnv.Dir()),
+					ParameterSet:  v.Parameters(),
+					Run:           run,
+					BasePort:      oasis.NodePortRange(index),
+					MetricsLabels: scenarioMetricsLabels(v),
 				})
 				if err != nil {
 					logger.Error("failed to setup child environment",
@@ -430,16 +582,45 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 					pusher = pusher.Gatherer(prometheus.DefaultGatherer)
 				}
 
-				if err = doScenario(childEnv, v); err != nil {
+				startTime := time.Now()
+
+				// Override the log level for this scenario only, if configured.
+				defaultLogLevel := logging.GetLevel()
+				if lvl, ok := logLevels[name]; ok {
+					logging.SetLevel(lvl)
+				}
+
+				if err = doScenario(ctx, childEnv, v); err != nil {
 					logger.Error("failed to run scenario",
 						"err", err,
 						"scenario", name,
 						"run_id", runID,
 					)
 					err = fmt.Errorf("root: failed to run scenario: %w", err)
+
+					// Exceeding the budget of this job is not a failure other jobs should abort on.
+					if !isInterrupted(budgetCh) {
+						failFast.raise(name, parallelJobIndex)
+					}
+
+					// Copy failure artifacts before the data directory is cleaned up.
+					artifactsDir := viper.GetString(cfgFailureArtifactsDir)
+					if genesisPath, copyErr := copyFailureArtifacts(childEnv.ScenarioInfo(), artifactsDir, n); copyErr != nil {
+						logger.Error("failed to copy failure artifacts",
+							"err", copyErr,
+							"scenario", name,
+							"run_id", runID,
+						)
+					} else if genesisPath != "" {
+						logger.Info("copied genesis document of failed scenario",
+							"path", genesisPath,
+							"scenario", name,
+							"run_id", runID,
+						)
+					}
 				}
 
-				if cleanErr := doCleanup(childEnv); cleanErr != nil {
+				if cleanErr := cleanup.doCleanup(childEnv, err == nil); cleanErr != nil {
 					logger.Error("failed to clean up child environment",
 						"err", cleanErr,
 						"scenario", name,
@@ -450,7 +631,27 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 					}
 				}
 
+				logging.SetLevel(defaultLogLevel)
+
+				result := resultPassed
 				if err != nil {
+					result = resultFailed
+				}
+				summary.add(name, runID, result, time.Since(startTime))
+
+				// Retain all data directories for inspection in case the run
+				// was interrupted.
+				if isInterrupted(interruptCh) {
+					env.GetRootDir().SetNoCleanup(true)
+					logger.Error("run interrupted, retaining data directory",
+						"path", rootEnv.Dir(),
+					)
+				}
+
+				if err != nil {
+					if isInterrupted(budgetCh) {
+						return fmt.Errorf("%w: %s", errBudgetExceeded, err)
+					}
 					return err
 				}
 
@@ -466,23 +667,63 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 	return nil
 }
 
-func doScenario(childEnv *env.Env, sc scenario.Scenario) (err error) {
+// scenarioMetricsLabels returns the custom metrics labels of the scenario, if
+// it provides any.
+func scenarioMetricsLabels(sc scenario.Scenario) map[string]string {
+	if ml, ok := sc.(scenario.MetricsLabeler); ok {
+		return ml.MetricsLabels()
+	}
+	return nil
+}
+
+func doScenario(ctx context.Context, childEnv *env.Env, sc scenario.Scenario) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("root: panic caught running scenario: %v: %s", r, debug.Stack())
 		}
 	}()
 
+	// Reclaim scenario-spawned resources even if the scenario panics.
+	defer func() {
+		if cleanErr := childEnv.RunScenarioCleanups(); cleanErr != nil && err == nil {
+			err = fmt.Errorf("root: failed to run scenario cleanup routines: %w", cleanErr)
+		}
+	}()
+
 	if err = sc.PreInit(childEnv); err != nil {
 		err = fmt.Errorf("root: failed to pre-initialize scenario: %w", err)
 		return
 	}
 
+	defer func() {
+		if err != nil {
+			fixtures.invalidate()
+		}
+	}()
+
+	if err = checkRequiredBinaries(sc); err != nil {
+		return
+	}
+
 	var fixture *oasis.NetworkFixture
 	if fixture, err = sc.Fixture(); err != nil {
 		err = fmt.Errorf("root: failed to initialize network fixture: %w", err)
 		return
 	}
+	if fixture != nil {
+		if err = fixture.ExpandEnv(); err != nil {
+			err = fmt.Errorf("root: failed to expand environment variables in fixture: %w", err)
+			return
+		}
+	}
+
+	// Reuse the prepared genesis document of the previous scenario, if the
+	// fixture cache is enabled and the fixtures match.
+	fixtureFp, fixtureCached, err := fixtures.apply(fixture)
+	if err != nil {
+		err = fmt.Errorf("root: failed to apply fixture cache: %w", err)
+		return
+	}
 
 	// Instantiate fixture if it is non-nil. Otherwise assume Init will do
 	// something on its own.
@@ -513,32 +754,123 @@ func doScenario(childEnv *env.Env, sc scenario.Scenario) (err error) {
 		}
 	}
 
-	if err = sc.Run(childEnv); err != nil {
+	sampler := newRSSSampler(net)
+	err = runScenario(ctx, childEnv, sc)
+	if err == nil {
+		err = runPostRun(childEnv, sc, net)
+	}
+	peakRSS := sampler.stop()
+
+	// In interactive mode, keep the network running until the developer is done inspecting it.
+	if viper.GetBool(cfgInteractive) && ctx.Err() == nil {
+		waitInteractive(ctx, os.Stdout, os.Stdin, childEnv.Dir(), net)
+	}
+	childEnv.ScenarioInfo().PeakRSSBytes = peakRSS
+	if infoErr := childEnv.WriteScenarioInfo(); infoErr != nil && err == nil {
+		err = infoErr
+	}
+	if err != nil {
 		err = fmt.Errorf("root: failed to run scenario: %w", err)
 		return
 	}
 
 	if pusher != nil {
 		metrics.UpGauge.Set(0.0)
+		metrics.PeakRSSGauge.Set(float64(peakRSS))
+		// Only report the time to first block when the network actually committed one, so that
+		// failed runs do not push a bogus zero.
+		if ttfb, ok := timeToFirstBlock(net); ok {
+			metrics.TimeToFirstBlockGauge.Set(ttfb.Seconds())
+			pusher = pusher.Collector(metrics.TimeToFirstBlockGauge)
+		}
 		if err = pusher.Push(); err != nil {
 			err = fmt.Errorf("root: failed to push metrics: %w", err)
 			return
 		}
 	}
 
+	if err = fixtures.store(fixture, fixtureFp, fixtureCached, net); err != nil {
+		err = fmt.Errorf("root: failed to update fixture cache: %w", err)
+		return
+	}
+
 	return
 }
 
-func doCleanup(childEnv *env.Env) (err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("root: panic caught cleaning up scenario: %v, %s", r, debug.Stack())
+// timeToFirstBlock returns the time from network creation to the first committed consensus
+// block and a flag indicating whether such a block has been observed.
+func timeToFirstBlock(net *oasis.Network) (time.Duration, bool) {
+	if net == nil {
+		return 0, false
+	}
+	return net.TimeToFirstBlock()
+}
+
+// checkRequiredBinaries makes sure that all of the binaries required by the
+// scenario exist and are executable.
+func checkRequiredBinaries(sc scenario.Scenario) error {
+	br, ok := sc.(scenario.BinaryRequirer)
+	if !ok {
+		return nil
+	}
+	for _, binary := range br.RequiredBinaries() {
+		// Binaries without a path separator are resolved via PATH, the same
+		// as when they are executed.
+		if _, err := exec.LookPath(binary); err != nil {
+			return fmt.Errorf("root: missing binary %s: %w", binary, err)
 		}
+	}
+	return nil
+}
+
+// runPostRun runs the scenario's final network state assertions in case the
+// scenario implements them.
+func runPostRun(childEnv *env.Env, sc scenario.Scenario, net *oasis.Network) error {
+	pr, ok := sc.(scenario.PostRunner)
+	if !ok {
+		return nil
+	}
+	if err := pr.PostRun(childEnv, net); err != nil {
+		return fmt.Errorf("%w: %s", scenario.ErrPostRunAssertion, err)
+	}
+	return nil
+}
+
+// scenarioAbortTimeout is the maximum amount of time to wait for an aborted
+// scenario to return before proceeding with the cleanup.
+var scenarioAbortTimeout = 30 * time.Second
+
+// runScenario runs the scenario, aborting it in case the context is canceled
+// before the scenario completes.
+//
+// On abort, the scenario is given up to scenarioAbortTimeout to return so
+// that the cleanup does not run concurrently with it. Only if the scenario
+// does not return in time is it left running in the background until the
+// child environment is cleaned up, which terminates all of its nodes.
+func runScenario(ctx context.Context, childEnv *env.Env, sc scenario.Scenario) error {
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("root: panic caught running scenario: %v: %s", r, debug.Stack())
+			}
+		}()
+
+		errCh <- sc.Run(childEnv)
 	}()
 
-	childEnv.Cleanup()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
 
-	return
+	select {
+	case <-errCh:
+		return fmt.Errorf("root: scenario aborted: %w", ctx.Err())
+	case <-time.After(scenarioAbortTimeout):
+		return fmt.Errorf("root: scenario aborted: %w (scenario did not return within %s)", ctx.Err(), scenarioAbortTimeout)
+	}
 }
 
 func runList(cmd *cobra.Command, args []string) {
@@ -588,6 +920,11 @@ func init() {
 		nil,
 		"regexp patterns matching names of scenarios to skip",
 	)
+	persistentFlags.StringSlice(
+		common.CfgScenarioExcludeRegex,
+		nil,
+		"regexp patterns matching names of scenarios to exclude after partitioning between parallel jobs (merged with OASIS_EXCLUDE_E2E)",
+	)
 	persistentFlags.String(metrics.CfgMetricsAddr, "", "Prometheus address")
 	persistentFlags.StringToString(
 		metrics.CfgMetricsLabels,
@@ -607,12 +944,24 @@ func init() {
 		"metrics push interval for test runner and oasis nodes",
 	)
 	rootFlags.IntVarP(&numRuns, cfgNumRuns, "n", 1, "number of runs for given scenario(s)")
+	rootFlags.String(cfgSummaryFile, "", "path to file where the run summary is written")
+	rootFlags.Bool(cfgCountOnly, false, "only print the number of scenario instances to run and exit")
 	rootFlags.Int(cfgParallelJobCount, 1, "(for CI) number of overall parallel jobs")
 	rootFlags.Int(cfgParallelJobIndex, 0, "(for CI) index of this parallel job")
+	rootFlags.String(cfgCleanupPolicy, cleanupPolicyAlways, "scenario data directory cleanup policy (always, on-success, never)")
+	rootFlags.Bool(cfgShuffle, false, "run scenarios in random order")
+	rootFlags.Int64(cfgShuffleSeed, 0, "random seed used to shuffle scenarios (default: current time)")
+	rootFlags.Bool(cfgFixtureCache, false, "reuse the genesis document of the previous scenario with an identical fixture")
+	rootFlags.Bool(cfgFailFast, false, "abort in-flight scenarios as soon as any parallel job fails (requires fail_fast.signal_file)")
+	rootFlags.String(cfgFailFastSignalFile, "", "(for CI) failure signal file shared by all parallel jobs")
+	rootFlags.String(cfgFailureArtifactsDir, "", "directory to which the genesis document of failed scenarios is copied")
+	rootFlags.Duration(cfgBudget, 0, "wall-clock budget for the whole run, after which the in-flight scenario is aborted (0 = unlimited)")
+	rootFlags.Bool(cfgInteractive, false, "print node connection details and wait for Enter after the scenario runs")
 	_ = viper.BindPFlags(rootFlags)
 	rootCmd.Flags().AddFlagSet(rootFlags)
 	rootCmd.Flags().AddFlagSet(env.Flags)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(validateParamsCmd)
 
 	cmp.Register(rootCmd)
 
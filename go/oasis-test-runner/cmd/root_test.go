@@ -1,9 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/scenario"
 )
 
 func TestComputeParamSets(t *testing.T) {
@@ -80,3 +88,94 @@ func TestGeneralizedScenarioName(t *testing.T) {
 	expectedNames = []string{""}
 	require.Equal(t, expectedNames, generalizedScenarioName(""))
 }
+
+type postRunScenario struct {
+	scenario.Scenario
+
+	err    error
+	called bool
+}
+
+func (sc *postRunScenario) PostRun(childEnv *env.Env, net *oasis.Network) error {
+	sc.called = true
+	return sc.err
+}
+
+func TestRunPostRun(t *testing.T) {
+	require := require.New(t)
+
+	// Scenarios without the hook are skipped.
+	require.NoError(runPostRun(nil, nil, nil), "runPostRun should succeed without a hook")
+
+	sc := &postRunScenario{}
+	require.NoError(runPostRun(nil, sc, nil), "runPostRun should succeed when assertions pass")
+	require.True(sc.called, "PostRun should be called")
+
+	sc = &postRunScenario{err: fmt.Errorf("unexpected balance")}
+	err := runPostRun(nil, sc, nil)
+	require.Error(err, "runPostRun should fail when assertions fail")
+	require.True(errors.Is(err, scenario.ErrPostRunAssertion), "error should be a post-run assertion error")
+	require.Contains(err.Error(), "unexpected balance", "error should include the assertion failure")
+}
+
+type panickingScenario struct {
+	scenario.Scenario
+
+	cleanedUp bool
+}
+
+func (sc *panickingScenario) PreInit(childEnv *env.Env) error {
+	childEnv.RegisterCleanup(func() {
+		sc.cleanedUp = true
+	})
+	panic("scenario bug")
+}
+
+func TestDoScenarioCleanupOnPanic(t *testing.T) {
+	require := require.New(t)
+
+	sc := &panickingScenario{}
+	err := doScenario(context.Background(), env.New(nil), sc)
+	require.Error(err, "doScenario should fail when the scenario panics")
+	require.Contains(err.Error(), "scenario bug", "error should include the panic")
+	require.True(sc.cleanedUp, "scenario cleanup routines should run on panic")
+}
+
+type blockingScenario struct {
+	scenario.Scenario
+
+	releaseCh chan struct{}
+	returned  bool
+}
+
+func (sc *blockingScenario) Run(childEnv *env.Env) error {
+	<-sc.releaseCh
+	sc.returned = true
+	return nil
+}
+
+func TestRunScenarioAbort(t *testing.T) {
+	require := require.New(t)
+
+	// An aborted scenario should be waited for before returning.
+	sc := &blockingScenario{releaseCh: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	time.AfterFunc(50*time.Millisecond, func() { close(sc.releaseCh) })
+	err := runScenario(ctx, nil, sc)
+	require.True(errors.Is(err, context.Canceled), "runScenario should fail when aborted")
+	require.True(sc.returned, "runScenario should wait for the aborted scenario to return")
+
+	// But only for a bounded amount of time.
+	defaultTimeout := scenarioAbortTimeout
+	scenarioAbortTimeout = 50 * time.Millisecond
+	defer func() {
+		scenarioAbortTimeout = defaultTimeout
+	}()
+
+	sc = &blockingScenario{releaseCh: make(chan struct{})}
+	defer close(sc.releaseCh)
+	err = runScenario(ctx, nil, sc)
+	require.True(errors.Is(err, context.Canceled), "runScenario should fail when aborted")
+	require.Contains(err.Error(), "did not return", "error should mention the scenario did not return")
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+)
+
+const rssSampleInterval = time.Second
+
+// rssSampler periodically samples the total resident set size of all nodes in
+// the network and records its peak.
+//
+// Note: Sampling relies on /proc and is only supported on Linux. On other
+// platforms the recorded peak is always zero.
+type rssSampler struct {
+	sync.Mutex
+
+	net  *oasis.Network
+	peak uint64
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+func (s *rssSampler) sample() {
+	var total uint64
+	for _, node := range s.net.Nodes() {
+		pid := node.Pid()
+		if pid == 0 {
+			continue
+		}
+		rss, err := readProcessRSS(pid)
+		if err != nil {
+			// The node may have exited in the meantime.
+			continue
+		}
+		total += rss
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if total > s.peak {
+		s.peak = total
+	}
+}
+
+func (s *rssSampler) worker() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(rssSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sample()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// stop stops sampling and returns the peak total resident set size in bytes.
+func (s *rssSampler) stop() uint64 {
+	if s == nil {
+		return 0
+	}
+
+	close(s.stopCh)
+	<-s.doneCh
+	s.sample()
+
+	s.Lock()
+	defer s.Unlock()
+	return s.peak
+}
+
+// newRSSSampler starts sampling the resident set size of all nodes in the
+// given network. Returns nil if the network is nil.
+func newRSSSampler(net *oasis.Network) *rssSampler {
+	if net == nil {
+		return nil
+	}
+
+	s := &rssSampler{
+		net:    net,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	go s.worker()
+
+	return s
+}
+
+// readProcessRSS returns the current resident set size of the given process in
+// bytes.
+func readProcessRSS(pid int) (uint64, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return parseProcStatusRSS(f)
+}
+
+// parseProcStatusRSS parses the VmRSS entry of a /proc/<pid>/status file.
+func parseProcStatusRSS(r io.Reader) (uint64, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || fields[0] != "VmRSS:" {
+			continue
+		}
+		if fields[2] != "kB" {
+			return 0, fmt.Errorf("unexpected VmRSS unit: %s", fields[2])
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("malformed VmRSS value: %w", err)
+		}
+		return kb * 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("VmRSS not found")
+}
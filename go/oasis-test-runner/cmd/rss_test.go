@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcStatusRSS(t *testing.T) {
+	rss, err := parseProcStatusRSS(strings.NewReader("Name:\toasis-node\nVmHWM:\t  2048 kB\nVmRSS:\t  1024 kB\n"))
+	require.NoError(t, err)
+	require.EqualValues(t, 1024*1024, rss)
+
+	_, err = parseProcStatusRSS(strings.NewReader("Name:\toasis-node\n"))
+	require.Error(t, err, "missing VmRSS should fail")
+
+	_, err = parseProcStatusRSS(strings.NewReader("VmRSS:\t  abc kB\n"))
+	require.Error(t, err, "malformed VmRSS should fail")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// watchSignals cancels the given context once SIGINT or SIGTERM is received.
+//
+// Returns a channel that is closed once a signal has been received. After the
+// first signal, default signal handling is restored so that a repeated signal
+// terminates the process immediately.
+func watchSignals(ctx context.Context, cancel context.CancelFunc, logger *logging.Logger) <-chan struct{} {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	interruptCh := make(chan struct{})
+	go func() {
+		defer signal.Stop(sigCh)
+
+		select {
+		case sig := <-sigCh:
+			logger.Error("received signal, aborting the in-flight scenario",
+				"signal", sig,
+			)
+			close(interruptCh)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return interruptCh
+}
+
+// isInterrupted returns true iff the interrupt channel has been closed.
+func isInterrupted(interruptCh <-chan struct{}) bool {
+	select {
+	case <-interruptCh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+const cfgSummaryFile = "summary.file"
+
+const (
+	resultPassed  = "passed"
+	resultFailed  = "failed"
+	resultSkipped = "skipped"
+)
+
+// scenarioResult is the result of a single scenario instance.
+type scenarioResult struct {
+	scenario string
+	runID    int
+	result   string
+	duration time.Duration
+}
+
+// runSummary collects the results of all scenario instances in a run.
+type runSummary struct {
+	results []*scenarioResult
+}
+
+func (s *runSummary) add(scenario string, runID int, result string, duration time.Duration) {
+	s.results = append(s.results, &scenarioResult{
+		scenario: scenario,
+		runID:    runID,
+		result:   result,
+		duration: duration,
+	})
+}
+
+// write writes the summary table, sorted by duration in descending order,
+// followed by the totals.
+func (s *runSummary) write(w io.Writer) error {
+	results := make([]*scenarioResult, len(s.results))
+	copy(results, s.results)
+	sort.SliceStable(results, func(i, j int) bool { return results[i].duration > results[j].duration })
+
+	totals := make(map[string]int)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "SCENARIO\tRUN ID\tRESULT\tDURATION\n")
+	for _, r := range results {
+		totals[r.result]++
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", r.scenario, r.runID, r.result, r.duration.Round(time.Millisecond))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "Total: %d passed, %d failed, %d skipped\n",
+		totals[resultPassed], totals[resultFailed], totals[resultSkipped],
+	)
+	return err
+}
+
+// print prints the summary to stdout and, if configured, to the given file.
+func (s *runSummary) print(path string, logger *logging.Logger) {
+	if len(s.results) == 0 {
+		return
+	}
+
+	fmt.Printf("Summary:\n")
+	if err := s.write(os.Stdout); err != nil {
+		logger.Error("failed to print summary",
+			"err", err,
+		)
+	}
+
+	if path == "" {
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		logger.Error("failed to create summary file",
+			"err", err,
+			"path", path,
+		)
+		return
+	}
+	defer f.Close()
+	if err = s.write(f); err != nil {
+		logger.Error("failed to write summary file",
+			"err", err,
+			"path", path,
+		)
+	}
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSummary(t *testing.T) {
+	var s runSummary
+	s.add("e2e/fast", 0, resultPassed, 1*time.Second)
+	s.add("e2e/slow", 0, resultFailed, 3*time.Second)
+	s.add("e2e/medium", 1, resultPassed, 2*time.Second)
+	s.add("e2e/excluded", 0, resultSkipped, 0)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.write(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 6)
+	require.True(t, strings.HasPrefix(lines[1], "e2e/slow"), "slowest scenario should be first")
+	require.True(t, strings.HasPrefix(lines[2], "e2e/medium"))
+	require.True(t, strings.HasPrefix(lines[3], "e2e/fast"))
+	require.True(t, strings.HasPrefix(lines[4], "e2e/excluded"))
+	require.Equal(t, "Total: 2 passed, 1 failed, 1 skipped", lines[5])
+}
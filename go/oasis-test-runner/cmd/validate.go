@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+var (
+	validateParamsCmd = &cobra.Command{
+		Use:   "validate-params [flags]",
+		Short: "validate scenario parameter flags without running any scenario",
+		Long: `validate-params checks all flags passed on the command line and all keys in
+the config file (if given via --config) against the registered test runner
+flags and scenario parameters. It fails listing all unrecognized flags,
+including typos in --<scenario_name>.<parameter> flags which would otherwise
+have no effect when given in the config file.`,
+		DisableFlagParsing: true,
+		RunE:               runValidateParams,
+	}
+
+	// scenarioParams maps (generalized) scenario names to their registered
+	// parameter names.
+	scenarioParams = make(map[string][]string)
+)
+
+// lookupFlag returns the flag with the given name (without leading dashes)
+// registered with the root command, or nil if there is no such flag.
+func lookupFlag(name string, shorthand bool) *flag.Flag {
+	flagSets := []*flag.FlagSet{rootCmd.Flags(), rootCmd.PersistentFlags()}
+	for _, fs := range flagSets {
+		var f *flag.Flag
+		switch {
+		case !shorthand:
+			f = fs.Lookup(name)
+		case len(name) == 1:
+			f = fs.ShorthandLookup(name)
+		}
+		if f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// validateParam validates the given flag name, returning a descriptive error
+// if it is not recognized.
+func validateParam(name string, shorthand bool) error {
+	if lookupFlag(name, shorthand) != nil {
+		return nil
+	}
+
+	// Check whether the flag looks like a parameter of a known scenario.
+	var scNames []string
+	for scName := range scenarioParams {
+		scNames = append(scNames, scName)
+	}
+	// Prefer the most specific scenario name.
+	sort.Sort(sort.Reverse(sort.StringSlice(scNames)))
+	for _, scName := range scNames {
+		if !strings.HasPrefix(name, scName+".") {
+			continue
+		}
+		return fmt.Errorf("unknown parameter '%s' of scenario %s (available: %s)",
+			strings.TrimPrefix(name, scName+"."),
+			scName,
+			strings.Join(append(scenarioParams[scName], cfgScenarioLogLevel), ", "),
+		)
+	}
+
+	return fmt.Errorf("unknown flag '%s'", name)
+}
+
+// validateArgs validates all flags given in the command line arguments.
+//
+// Arguments are split into flags and values the same way pflag does, so that
+// flag values are never mistaken for flags.
+func validateArgs(args []string) []error {
+	var errs []error
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			continue
+		}
+		if arg == "--" {
+			break
+		}
+
+		if strings.HasPrefix(arg, "--") {
+			// Long flags take a value either as --flag=value or --flag value.
+			name := strings.SplitN(arg[2:], "=", 2)[0]
+			if err := validateParam(name, false); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if f := lookupFlag(name, false); f.NoOptDefVal == "" && !strings.Contains(arg, "=") {
+				i++
+			}
+			continue
+		}
+
+		// Shorthand flags can be combined (e.g., -ab) and take a value either
+		// as -n3, -n=3 or -n 3.
+		for shorthands := arg[1:]; len(shorthands) > 0; shorthands = shorthands[1:] {
+			name := shorthands[:1]
+			if err := validateParam(name, true); err != nil {
+				errs = append(errs, err)
+				break
+			}
+			if lookupFlag(name, true).NoOptDefVal != "" {
+				continue
+			}
+			if len(shorthands) == 1 {
+				i++
+			}
+			break
+		}
+	}
+	return errs
+}
+
+// configFileArg returns the value of the config file flag, if given.
+func configFileArg(args []string) string {
+	for i, arg := range args {
+		switch {
+		case arg == "--"+cfgConfigFile && i+1 < len(args):
+			return args[i+1]
+		case strings.HasPrefix(arg, "--"+cfgConfigFile+"="):
+			return strings.TrimPrefix(arg, "--"+cfgConfigFile+"=")
+		}
+	}
+	return ""
+}
+
+// validateConfigFile validates all keys in the given config file.
+func validateConfigFile(path string) ([]error, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("validate-params: failed to read config file: %w", err)
+	}
+
+	var errs []error
+	keys := v.AllKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := validateParam(key, false); err != nil {
+			errs = append(errs, fmt.Errorf("config file: %w", err))
+		}
+	}
+	return errs, nil
+}
+
+func runValidateParams(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	errs := validateArgs(args)
+	if cfgPath := configFileArg(args); cfgPath != "" {
+		cfgErrs, err := validateConfigFile(cfgPath)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, cfgErrs...)
+	}
+
+	if len(errs) == 0 {
+		fmt.Printf("All parameters are valid.\n")
+		return nil
+	}
+	for _, err := range errs {
+		fmt.Printf("  * %s\n", err)
+	}
+	return fmt.Errorf("validate-params: %d unrecognized parameter(s)", len(errs))
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateArgs(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		numErrs int
+	}{
+		{"Long", []string{"--" + cfgNumRuns, "3"}, 0},
+		{"LongEquals", []string{"--" + cfgNumRuns + "=3"}, 0},
+		{"LongNegativeValue", []string{"--" + cfgNumRuns, "-1"}, 0},
+		{"LongBool", []string{"--" + cfgCountOnly, "scenario"}, 0},
+		{"Shorthand", []string{"-n", "3"}, 0},
+		{"ShorthandInline", []string{"-n3"}, 0},
+		{"ShorthandEquals", []string{"-n=3"}, 0},
+		{"UnknownLong", []string{"--no_such_flag"}, 1},
+		{"UnknownLongEquals", []string{"--no_such_flag=3"}, 1},
+		{"UnknownShorthand", []string{"-x"}, 1},
+		{"UnknownCombinedShorthand", []string{"-xn3"}, 1},
+		{"Multiple", []string{"--no_such_flag", "-n3", "--other_flag=1"}, 2},
+		{"Terminator", []string{"--", "--no_such_flag"}, 0},
+		{"Positional", []string{"scenario"}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateArgs(tc.args)
+			require.Len(t, errs, tc.numErrs, "validateArgs(%v)", tc.args)
+		})
+	}
+}
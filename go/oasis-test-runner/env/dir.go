@@ -19,6 +19,7 @@ const (
 	cfgBaseDir          = "basedir"
 	cfgBaseDirNoCleanup = "basedir.no_cleanup"
 	cfgBaseDirNoTempDir = "basedir.no_temp_dir"
+	cfgBaseDirDataDir   = "basedir.datadir"
 )
 
 var (
@@ -32,6 +33,10 @@ var (
 type Dir struct {
 	dir       string
 	noCleanup bool
+
+	// childDir is an optional separate directory under which the child
+	// environments are created.
+	childDir *Dir
 }
 
 // String returns the string representation (path) of the Dir.
@@ -48,25 +53,73 @@ func (d *Dir) Init(cmd *cobra.Command) error {
 	d.dir = viper.GetString(cfgBaseDir)
 	d.noCleanup = viper.GetBool(cfgBaseDirNoCleanup)
 
-	if viper.GetBool(cfgBaseDirNoTempDir) {
+	// Make sure the data directory is usable before creating anything.
+	dataDir := viper.GetString(cfgBaseDirDataDir)
+	if dataDir != "" {
+		if err := checkWritable(dataDir); err != nil {
+			return fmt.Errorf("env: data directory %s is not usable: %w", dataDir, err)
+		}
+	}
+
+	noTempDir := viper.GetBool(cfgBaseDirNoTempDir)
+	prefix := strings.Split(cmd.Use, " ")[0]
+	if noTempDir {
 		// If we don't create a temporary directory, don't clean up.
 		d.noCleanup = true
 	} else {
 		// Create a temporary directory using a prefix derived from the
 		// command's `Use` field.
 		var err error
-		splitUse := strings.Split(cmd.Use, " ")
-		if d.dir, err = ioutil.TempDir(d.dir, splitUse[0]); err != nil {
+		if d.dir, err = ioutil.TempDir(d.dir, prefix); err != nil {
 			return fmt.Errorf("env: failed to create default base directory: %w", err)
 		}
 	}
 
+	// Optionally place child environments under a separate data directory.
+	if dataDir != "" {
+		d.childDir = &Dir{
+			dir:       dataDir,
+			noCleanup: d.noCleanup,
+		}
+		if !noTempDir {
+			var err error
+			if d.childDir.dir, err = ioutil.TempDir(dataDir, prefix); err != nil {
+				return fmt.Errorf("env: failed to create data directory: %w", err)
+			}
+		}
+	}
+
 	return nil
 }
 
+// ChildDir returns the Dir under which child environments are created.
+func (d *Dir) ChildDir() *Dir {
+	if d.childDir != nil {
+		return d.childDir
+	}
+	return d
+}
+
+// checkWritable makes sure that the given directory exists and is writable.
+func checkWritable(dir string) error {
+	if err := common.Mkdir(dir); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, ".writable-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
 // SetNoCleanup enables/disables the removal of the Dir on Cleanup.
 func (d *Dir) SetNoCleanup(v bool) {
 	d.noCleanup = v
+	if d.childDir != nil {
+		d.childDir.SetNoCleanup(v)
+	}
 }
 
 // NewSubDir creates a new subdirectory under a Dir, and returns the
@@ -96,6 +149,9 @@ func (d *Dir) NewLogWriter(name string) (io.WriteCloser, error) {
 
 // Cleanup cleans up the Dir.
 func (d *Dir) Cleanup() {
+	if d.childDir != nil {
+		d.childDir.Cleanup()
+	}
 	if d.dir == "" || d.noCleanup {
 		return
 	}
@@ -117,6 +173,7 @@ func init() {
 	Flags.String(cfgBaseDir, "", "test base directory")
 	Flags.Bool(cfgBaseDirNoCleanup, false, "do not cleanup test base directory")
 	Flags.Bool(cfgBaseDirNoTempDir, false, "do not create a temp directory inside base directory")
+	Flags.String(cfgBaseDirDataDir, "", "scenario data directory (default: test base directory)")
 
 	_ = viper.BindPFlags(Flags)
 }
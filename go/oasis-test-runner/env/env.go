@@ -5,10 +5,12 @@ import (
 	"container/list"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os/exec"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -49,6 +51,21 @@ type ScenarioInstanceInfo struct {
 
 	// Run is the number of the run.
 	Run int `json:"run"`
+
+	// BasePort is the first port of the port range reserved for the scenario
+	// instance.
+	BasePort uint16 `json:"base_port,omitempty"`
+
+	// MetricsLabels are additional scenario-specific metrics labels.
+	MetricsLabels map[string]string `json:"metrics_labels,omitempty"`
+
+	// PeakRSSBytes is the peak total resident set size of all network nodes
+	// sampled while the scenario was running.
+	PeakRSSBytes uint64 `json:"peak_rss_bytes,omitempty"`
+
+	// GenesisFile is the path to the genesis document used by the scenario
+	// network, if any.
+	GenesisFile string `json:"genesis_file,omitempty"`
 }
 
 // MarshalJSON outputs ParameterFlagSet as an ordinary JSON map.
@@ -70,16 +87,73 @@ func (pfs *ParameterFlagSet) Clone() *ParameterFlagSet {
 	pfs.VisitAll(func(f *flag.Flag) {
 		// Clone concrete members of Flag.
 		fl := *f
-		// Clone Value interface.
-		fl.Value = reflect.New(reflect.TypeOf(fl.Value).Elem()).Interface().(flag.Value)
-		// And actual flag value.
-		_ = fl.Value.Set(f.Value.String())
+		switch v := f.Value.(type) {
+		case *enumValue:
+			// Enum values also need their set of allowed values.
+			fl.Value = v.clone()
+		default:
+			// Clone Value interface.
+			fl.Value = reflect.New(reflect.TypeOf(fl.Value).Elem()).Interface().(flag.Value)
+			// And actual flag value.
+			_ = fl.Value.Set(f.Value.String())
+		}
 		newPfs.AddFlag(&fl)
 	})
 
 	return newPfs
 }
 
+// Enum defines a string parameter with the given name, default value and usage string, which
+// only accepts one of the given allowed values.
+//
+// Setting the parameter to any other value fails with an error listing all valid options, so
+// typos are caught when the scenario parameters are parsed. The value can be retrieved using
+// GetString.
+func (pfs *ParameterFlagSet) Enum(name, value string, allowed []string, usage string) {
+	v := &enumValue{allowed: allowed}
+	if err := v.Set(value); err != nil {
+		panic(fmt.Sprintf("env: bad default for enum parameter '%s': %s", name, err))
+	}
+	pfs.Var(v, name, usage)
+}
+
+// enumValue is a string flag value restricted to a set of allowed values.
+type enumValue struct {
+	value   string
+	allowed []string
+}
+
+func (v *enumValue) String() string {
+	return v.value
+}
+
+func (v *enumValue) Set(s string) error {
+	for _, a := range v.allowed {
+		if s == a {
+			v.value = s
+			return nil
+		}
+	}
+
+	options := make([]string, 0, len(v.allowed))
+	for _, a := range v.allowed {
+		options = append(options, fmt.Sprintf("%q", a))
+	}
+	return fmt.Errorf("invalid value %q (valid options: %s)", s, strings.Join(options, ", "))
+}
+
+// Type returns "string" so that enum parameters can be retrieved using GetString.
+func (v *enumValue) Type() string {
+	return "string"
+}
+
+func (v *enumValue) clone() *enumValue {
+	return &enumValue{
+		value:   v.value,
+		allowed: append([]string{}, v.allowed...),
+	}
+}
+
 // NewParameterFlagSet returns new instance of ParameterFlagSet.
 func NewParameterFlagSet(name string, eh flag.ErrorHandling) *ParameterFlagSet {
 	return &ParameterFlagSet{
@@ -103,6 +177,8 @@ type Env struct {
 	cleanupCmds  []*cmdMonitor
 	cleanupLock  sync.Mutex
 
+	scenarioCleanupFns []CleanupFn
+
 	isInCleanup bool
 }
 
@@ -141,6 +217,48 @@ func (env *Env) AddOnCleanup(fn CleanupFn) {
 	env.cleanupFns = append([]CleanupFn{fn}, env.cleanupFns...)
 }
 
+// RegisterCleanup registers a scenario-specific cleanup routine (e.g., for
+// killing an external tool spawned by the scenario).
+//
+// As opposed to AddOnCleanup routines, these are run by the test runner as
+// soon as the scenario finishes, even in case it panics, and before the
+// environment is cleaned up. Routines will be called in reverse order that
+// they were registered.
+func (env *Env) RegisterCleanup(fn CleanupFn) {
+	env.cleanupLock.Lock()
+	defer env.cleanupLock.Unlock()
+
+	env.scenarioCleanupFns = append([]CleanupFn{fn}, env.scenarioCleanupFns...)
+}
+
+// RunScenarioCleanups runs all scenario-specific cleanup routines registered
+// via RegisterCleanup and forgets them.
+//
+// A panicking routine does not prevent the remaining routines from running.
+// In case any routine panics, an error describing the panic is returned.
+func (env *Env) RunScenarioCleanups() error {
+	env.cleanupLock.Lock()
+	fns := env.scenarioCleanupFns
+	env.scenarioCleanupFns = nil
+	env.cleanupLock.Unlock()
+
+	var panics []string
+	for _, fn := range fns {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					panics = append(panics, fmt.Sprintf("%v", r))
+				}
+			}()
+			fn()
+		}()
+	}
+	if len(panics) > 0 {
+		return fmt.Errorf("env: panic caught in cleanup routine(s): %s", strings.Join(panics, "; "))
+	}
+	return nil
+}
+
 // AddTermOnCleanup adds a process that will be terminated during the
 // environment's cleanup, and will return a channel that will be
 // closed (after an error is sent if applicable when the process
@@ -191,6 +309,9 @@ func (env *Env) Cleanup() {
 		child.Cleanup()
 	}
 
+	// Run any scenario-specific cleanup routines that have not been run yet.
+	_ = env.RunScenarioCleanups()
+
 	// Tear down this environment's commands.
 	for _, v := range env.cleanupCmds {
 		v.termOrKill()
@@ -216,7 +337,7 @@ func (env *Env) NewChild(childName string, scInfo *ScenarioInstanceInfo) (*Env,
 		parentDir = env.dir
 	}
 
-	subDir, err := parentDir.NewSubDir(childName)
+	subDir, err := parentDir.ChildDir().NewSubDir(childName)
 	if err != nil {
 		return nil, err
 	}
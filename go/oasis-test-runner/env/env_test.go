@@ -2,9 +2,15 @@ package env
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 )
 
@@ -57,6 +63,36 @@ func TestParameterFlagSet_Clone(t *testing.T) {
 	require.Equal(t, 43, fs2f3)
 }
 
+func TestParameterFlagSet_Enum(t *testing.T) {
+	require := require.New(t)
+
+	fs1 := NewParameterFlagSet("fs1", pflag.ContinueOnError)
+	fs1.Enum("flag1", "a", []string{"a", "b"}, "usage1")
+	v, err := fs1.GetString("flag1")
+	require.NoError(err, "GetString")
+	require.Equal("a", v)
+
+	require.NoError(fs1.Set("flag1", "b"))
+	err = fs1.Set("flag1", "c")
+	require.Error(err, "Set should fail with a value that is not allowed")
+	require.Contains(err.Error(), `valid options: "a", "b"`)
+	v, _ = fs1.GetString("flag1")
+	require.Equal("b", v, "failed Set should not change the value")
+
+	// Cloned flagsets should keep the allowed values.
+	fs2 := fs1.Clone()
+	v, _ = fs2.GetString("flag1")
+	require.Equal("b", v)
+	require.NoError(fs2.Set("flag1", "a"))
+	require.Error(fs2.Set("flag1", "c"), "Set on a clone should fail with a value that is not allowed")
+	v, _ = fs1.GetString("flag1")
+	require.Equal("b", v, "changing the clone should leave the original intact")
+
+	require.Panics(func() {
+		fs1.Enum("flag2", "c", []string{"a", "b"}, "usage2")
+	}, "Enum should panic with a default value that is not allowed")
+}
+
 func TestParameterFlagSet_MarshalJSON(t *testing.T) {
 	fs := NewParameterFlagSet("fs", pflag.ExitOnError)
 	fs.String("flag1", "defaultvalue1", "usage1")
@@ -73,3 +109,62 @@ func TestParameterFlagSet_MarshalJSON(t *testing.T) {
 	require.Equal(t, "value1", fsNew["flag1"])
 	require.Equal(t, "defaultvalue2", fsNew["flag2"])
 }
+
+func TestDirSeparateDataDir(t *testing.T) {
+	require := require.New(t)
+
+	baseDir, err := ioutil.TempDir("", "oasis-test-runner-env-base")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(baseDir)
+	dataDir := filepath.Join(baseDir, "data")
+
+	viper.Set(cfgBaseDir, baseDir)
+	defer viper.Set(cfgBaseDir, "")
+	viper.Set(cfgBaseDirDataDir, dataDir)
+	defer viper.Set(cfgBaseDirDataDir, "")
+
+	var d Dir
+	err = d.Init(&cobra.Command{Use: "env-test"})
+	require.NoError(err, "Init")
+
+	// Child environments should be created under the data directory.
+	root := New(&d)
+	child, err := root.NewChild("child", &ScenarioInstanceInfo{})
+	require.NoError(err, "NewChild")
+	require.True(strings.HasPrefix(child.Dir(), dataDir), "child environment should be under the data directory")
+	require.False(strings.HasPrefix(root.Dir(), dataDir), "root environment should not be under the data directory")
+
+	// Cleanup should remove both directories.
+	root.Cleanup()
+	_, err = os.Stat(child.Dir())
+	require.True(os.IsNotExist(err), "child environment directory should be removed")
+
+	// An unusable data directory should be rejected upfront.
+	notDir := filepath.Join(baseDir, "file")
+	require.NoError(ioutil.WriteFile(notDir, []byte{}, 0o600))
+	viper.Set(cfgBaseDirDataDir, notDir)
+
+	var d2 Dir
+	err = d2.Init(&cobra.Command{Use: "env-test"})
+	require.Error(err, "Init should fail with an unusable data directory")
+}
+
+func TestRunScenarioCleanups(t *testing.T) {
+	require := require.New(t)
+
+	e := New(nil)
+
+	var order []int
+	e.RegisterCleanup(func() { order = append(order, 1) })
+	e.RegisterCleanup(func() { panic("cleanup bug") })
+	e.RegisterCleanup(func() { order = append(order, 3) })
+
+	err := e.RunScenarioCleanups()
+	require.Error(err, "RunScenarioCleanups should report panics")
+	require.Contains(err.Error(), "cleanup bug")
+	require.Equal([]int{3, 1}, order, "routines should run in reverse order despite panics")
+
+	// Routines should only be run once.
+	require.NoError(e.RunScenarioCleanups())
+	require.Equal([]int{3, 1}, order)
+}
@@ -87,7 +87,9 @@ func (net *Network) NewClient(cfg *ClientCfg) (*Client, error) {
 	}
 	client.doStartNode = client.startNode
 
+	net.nodesLock.Lock()
 	net.clients = append(net.clients, client)
+	net.nodesLock.Unlock()
 	net.nextNodePort += 2
 
 	return client, nil
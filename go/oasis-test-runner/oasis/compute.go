@@ -172,7 +172,9 @@ func (net *Network) NewCompute(cfg *ComputeCfg) (*Compute, error) {
 	worker.doStartNode = worker.startNode
 	copy(worker.NodeID[:], nodeKey[:])
 
+	net.nodesLock.Lock()
 	net.computeWorkers = append(net.computeWorkers, worker)
+	net.nodesLock.Unlock()
 	net.nextNodePort += 3
 
 	if err := net.AddLogWatcher(&worker.Node); err != nil {
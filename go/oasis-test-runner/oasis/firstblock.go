@@ -0,0 +1,55 @@
+package oasis
+
+import (
+	"context"
+	"time"
+
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+)
+
+// firstBlockPollInterval is the interval at which the network is polled for the first committed
+// consensus block.
+const firstBlockPollInterval = 100 * time.Millisecond
+
+// watchFirstBlock polls the given controller until the first consensus block is committed and
+// records the time elapsed since the network was created.
+func (net *Network) watchFirstBlock(ctx context.Context, ctrl *Controller) {
+	ticker := time.NewTicker(firstBlockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Errors are expected until the node has started and committed a block.
+		blk, err := ctrl.Consensus.GetBlock(ctx, consensus.HeightLatest)
+		if err == nil && blk.Height > 0 {
+			ttfb := time.Since(net.createdAt)
+
+			net.firstBlockLock.Lock()
+			net.timeToFirstBlock = ttfb
+			net.hasFirstBlockTime = true
+			net.firstBlockLock.Unlock()
+
+			net.logger.Info("first consensus block committed",
+				"height", blk.Height,
+				"time_to_first_block", ttfb,
+			)
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// TimeToFirstBlock returns the time elapsed between the creation of the network and the first
+// committed consensus block observed after the network was started.
+//
+// The second return value is false if no committed block has been observed yet.
+func (net *Network) TimeToFirstBlock() (time.Duration, bool) {
+	net.firstBlockLock.Lock()
+	defer net.firstBlockLock.Unlock()
+
+	return net.timeToFirstBlock, net.hasFirstBlockTime
+}
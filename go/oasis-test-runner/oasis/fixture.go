@@ -1,10 +1,14 @@
 package oasis
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"regexp"
 	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx"
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
@@ -32,6 +36,68 @@ type NetworkFixture struct {
 	ByzantineNodes     []ByzantineFixture        `json:"byzantine_nodes,omitempty"`
 }
 
+var envVarRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces all ${VAR} references in the given string with the
+// values of the corresponding environment variables.
+func expandEnv(s string) (string, error) {
+	var err error
+	expanded := envVarRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envVarRegexp.FindStringSubmatch(ref)[1]
+		v, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("oasis: environment variable %s referenced in fixture is not set", name)
+		}
+		return v
+	})
+	return expanded, err
+}
+
+// ExpandEnv replaces ${VAR} references with values of the corresponding
+// environment variables. It fails if any referenced variable is not set.
+//
+// Substitution is supported in the following fields:
+//
+//   - network.genesis_file
+//   - network.node_binary
+//   - network.runtime_loader_binary
+//   - runtimes[].binaries
+//   - runtimes[].genesis_state_path
+func (f *NetworkFixture) ExpandEnv() error {
+	fields := []*string{
+		&f.Network.GenesisFile,
+		&f.Network.NodeBinary,
+		&f.Network.RuntimeSGXLoaderBinary,
+	}
+	for i := range f.Runtimes {
+		for j := range f.Runtimes[i].Binaries {
+			fields = append(fields, &f.Runtimes[i].Binaries[j])
+		}
+		fields = append(fields, &f.Runtimes[i].GenesisStatePath)
+	}
+
+	for _, field := range fields {
+		expanded, err := expandEnv(*field)
+		if err != nil {
+			return err
+		}
+		*field = expanded
+	}
+	return nil
+}
+
+// Fingerprint returns a hash of the serialized fixture.
+//
+// Any change to the serializable part of the fixture results in a different
+// fingerprint.
+func (f *NetworkFixture) Fingerprint() (hash.Hash, error) {
+	b, err := json.Marshal(f)
+	if err != nil {
+		return hash.Hash{}, fmt.Errorf("oasis: failed to serialize fixture: %w", err)
+	}
+	return hash.NewFromBytes(b), nil
+}
+
 // Create instantiates the network described by the fixture.
 func (f *NetworkFixture) Create(env *env.Env) (*Network, error) {
 	// Use default MRSIGNER if not provided.
@@ -362,7 +362,9 @@ func (net *Network) NewKeymanager(cfg *KeymanagerCfg) (*Keymanager, error) {
 	km.doStartNode = km.startNode
 	copy(km.NodeID[:], nodeKey[:])
 
+	net.nodesLock.Lock()
 	net.keymanagers = append(net.keymanagers, km)
+	net.nodesLock.Unlock()
 	net.nextNodePort += 2
 
 	if err := net.AddLogWatcher(&km.Node); err != nil {
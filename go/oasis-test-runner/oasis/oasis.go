@@ -45,6 +45,11 @@ import (
 const (
 	baseNodePort = 20000
 
+	// nodePortRangeSize is the number of ports reserved for each network
+	// instance when allocating disjoint port ranges.
+	nodePortRangeSize = 200
+	maxNodePort       = 65535
+
 	validatorStartDelay = 3 * time.Second
 
 	defaultConsensusBackend            = "tendermint"
@@ -133,20 +138,24 @@ func (n *Node) LoadIdentity() (*identity.Identity, error) {
 }
 
 func (n *Node) stopNode() error {
-	if n.cmd == nil {
-		return nil
-	}
-
 	// Mark the node as stopping so that we don't abort the scenario when the node exits.
 	n.Lock()
+	cmd, exitCh := n.cmd, n.exitCh
+	if cmd == nil {
+		n.Unlock()
+		return nil
+	}
 	n.isStopping = true
 	n.Unlock()
 
 	// Stop the node and wait for it to stop.
-	_ = n.cmd.Process.Kill()
-	_ = n.cmd.Wait()
-	<-n.Exit()
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	<-exitCh
+
+	n.Lock()
 	n.cmd = nil
+	n.Unlock()
 
 	return nil
 }
@@ -178,6 +187,9 @@ func (n *Node) RestartAfter(ctx context.Context, startDelay time.Duration) error
 // if the node isn't running yet. This can be used as a replacement for NetworkCfg.NodeBinary
 // in cases where the test runner is actually using a wrapper to start the node.
 func (n *Node) BinaryPath() string {
+	n.Lock()
+	defer n.Unlock()
+
 	if n.cmd == nil || n.cmd.Process == nil {
 		return ""
 	}
@@ -185,6 +197,19 @@ func (n *Node) BinaryPath() string {
 	return fmt.Sprintf("/proc/%d/exe", n.cmd.Process.Pid)
 }
 
+// Pid returns the process ID of the running node, or 0 if the node isn't
+// running.
+func (n *Node) Pid() int {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.cmd == nil || n.cmd.Process == nil {
+		return 0
+	}
+
+	return n.cmd.Process.Pid
+}
+
 // WaitReady is a helper for creating a controller and calling node's WaitReady.
 func (n *Node) WaitReady(ctx context.Context) error {
 	nodeCtrl, err := NewController(n.SocketPath())
@@ -255,6 +280,10 @@ type Network struct {
 	env     *env.Env
 	baseDir *env.Dir
 
+	// nodesLock protects the node slices read by Nodes, which may be called concurrently with
+	// the scenario adding nodes.
+	nodesLock sync.RWMutex
+
 	entities       []*Entity
 	validators     []*Validator
 	runtimes       []*Runtime
@@ -278,6 +307,12 @@ type Network struct {
 	controller       *Controller
 	clientController *Controller
 
+	createdAt         time.Time
+	firstBlockOnce    sync.Once
+	firstBlockLock    sync.Mutex
+	timeToFirstBlock  time.Duration
+	hasFirstBlockTime bool
+
 	errCh chan error
 }
 
@@ -299,6 +334,10 @@ type NetworkCfg struct { // nolint: maligned
 	// NodeBinary is the path to the Oasis node binary.
 	NodeBinary string `json:"node_binary"`
 
+	// StorageBackend is the storage backend used by all storage nodes. If set, it overrides the
+	// backend configured for individual storage nodes.
+	StorageBackend string `json:"storage_backend,omitempty"`
+
 	// RuntimeSGXLoaderBinary is the path to the Oasis SGX runtime loader.
 	RuntimeSGXLoaderBinary string `json:"runtime_loader_binary"`
 
@@ -399,20 +438,23 @@ func (net *Network) Byzantine() []*Byzantine {
 //
 // Seed, sentry, byzantine and IAS proxy nodes are omitted.
 func (net *Network) Nodes() []*Node {
+	net.nodesLock.RLock()
+	defer net.nodesLock.RUnlock()
+
 	var nodes []*Node
-	for _, v := range net.Validators() {
+	for _, v := range net.validators {
 		nodes = append(nodes, &v.Node)
 	}
-	for _, s := range net.StorageWorkers() {
+	for _, s := range net.storageWorkers {
 		nodes = append(nodes, &s.Node)
 	}
-	for _, c := range net.ComputeWorkers() {
+	for _, c := range net.computeWorkers {
 		nodes = append(nodes, &c.Node)
 	}
-	for _, k := range net.Keymanagers() {
+	for _, k := range net.keymanagers {
 		nodes = append(nodes, &k.Node)
 	}
-	for _, v := range net.Clients() {
+	for _, v := range net.clients {
 		nodes = append(nodes, &v.Node)
 	}
 	return nodes
@@ -498,6 +540,19 @@ func (net *Network) CheckLogWatchers() (err error) {
 func (net *Network) Start() error { // nolint: gocyclo
 	net.logger.Info("starting network")
 
+	nodeBinary, nodeVersion, err := resolveNodeBinary(net.cfg.NodeBinary)
+	if err != nil {
+		net.logger.Error("failed to resolve node binary",
+			"err", err,
+			"node_binary", net.cfg.NodeBinary,
+		)
+		return err
+	}
+	net.logger.Info("using node binary",
+		"path", nodeBinary,
+		"version", nodeVersion,
+	)
+
 	// Figure out if the IAS proxy is needed by peeking at all the
 	// runtimes.
 	for _, v := range net.Runtimes() {
@@ -527,6 +582,13 @@ func (net *Network) Start() error { // nolint: gocyclo
 		)
 	}
 
+	net.logger.Info("using genesis document",
+		"path", net.GenesisPath(),
+	)
+	if scInfo := net.env.ScenarioInfo(); scInfo != nil {
+		scInfo.GenesisFile = net.GenesisPath()
+	}
+
 	// Retrieve the genesis document and use it to configure the context for
 	// signature domain separation.
 	genesisProvider, err := genesisFile.NewFileProvider(net.GenesisPath())
@@ -710,6 +772,14 @@ func (net *Network) Start() error { // nolint: gocyclo
 		break
 	}
 
+	if net.controller != nil {
+		net.firstBlockOnce.Do(func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			net.env.AddOnCleanup(env.CleanupFn(cancel))
+			go net.watchFirstBlock(ctx, net.controller)
+		})
+	}
+
 	net.logger.Info("network started")
 
 	return nil
@@ -720,6 +790,31 @@ func (net *Network) Stop() {
 	net.env.Cleanup()
 }
 
+// resolveNodeBinary resolves the given node binary (a path or a name resolved via PATH) and
+// returns the path of the binary together with its reported version.
+//
+// In case the version cannot be determined (e.g., for a wrapper script), an empty version is
+// returned. Only a missing or non-executable binary is an error.
+func resolveNodeBinary(binary string) (string, string, error) {
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return "", "", fmt.Errorf("oasis: missing node binary %s: %w", binary, err)
+	}
+
+	out, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return path, "", nil
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return path, strings.Join(lines, ", "), nil
+}
+
 func (net *Network) runNodeBinary(consoleWriter io.Writer, args ...string) error {
 	nodeBinary := net.cfg.NodeBinary
 	cmd := exec.Command(nodeBinary, args...)
@@ -1067,16 +1162,34 @@ func New(env *env.Env, cfg *NetworkCfg) (*Network, error) {
 		cfgCopy.HaltEpoch = defaultHaltEpoch
 	}
 
+	// Use the port range assigned to the scenario instance, if any.
+	nextNodePort := uint16(baseNodePort)
+	if scInfo := env.ScenarioInfo(); scInfo != nil && scInfo.BasePort != 0 {
+		nextNodePort = scInfo.BasePort
+	}
+
 	return &Network{
 		logger:       logging.GetLogger("oasis/" + env.Name()),
 		env:          env,
 		baseDir:      baseDir,
 		cfg:          &cfgCopy,
-		nextNodePort: baseNodePort,
+		nextNodePort: nextNodePort,
+		createdAt:    time.Now(),
 		errCh:        make(chan error, maxNodes),
 	}, nil
 }
 
+// NodePortRange returns the first port of the port range reserved for the
+// network instance with the given (global) index.
+//
+// Port ranges of instances with different indices are disjoint, unless the
+// number of instances exceeds the number of available ranges in which case
+// the ranges are reused in a round-robin fashion.
+func NodePortRange(index int) uint16 {
+	numRanges := (maxNodePort - baseNodePort) / nodePortRangeSize
+	return uint16(baseNodePort + (index%numRanges)*nodePortRangeSize)
+}
+
 func nodeLogPath(dir *env.Dir) string {
 	return filepath.Join(dir.String(), logNodeFile)
 }
@@ -4,6 +4,12 @@ import (
 	"bytes"
 	"crypto"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
 	"testing"
 
 	"github.com/oasisprotocol/ed25519"
@@ -44,3 +50,131 @@ func TestNodeIdentity(t *testing.T) {
 	require.Equal(t, 1, bytes.Compare(c1, b2))
 	require.Equal(t, 1, bytes.Compare(b3, c1))
 }
+
+func TestNetworkFixtureFingerprint(t *testing.T) {
+	f := &NetworkFixture{
+		Network: NetworkCfg{
+			NodeBinary:              "oasis-node",
+			DeterministicIdentities: true,
+		},
+		Entities:   []EntityCfg{{IsDebugTestEntity: true}, {}},
+		Validators: []ValidatorFixture{{Entity: 1}},
+	}
+	fp1, err := f.Fingerprint()
+	require.NoError(t, err, "Fingerprint")
+	fp2, err := f.Fingerprint()
+	require.NoError(t, err, "Fingerprint")
+	require.EqualValues(t, fp1, fp2, "fingerprint should be deterministic")
+
+	f.Validators = append(f.Validators, ValidatorFixture{Entity: 1})
+	fp3, err := f.Fingerprint()
+	require.NoError(t, err, "Fingerprint")
+	require.NotEqualValues(t, fp1, fp3, "fixture mutation should change the fingerprint")
+}
+
+func TestNodePortRange(t *testing.T) {
+	require.EqualValues(t, baseNodePort, NodePortRange(0))
+	require.EqualValues(t, baseNodePort+nodePortRangeSize, NodePortRange(1))
+
+	numRanges := (maxNodePort - baseNodePort) / nodePortRangeSize
+	last := NodePortRange(numRanges - 1)
+	require.True(t, int(last)+nodePortRangeSize-1 <= maxNodePort, "last range should fit")
+	require.EqualValues(t, NodePortRange(0), NodePortRange(numRanges), "ranges should wrap around")
+}
+
+func TestNetworkFixtureExpandEnv(t *testing.T) {
+	require.NoError(t, os.Setenv("OASIS_TEST_FIXTURE_DIR", "/opt/oasis"))
+	defer os.Unsetenv("OASIS_TEST_FIXTURE_DIR")
+
+	f := &NetworkFixture{
+		Network: NetworkCfg{
+			NodeBinary: "${OASIS_TEST_FIXTURE_DIR}/oasis-node",
+		},
+		Runtimes: []RuntimeFixture{
+			{Binaries: []string{"${OASIS_TEST_FIXTURE_DIR}/runtime", "runtime.sgxs"}},
+		},
+	}
+	require.NoError(t, f.ExpandEnv(), "ExpandEnv")
+	require.Equal(t, "/opt/oasis/oasis-node", f.Network.NodeBinary)
+	require.Equal(t, []string{"/opt/oasis/runtime", "runtime.sgxs"}, f.Runtimes[0].Binaries)
+
+	f.Network.GenesisFile = "${OASIS_TEST_FIXTURE_UNSET}/genesis.json"
+	require.Error(t, f.ExpandEnv(), "ExpandEnv should fail on unset variables")
+}
+
+func TestResolveNodeBinary(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-test-runner-node-binary")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	_, _, err = resolveNodeBinary(filepath.Join(dir, "missing-oasis-node"))
+	require.Error(err, "resolveNodeBinary should fail for a missing binary")
+
+	binary := filepath.Join(dir, "oasis-node")
+	script := "#!/bin/sh\necho 'Software version: 1.2.3'\necho\necho 'Consensus protocol: 4.0.0'\n"
+	err = ioutil.WriteFile(binary, []byte(script), 0o700)
+	require.NoError(err, "WriteFile")
+
+	path, version, err := resolveNodeBinary(binary)
+	require.NoError(err, "resolveNodeBinary")
+	require.Equal(binary, path)
+	require.Equal("Software version: 1.2.3, Consensus protocol: 4.0.0", version)
+}
+
+func TestNodePidConcurrentRestart(t *testing.T) {
+	require := require.New(t)
+
+	val := &Validator{}
+	net := &Network{validators: []*Validator{val}}
+	node := &val.Node
+
+	start := func() {
+		cmd := exec.Command("sleep", "60")
+		require.NoError(cmd.Start(), "Start")
+
+		exitCh := make(chan error)
+		close(exitCh)
+
+		node.Lock()
+		node.cmd = cmd
+		node.exitCh = exitCh
+		node.Unlock()
+	}
+
+	// Sample the process IDs of all nodes while the node is being restarted, the same way as
+	// the test runner's resident set size sampler does.
+	var samples uint64
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		for {
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+			for _, n := range net.Nodes() {
+				_ = n.Pid()
+			}
+			atomic.AddUint64(&samples, 1)
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		// Make sure that sampling actually overlaps with the restarts.
+		for s := atomic.LoadUint64(&samples); atomic.LoadUint64(&samples) == s; {
+			runtime.Gosched()
+		}
+
+		start()
+		require.NotZero(node.Pid(), "Pid should be set for a running node")
+		require.NoError(node.Stop(), "Stop")
+		require.Zero(node.Pid(), "Pid should be zero for a stopped node")
+	}
+
+	close(stopCh)
+	<-doneCh
+}
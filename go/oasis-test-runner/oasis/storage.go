@@ -187,6 +187,11 @@ func (net *Network) NewStorage(cfg *StorageCfg) (*Storage, error) {
 		return nil, fmt.Errorf("oasis/storage: sentry client public key unmarshal failure: %w", err)
 	}
 
+	backend := cfg.Backend
+	if net.cfg.StorageBackend != "" {
+		backend = net.cfg.StorageBackend
+	}
+
 	worker := &Storage{
 		Node: Node{
 			Name:                                     storageName,
@@ -200,7 +205,7 @@ func (net *Network) NewStorage(cfg *StorageCfg) (*Storage, error) {
 			logWatcherHandlerFactories:               cfg.LogWatcherHandlerFactories,
 			consensus:                                cfg.Consensus,
 		},
-		backend:                 cfg.Backend,
+		backend:                 backend,
 		entity:                  cfg.Entity,
 		sentryIndices:           cfg.SentryIndices,
 		disableCertRotation:     cfg.DisableCertRotation,
@@ -217,7 +222,9 @@ func (net *Network) NewStorage(cfg *StorageCfg) (*Storage, error) {
 	worker.doStartNode = worker.startNode
 	copy(worker.NodeID[:], nodeKey[:])
 
+	net.nodesLock.Lock()
 	net.storageWorkers = append(net.storageWorkers, worker)
+	net.nodesLock.Unlock()
 	net.nextNodePort += 3
 
 	if err := net.AddLogWatcher(&worker.Node); err != nil {
@@ -214,7 +214,9 @@ func (net *Network) NewValidator(cfg *ValidatorCfg) (*Validator, error) {
 		return nil, fmt.Errorf("oasis/validator: failed to provision validator: %w", err)
 	}
 
+	net.nodesLock.Lock()
 	net.validators = append(net.validators, val)
+	net.nodesLock.Unlock()
 	net.nextNodePort += 2
 
 	if err := net.AddLogWatcher(&val.Node); err != nil {
@@ -22,11 +22,14 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis/cli"
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/scenario"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
 )
 
 const (
 	// cfgNodeBinary is the path to oasis-node executable.
 	cfgNodeBinary = "node.binary"
+	// cfgStorageBackend is the storage backend used by storage nodes.
+	cfgStorageBackend = "storage_backend"
 )
 
 // E2eParamsDummy is a dummy instance of E2E used to register global e2e flags.
@@ -55,6 +58,7 @@ func NewE2E(name string) *E2E {
 		Flags:  env.NewParameterFlagSet(fullName, flag.ContinueOnError),
 	}
 	sc.Flags.String(cfgNodeBinary, "oasis-node", "path to the node binary")
+	sc.Flags.Enum(cfgStorageBackend, database.BackendNameBadgerDB, database.BackendNames, "storage backend used by storage nodes")
 
 	return sc
 }
@@ -84,13 +88,21 @@ func (sc *E2E) PreInit(childEnv *env.Env) error {
 	return nil
 }
 
+// Implements scenario.BinaryRequirer.
+func (sc *E2E) RequiredBinaries() []string {
+	nodeBinary, _ := sc.Flags.GetString(cfgNodeBinary)
+	return []string{nodeBinary}
+}
+
 // Implements scenario.Scenario.
 func (sc *E2E) Fixture() (*oasis.NetworkFixture, error) {
 	nodeBinary, _ := sc.Flags.GetString(cfgNodeBinary)
+	storageBackend, _ := sc.Flags.GetString(cfgStorageBackend)
 
 	return &oasis.NetworkFixture{
 		Network: oasis.NetworkCfg{
-			NodeBinary: nodeBinary,
+			NodeBinary:     nodeBinary,
+			StorageBackend: storageBackend,
 			Consensus: consensusGenesis.Genesis{
 				Parameters: consensusGenesis.Parameters{
 					GasCosts: transaction.Costs{
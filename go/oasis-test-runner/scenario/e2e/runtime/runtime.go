@@ -79,7 +79,7 @@ func newRuntimeImpl(name, clientBinary string, clientArgs []string) *runtimeImpl
 	sc.Flags.String(cfgClientBinaryDir, "", "path to the client binaries directory")
 	sc.Flags.String(cfgRuntimeBinaryDir, "", "path to the runtime binaries directory")
 	sc.Flags.String(cfgRuntimeLoader, "oasis-core-runtime-loader", "path to the runtime loader")
-	sc.Flags.String(cfgTEEHardware, "", "TEE hardware to use")
+	sc.Flags.Enum(cfgTEEHardware, "", []string{"", node.TEEHardwareIntelSGX.String()}, "TEE hardware to use")
 	sc.Flags.Bool(cfgIasMock, true, "if mock IAS service should be used")
 	sc.Flags.Int64(cfgEpochInterval, 0, "epoch interval")
 
@@ -98,6 +98,18 @@ func (sc *runtimeImpl) PreInit(childEnv *env.Env) error {
 	return nil
 }
 
+func (sc *runtimeImpl) RequiredBinaries() []string {
+	binaries := sc.E2E.RequiredBinaries()
+	if tee, err := sc.getTEEHardware(); err == nil && tee == node.TEEHardwareIntelSGX {
+		runtimeLoader, _ := sc.Flags.GetString(cfgRuntimeLoader)
+		binaries = append(binaries, runtimeLoader)
+	}
+	if sc.clientBinary != "" {
+		binaries = append(binaries, sc.resolveClientBinary(sc.clientBinary))
+	}
+	return binaries
+}
+
 func (sc *runtimeImpl) Fixture() (*oasis.NetworkFixture, error) {
 	f, err := sc.E2E.Fixture()
 	if err != nil {
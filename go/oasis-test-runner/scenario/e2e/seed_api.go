@@ -86,6 +86,12 @@ func (sc *seedAPI) Run(childEnv *env.Env) error { // nolint: gocyclo
 	if status.Consensus.IsValidator {
 		return fmt.Errorf("seed node reports itself to be a validator")
 	}
+	if status.Consensus.CheckTxDisabled {
+		return fmt.Errorf("seed node reports CheckTx as disabled")
+	}
+	if status.Consensus.P2PListenAddress == "" {
+		return fmt.Errorf("seed node does not report its P2P listen address")
+	}
 	if status.Consensus.Features.Has(consensusAPI.FeatureServices) {
 		return fmt.Errorf("seed node reports feature services")
 	}
@@ -120,12 +126,24 @@ func (sc *seedAPI) Run(childEnv *env.Env) error { // nolint: gocyclo
 		return fmt.Errorf("seed node SubmitTx should fail with unsupported")
 	}
 
+	sc.Logger.Info("testing SubmitTxForChain")
+	err = seedCtrl.Consensus.SubmitTxForChain(ctx, &transaction.SignedTransaction{}, doc.ChainID)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node SubmitTxForChain should fail with unsupported")
+	}
+
 	sc.Logger.Info("testing SubmitTxNoWait")
 	err = seedCtrl.Consensus.SubmitTxNoWait(ctx, &transaction.SignedTransaction{})
 	if err != consensusAPI.ErrUnsupported {
 		return fmt.Errorf("seed node SubmitTxNoWait should fail with unsupported")
 	}
 
+	sc.Logger.Info("testing CheckTx")
+	err = seedCtrl.Consensus.CheckTx(ctx, &transaction.SignedTransaction{})
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node CheckTx should fail with unsupported")
+	}
+
 	sc.Logger.Info("testing SubmitEvidence")
 	err = seedCtrl.Consensus.SubmitEvidence(ctx, &consensusAPI.Evidence{})
 	if err != consensusAPI.ErrUnsupported {
@@ -138,6 +156,12 @@ func (sc *seedAPI) Run(childEnv *env.Env) error { // nolint: gocyclo
 		return fmt.Errorf("seed node StateToGenesis should fail with unsupported")
 	}
 
+	sc.Logger.Info("testing ValidatorSetToGenesis")
+	_, err = seedCtrl.Consensus.ValidatorSetToGenesis(ctx, 0)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node ValidatorSetToGenesis should fail with unsupported")
+	}
+
 	sc.Logger.Info("testing EstimateGas")
 	_, err = seedCtrl.Consensus.EstimateGas(ctx, &consensusAPI.EstimateGasRequest{})
 	if err != consensusAPI.ErrUnsupported {
@@ -150,42 +174,124 @@ func (sc *seedAPI) Run(childEnv *env.Env) error { // nolint: gocyclo
 		return fmt.Errorf("seed node WaitEpoch should fail with unsupported")
 	}
 
+	sc.Logger.Info("testing WaitEpochBlock")
+	_, err = seedCtrl.Consensus.WaitEpochBlock(ctx, 0)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node WaitEpochBlock should fail with unsupported")
+	}
+
 	sc.Logger.Info("testing GetEpoch")
 	_, err = seedCtrl.Consensus.GetEpoch(ctx, 0)
 	if err != consensusAPI.ErrUnsupported {
 		return fmt.Errorf("seed node GetEpoch should fail with unsupported")
 	}
 
+	sc.Logger.Info("testing GetEpochProgress")
+	_, err = seedCtrl.Consensus.GetEpochProgress(ctx, 0)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node GetEpochProgress should fail with unsupported")
+	}
+
 	sc.Logger.Info("testing GetBlock")
 	_, err = seedCtrl.Consensus.GetBlock(ctx, consensusAPI.HeightLatest)
 	if err != consensusAPI.ErrUnsupported {
 		return fmt.Errorf("seed node GetBlock should fail with unsupported")
 	}
 
+	sc.Logger.Info("testing GetBlockSignatures")
+	_, err = seedCtrl.Consensus.GetBlockSignatures(ctx, consensusAPI.HeightLatest)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node GetBlockSignatures should fail with unsupported")
+	}
+
 	sc.Logger.Info("testing GetTransactions")
 	_, err = seedCtrl.Consensus.GetTransactions(ctx, consensusAPI.HeightLatest)
 	if err != consensusAPI.ErrUnsupported {
 		return fmt.Errorf("seed node GetTransactions should fail with unsupported")
 	}
 
+	sc.Logger.Info("testing GetTransactionsWithHashes")
+	_, err = seedCtrl.Consensus.GetTransactionsWithHashes(ctx, consensusAPI.HeightLatest)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node GetTransactionsWithHashes should fail with unsupported")
+	}
+
 	sc.Logger.Info("testing GetTransactionsWithResults")
 	_, err = seedCtrl.Consensus.GetTransactionsWithResults(ctx, consensusAPI.HeightLatest)
 	if err != consensusAPI.ErrUnsupported {
 		return fmt.Errorf("seed node GetTransactionsWithResults should fail with unsupported")
 	}
 
+	sc.Logger.Info("testing GetTransactionsWithResultsFiltered")
+	_, err = seedCtrl.Consensus.GetTransactionsWithResultsFiltered(ctx, &consensusAPI.GetTransactionsWithResultsRequest{
+		Height: consensusAPI.HeightLatest,
+	})
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node GetTransactionsWithResultsFiltered should fail with unsupported")
+	}
+
+	sc.Logger.Info("testing GetEventsAtHeight")
+	_, err = seedCtrl.Consensus.GetEventsAtHeight(ctx, consensusAPI.HeightLatest)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node GetEventsAtHeight should fail with unsupported")
+	}
+
+	sc.Logger.Info("testing GetDecodedBlockResults")
+	_, err = seedCtrl.Consensus.GetDecodedBlockResults(ctx, consensusAPI.HeightLatest)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node GetDecodedBlockResults should fail with unsupported")
+	}
+
+	sc.Logger.Info("testing GetBlockWithResults")
+	_, err = seedCtrl.Consensus.GetBlockWithResults(ctx, consensusAPI.HeightLatest)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node GetBlockWithResults should fail with unsupported")
+	}
+
+	sc.Logger.Info("testing StreamTransactionsWithResults")
+	txsCh, err := seedCtrl.Consensus.StreamTransactionsWithResults(ctx, 1, 1)
+	if err == nil {
+		// Errors are only reported once the stream is read.
+		txs, ok := <-txsCh
+		if !ok {
+			return fmt.Errorf("seed node StreamTransactionsWithResults stream closed without an error")
+		}
+		err = txs.Err
+	}
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node StreamTransactionsWithResults should fail with unsupported")
+	}
+
 	sc.Logger.Info("testing GetUnconfirmedTransactions")
 	_, err = seedCtrl.Consensus.GetUnconfirmedTransactions(ctx)
 	if err != consensusAPI.ErrUnsupported {
 		return fmt.Errorf("seed node GetUnconfirmedTransactions should fail with unsupported")
 	}
 
+	sc.Logger.Info("testing GetUnconfirmedTransactionsWithMeta")
+	_, err = seedCtrl.Consensus.GetUnconfirmedTransactionsWithMeta(ctx)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node GetUnconfirmedTransactionsWithMeta should fail with unsupported")
+	}
+
+	sc.Logger.Info("testing GetBlockIntervalStats")
+	_, err = seedCtrl.Consensus.GetBlockIntervalStats(ctx, 10)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node GetBlockIntervalStats should fail with unsupported")
+	}
+
 	sc.Logger.Info("testing GetSignerNonce")
 	_, err = seedCtrl.Consensus.GetSignerNonce(ctx, &consensusAPI.GetSignerNonceRequest{})
 	if err != consensusAPI.ErrUnsupported {
 		return fmt.Errorf("seed node GetSignerNonce should fail with unsupported")
 	}
 
+	sc.Logger.Info("testing GetSignerNonceAtHeight")
+	_, err = seedCtrl.Consensus.GetSignerNonceAtHeight(ctx, &consensusAPI.GetSignerNonceRequest{}, 1)
+	if err != consensusAPI.ErrUnsupported {
+		return fmt.Errorf("seed node GetSignerNonceAtHeight should fail with unsupported")
+	}
+
 	sc.Logger.Info("testing GetLightBlock")
 	_, err = seedCtrl.Consensus.GetLightBlock(ctx, consensusAPI.HeightLatest)
 	if err != consensusAPI.ErrUnsupported {
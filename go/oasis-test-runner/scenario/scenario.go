@@ -2,10 +2,16 @@
 package scenario
 
 import (
+	"errors"
+
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
 )
 
+// ErrPostRunAssertion is the error returned when a scenario ran successfully, but the assertions
+// on the final network state performed by PostRun failed.
+var ErrPostRunAssertion = errors.New("post-run assertion failed")
+
 // Scenario is a test scenario identified by name.
 type Scenario interface {
 	// Clone returns a copy of this scenario instance to be run in parallel.
@@ -39,3 +45,31 @@ type Scenario interface {
 	// Run runs the scenario.
 	Run(childEnv *env.Env) error
 }
+
+// MetricsLabeler is an optional interface implemented by scenarios which add
+// custom labels to the pushed metrics.
+type MetricsLabeler interface {
+	// MetricsLabels returns additional labels used when pushing metrics of the
+	// scenario (e.g., workload type or runtime ID), so that results can be
+	// compared based on them.
+	MetricsLabels() map[string]string
+}
+
+// BinaryRequirer is an optional interface implemented by scenarios which depend
+// on external binaries.
+type BinaryRequirer interface {
+	// RequiredBinaries returns the paths (or names resolved via PATH) of all
+	// binaries required by the scenario, so that missing binaries are reported
+	// before the scenario is set up.
+	RequiredBinaries() []string
+}
+
+// PostRunner is an optional interface implemented by scenarios which assert the final network
+// state after Run completes successfully.
+type PostRunner interface {
+	// PostRun performs assertions on the final network state (e.g., expected staking balances or
+	// registered entities).
+	//
+	// Network will be provided in case Fixture returned a non-nil value, otherwise it will be nil.
+	PostRun(childEnv *env.Env, net *oasis.Network) error
+}
@@ -267,3 +267,53 @@ func TestHistoryPruneError(t *testing.T) {
 		require.NoError(err, "GetBlock(%d)", i)
 	}
 }
+
+type testPruneDeferringHandler struct {
+}
+
+func (h *testPruneDeferringHandler) Prune(ctx context.Context, rounds []uint64) error {
+	return fmt.Errorf("%w: not yet", ErrPruneDeferred)
+}
+
+func TestHistoryPruneDeferred(t *testing.T) {
+	require := require.New(t)
+
+	// Create a new random temporary directory under /tmp.
+	dataDir, err := ioutil.TempDir("", "oasis-runtime-history-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history prune deferred test ns"), 0)
+
+	history, err := New(dataDir, runtimeID, &Config{
+		Pruner:        NewKeepLastPruner(10),
+		PruneInterval: time.Hour,
+	})
+	require.NoError(err, "New")
+	defer history.Close()
+
+	var ph testPruneDeferringHandler
+	history.Pruner().RegisterHandler(&ph)
+
+	// Create some blocks.
+	for i := 0; i <= 50; i++ {
+		blk := roothash.AnnotatedBlock{
+			Height: int64(i),
+			Block:  block.NewGenesisBlock(runtimeID, 0),
+		}
+		blk.Block.Header.Round = uint64(i)
+
+		err = history.Commit(&blk)
+		require.NoError(err, "Commit")
+	}
+
+	// A deferred prune is not an error.
+	err = history.Pruner().Prune(context.Background(), 50)
+	require.NoError(err, "Prune")
+
+	// Ensure nothing was pruned.
+	for i := 0; i <= 50; i++ {
+		_, err = history.GetBlock(context.Background(), uint64(i))
+		require.NoError(err, "GetBlock(%d)", i)
+	}
+}
@@ -2,6 +2,7 @@ package history
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -17,6 +18,10 @@ const (
 	PrunerStrategyKeepLast = "keep_last"
 )
 
+// ErrPruneDeferred can be returned by a PruneHandler to signal that the rounds cannot be pruned
+// yet. Pruning is then quietly aborted and retried later.
+var ErrPruneDeferred = errors.New("runtime/history: prune deferred")
+
 // PrunerFactory is the runtime history pruner factory interface.
 type PrunerFactory func(db *DB) (Pruner, error)
 
@@ -96,7 +101,7 @@ func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
 
 	lastPrunedRound := latestRound - p.numKept
 
-	return p.db.db.Update(func(tx *badger.Txn) error {
+	err := p.db.db.Update(func(tx *badger.Txn) error {
 		// NOTE: Do not prefetch values as we are only looking at keys.
 		it := tx.NewIterator(badger.IteratorOptions{
 			Prefix: blockKeyFmt.Encode(),
@@ -138,6 +143,13 @@ func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
 		// fails we abort the prune.
 		for _, ph := range p.prunerBase.handlers {
 			if err := ph.Prune(ctx, pruned); err != nil {
+				if errors.Is(err, ErrPruneDeferred) {
+					p.logger.Debug("prune handler deferred pruning, aborting prune",
+						"err", err,
+					)
+					return err
+				}
+
 				p.logger.Error("prune handler failed, aborting prune",
 					"err", err,
 					"round_count", len(pruned),
@@ -150,6 +162,10 @@ func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
 
 		return nil
 	})
+	if errors.Is(err, ErrPruneDeferred) {
+		return nil
+	}
+	return err
 }
 
 // NewKeepLastPruner creates a pruner that keeps the last configured
@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -40,6 +41,9 @@ var (
 	ErrUnsupported = errors.New(ModuleName, 4, "storage: method not supported by backend")
 	// ErrLimitReached means that a configured limit has been reached.
 	ErrLimitReached = errors.New(ModuleName, 5, "storage: limit reached")
+	// ErrValueTooLarge is the error returned when a write log entry value
+	// exceeds the configured maximum value size.
+	ErrValueTooLarge = errors.New(ModuleName, 6, "storage: value too large")
 
 	// The following errors are reimports from NodeDB.
 
@@ -68,6 +72,10 @@ var (
 	ErrRootMustFollowOld = nodedb.ErrRootMustFollowOld
 	// ErrReadOnly indicates that the storage backend is read-only.
 	ErrReadOnly = nodedb.ErrReadOnly
+	// ErrNoAppliedRoot indicates that no root has been applied yet.
+	ErrNoAppliedRoot = nodedb.ErrNoAppliedRoot
+	// ErrCompactionInProgress indicates that a compaction is already in progress.
+	ErrCompactionInProgress = nodedb.ErrCompactionInProgress
 
 	// ReceiptSignatureContext is the signature context used for verifying MKVS receipts.
 	ReceiptSignatureContext = signature.NewContext("oasis-core/storage: receipt", signature.WithChainSeparation())
@@ -85,6 +93,9 @@ type Config struct { // nolint: maligned
 	Signer signature.Signer
 
 	// ApplyLockLRUSlots is the number of LRU slots to use for Apply call locks.
+	//
+	// This bounds the number of distinct (old root, new root) pairs tracked by the root cache.
+	// Trees themselves are not kept resident by the root cache.
 	ApplyLockLRUSlots uint64
 
 	// InsecureSkipChecks bypasses the known root checks.
@@ -107,18 +118,63 @@ type Config struct { // nolint: maligned
 
 	// ReadOnly will make the storage read-only.
 	ReadOnly bool
+
+	// DetectConflicts enables the underlying database's transaction conflict detection.
+	DetectConflicts bool
+
+	// LogSamplingWindow is the window for collapsing identical database log messages (0 = disabled).
+	LogSamplingWindow time.Duration
+
+	// VerifyOnStart enables a consistency check of the most recent finalized roots when opening
+	// the database. If any of the roots fails verification, the backend refuses to start.
+	VerifyOnStart bool
+
+	// MaxPendingApplies is the maximum number of Apply/ApplyBatch calls that may be processed
+	// concurrently. Any further calls block until pending work drops below the threshold, which
+	// provides backpressure to callers. Zero means no limit.
+	MaxPendingApplies int
+
+	// MaxRootPrefixMatches is the maximum number of roots returned by a root hash prefix lookup.
+	// If zero, a default limit is used.
+	MaxRootPrefixMatches int
+
+	// OpenRetries is the number of times opening the database is retried in case it is locked by
+	// another process.
+	OpenRetries uint64
+
+	// OpenRetryInterval is the initial interval between attempts to open a locked database. The
+	// interval increases exponentially with each attempt.
+	OpenRetryInterval time.Duration
+
+	// RemoteFallback is an optional remote backend from which any nodes missing in the local
+	// database are fetched on demand (e.g., when applying against a root that is not available
+	// locally). Fetched nodes are persisted in the local database.
+	RemoteFallback Backend
+
+	// RemoteFallbackMaxConcurrency is the maximum number of concurrent requests to the remote
+	// fallback backend. If zero, a default limit is used.
+	RemoteFallbackMaxConcurrency int
+
+	// MaxValueSize is the maximum size of a value in an applied write log. Applies containing any
+	// larger value are rejected with ErrValueTooLarge. Zero means no limit.
+	//
+	// Runtime state values are normally small, so a limit of a few megabytes (e.g., 4 MiB) bounds
+	// the memory and disk use of a single malformed write log without affecting regular use.
+	MaxValueSize uint64
 }
 
 // ToNodeDB converts from a Config to a node DB Config.
 func (cfg *Config) ToNodeDB() *nodedb.Config {
 	return &nodedb.Config{
-		DB:               cfg.DB,
-		Namespace:        cfg.Namespace,
-		MaxCacheSize:     cfg.MaxCacheSize,
-		NoFsync:          cfg.NoFsync,
-		MemoryOnly:       cfg.MemoryOnly,
-		ReadOnly:         cfg.ReadOnly,
-		DiscardWriteLogs: cfg.DiscardWriteLogs,
+		DB:                cfg.DB,
+		Namespace:         cfg.Namespace,
+		MaxCacheSize:      cfg.MaxCacheSize,
+		NoFsync:           cfg.NoFsync,
+		MemoryOnly:        cfg.MemoryOnly,
+		ReadOnly:          cfg.ReadOnly,
+		DiscardWriteLogs:  cfg.DiscardWriteLogs,
+		DetectConflicts:   cfg.DetectConflicts,
+		LogSamplingWindow: cfg.LogSamplingWindow,
 	}
 }
 
@@ -237,6 +293,30 @@ type ApplyOp struct {
 	WriteLog WriteLog `json:"writelog"`
 }
 
+// StorageHealth is the health status of a storage backend.
+type StorageHealth struct {
+	// DatabaseOpen is true iff the underlying database is open.
+	DatabaseOpen bool `json:"database_open"`
+	// LastAppliedRoot is the hash of the most recently applied root if any.
+	LastAppliedRoot *hash.Hash `json:"last_applied_root,omitempty"`
+	// ValueLogSize is the size of the database value log in bytes. This is an upper bound on the
+	// amount of space that can be reclaimed by value log garbage collection.
+	ValueLogSize int64 `json:"value_log_size"`
+	// VerifiedOnStart is true iff the consistency check of the most recent finalized roots was
+	// performed and passed when the database was opened.
+	VerifiedOnStart bool `json:"verified_on_start"`
+}
+
+// ApplyEstimate is an estimate of the cost of applying a write log.
+type ApplyEstimate struct {
+	// NewRoot is the hash of the root that would result from applying the write log.
+	NewRoot hash.Hash `json:"new_root"`
+	// NewNodes is the number of new nodes that would be created.
+	NewNodes uint64 `json:"new_nodes"`
+	// NewNodeBytes is the total size of all new nodes in their serialized form.
+	NewNodeBytes uint64 `json:"new_node_bytes"`
+}
+
 // ApplyRequest is an Apply request.
 type ApplyRequest struct {
 	Namespace common.Namespace `json:"namespace"`
@@ -40,12 +40,30 @@ var (
 		},
 		[]string{"call"},
 	)
+	storageWriteLogEntries = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "oasis_storage_write_log_entries",
+			Help:    "Number of write log entries applied per storage call.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 12),
+		},
+		[]string{"call"},
+	)
+	storageWriteLogBytes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "oasis_storage_write_log_bytes",
+			Help:    "Total size of write log entries applied per storage call (bytes).",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 12),
+		},
+		[]string{"call"},
+	)
 
 	storageCollectors = []prometheus.Collector{
 		storageFailures,
 		storageCalls,
 		storageLatency,
 		storageValueSize,
+		storageWriteLogEntries,
+		storageWriteLogBytes,
 	}
 
 	labelApply           = prometheus.Labels{"call": "apply"}
@@ -94,6 +112,8 @@ func (w *metricsWrapper) Apply(ctx context.Context, request *ApplyRequest) ([]*R
 	}
 
 	storageCalls.With(labelApply).Inc()
+	storageWriteLogEntries.With(labelApply).Observe(float64(len(request.WriteLog)))
+	storageWriteLogBytes.With(labelApply).Observe(float64(size))
 	return receipts, err
 }
 
@@ -102,8 +122,9 @@ func (w *metricsWrapper) ApplyBatch(ctx context.Context, request *ApplyBatchRequ
 	receipts, err := w.Backend.ApplyBatch(ctx, request)
 	storageLatency.With(labelApplyBatch).Observe(time.Since(start).Seconds())
 
-	var size int
+	var size, entries int
 	for _, op := range request.Ops {
+		entries += len(op.WriteLog)
 		for _, entry := range op.WriteLog {
 			size += len(entry.Key) + len(entry.Value)
 		}
@@ -115,6 +136,8 @@ func (w *metricsWrapper) ApplyBatch(ctx context.Context, request *ApplyBatchRequ
 	}
 
 	storageCalls.With(labelApplyBatch).Inc()
+	storageWriteLogEntries.With(labelApplyBatch).Observe(float64(entries))
+	storageWriteLogBytes.With(labelApplyBatch).Observe(float64(size))
 	return receipts, err
 }
 
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+type applyBackend struct {
+	Backend
+}
+
+func (b *applyBackend) Apply(ctx context.Context, request *ApplyRequest) ([]*Receipt, error) {
+	return nil, nil
+}
+
+func (b *applyBackend) ApplyBatch(ctx context.Context, request *ApplyBatchRequest) ([]*Receipt, error) {
+	return nil, nil
+}
+
+// histogramSum returns the sample count and sum of the given write log histogram for a call.
+func histogramSum(t *testing.T, name string, labels prometheus.Labels) (uint64, float64) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(storageWriteLogEntries, storageWriteLogBytes)
+	mfs, err := reg.Gather()
+	require.NoError(t, err, "Gather()")
+
+	for _, mf := range mfs {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if lp.GetName() == "call" && lp.GetValue() == labels["call"] {
+					return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+				}
+			}
+		}
+	}
+	return 0, 0
+}
+
+func TestMetricsWriteLogSize(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	w := &metricsWrapper{Backend: &applyBackend{}}
+
+	entriesCount, entriesSum := histogramSum(t, "oasis_storage_write_log_entries", labelApply)
+	bytesCount, bytesSum := histogramSum(t, "oasis_storage_write_log_bytes", labelApply)
+	_, err := w.Apply(ctx, &ApplyRequest{
+		WriteLog: WriteLog{
+			{Key: []byte("key 1"), Value: []byte("value 1")},
+			{Key: []byte("key 2"), Value: []byte("value 2")},
+		},
+	})
+	require.NoError(err, "Apply()")
+
+	count, sum := histogramSum(t, "oasis_storage_write_log_entries", labelApply)
+	require.EqualValues(entriesCount+1, count, "Apply() should be observed once")
+	require.EqualValues(entriesSum+2, sum, "Apply() should observe the number of entries")
+	count, sum = histogramSum(t, "oasis_storage_write_log_bytes", labelApply)
+	require.EqualValues(bytesCount+1, count, "Apply() should be observed once")
+	require.EqualValues(bytesSum+24, sum, "Apply() should observe the number of bytes")
+
+	entriesCount, entriesSum = histogramSum(t, "oasis_storage_write_log_entries", labelApplyBatch)
+	_, err = w.ApplyBatch(ctx, &ApplyBatchRequest{
+		Ops: []ApplyOp{
+			{WriteLog: WriteLog{{Key: []byte("key 1"), Value: []byte("value 1")}}},
+			{WriteLog: WriteLog{{Key: []byte("key 2"), Value: []byte("value 2")}}},
+		},
+	})
+	require.NoError(err, "ApplyBatch()")
+
+	count, sum = histogramSum(t, "oasis_storage_write_log_entries", labelApplyBatch)
+	require.EqualValues(entriesCount+1, count, "ApplyBatch() should be observed once")
+	require.EqualValues(entriesSum+2, sum, "ApplyBatch() should observe the entries of all ops")
+}
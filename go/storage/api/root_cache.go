@@ -15,6 +15,10 @@ import (
 )
 
 // RootCache is a LRU based tree cache.
+//
+// Trees are not kept resident, GetTree and Apply always create a new tree backed by the node
+// database so memory use does not depend on the number or size of roots. The only state kept
+// in the LRU are the per-root apply locks, bounded by count.
 type RootCache struct {
 	localDB      nodedb.NodeDB
 	remoteSyncer syncer.ReadSyncer
@@ -35,6 +39,8 @@ func (rc *RootCache) GetTree(ctx context.Context, root Root) (mkvs.Tree, error)
 
 // Apply applies the write log, bypassing the apply operation iff the new root
 // already is in the node database.
+//
+// Write log entries with a nil value delete the corresponding keys.
 func (rc *RootCache) Apply(
 	ctx context.Context,
 	ns common.Namespace,
@@ -101,6 +107,67 @@ func (rc *RootCache) Apply(
 	return &r, nil
 }
 
+// EstimateApply computes the root that would result from applying the write log on top of the
+// given root at the given version, together with the number and size of the new nodes, without
+// persisting anything.
+//
+// Nodes that are already present in the node database but would be recreated by the write log
+// are counted as new, so the estimate is an upper bound.
+func (rc *RootCache) EstimateApply(
+	ctx context.Context,
+	root Root,
+	dstVersion uint64,
+	writeLog WriteLog,
+) (*ApplyEstimate, error) {
+	tree := mkvs.NewWithRoot(rc.remoteSyncer, rc.localDB, root, rc.persistEverything)
+	defer tree.Close()
+
+	if err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog)); err != nil {
+		return nil, err
+	}
+
+	var stats mkvs.CommitStats
+	_, newRoot, err := tree.Commit(ctx, root.Namespace, dstVersion, mkvs.NoPersist(), mkvs.WithCommitStats(&stats))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ApplyEstimate{
+		NewRoot:      newRoot,
+		NewNodes:     stats.Nodes,
+		NewNodeBytes: stats.Bytes,
+	}, nil
+}
+
+// ComputeChainRoots computes the roots that would result from applying each of the write logs
+// on top of the result of the previous one, starting at the given root, without persisting
+// anything. All resulting roots are at the given version.
+func (rc *RootCache) ComputeChainRoots(
+	ctx context.Context,
+	root Root,
+	dstVersion uint64,
+	writeLogs []WriteLog,
+) ([]hash.Hash, error) {
+	tree := mkvs.NewWithRoot(rc.remoteSyncer, rc.localDB, root, rc.persistEverything)
+	defer tree.Close()
+
+	// Nodes are never marked clean by commits that do not persist, so the same tree can be used
+	// to compute all roots in the chain.
+	roots := make([]hash.Hash, 0, len(writeLogs))
+	for _, writeLog := range writeLogs {
+		if err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog)); err != nil {
+			return nil, err
+		}
+
+		_, newRoot, err := tree.Commit(ctx, root.Namespace, dstVersion, mkvs.NoPersist())
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, newRoot)
+	}
+	return roots, nil
+}
+
 func (rc *RootCache) getApplyLock(root, expectedNewRoot Root) *sync.Mutex {
 	// Lock the Apply call based on (oldRoot, expectedNewRoot), so that when
 	// multiple executor committees commit the same write logs, we only write
@@ -7,13 +7,23 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	badgerNodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 )
 
 const (
@@ -24,8 +34,32 @@ const (
 	DBFileBadgerDB = "mkvs_storage.badger.db"
 
 	checkpointDir = "checkpoints"
+
+	// verifyOnStartNumVersions is the number of most recent finalized versions whose roots are
+	// verified on startup when VerifyOnStart is enabled.
+	verifyOnStartNumVersions = 10
+
+	// defaultMaxRootPrefixMatches is the default maximum number of roots returned by
+	// ResolveRootPrefix.
+	defaultMaxRootPrefixMatches = 100
+
+	// watchAppliesBufferSize is the number of applied roots buffered for each WatchApplies
+	// subscriber. In case a subscriber falls behind, the oldest roots are dropped.
+	watchAppliesBufferSize = 128
 )
 
+// BackendNames are the names of all supported database backends.
+var BackendNames = []string{
+	BackendNameBadgerDB,
+}
+
+// ErrDatabaseLocked is the error returned when the database is locked by another
+// process and could not be opened.
+var ErrDatabaseLocked = errors.New("storage/database: database locked by another process")
+
+// errBackendClosed is the error returned when watching a backend that has been cleaned up.
+var errBackendClosed = errors.New("storage/database: backend closed")
+
 // DefaultFileName returns the default database filename for the specified
 // backend.
 func DefaultFileName(backend string) string {
@@ -38,6 +72,7 @@ func DefaultFileName(backend string) string {
 }
 
 type databaseBackend struct {
+	namespace    common.Namespace
 	nodedb       nodedb.NodeDB
 	checkpointer checkpoint.CreateRestorer
 	rootCache    *api.RootCache
@@ -45,11 +80,61 @@ type databaseBackend struct {
 	signer signature.Signer
 	initCh chan struct{}
 
+	// pendingApplies is the number of Apply/ApplyBatch calls that are either waiting or being
+	// processed. It must only be accessed atomically.
+	pendingApplies int64
+	// applySem limits the number of concurrently processed Apply/ApplyBatch calls. It is nil in
+	// case there is no limit.
+	applySem chan struct{}
+
+	highWatermarkLock sync.Mutex
+	highWatermark     int64
+
+	maxRootPrefixMatches int
+	maxValueSize         uint64
+
+	// verifiedOnStart is true iff the consistency check passed when opening the database.
+	verifiedOnStart bool
+	// closed is non-zero after the backend has been cleaned up. It must only be accessed
+	// atomically.
+	closed uint32
+
+	applyNotifier   *pubsub.Broker
+	applySubsLock   sync.Mutex
+	applySubs       map[*applySubscription]struct{}
+	applySubsClosed bool
+
 	readOnly bool
 }
 
+// applySubscription is a WatchApplies subscription.
+type applySubscription struct {
+	ba  *databaseBackend
+	sub *pubsub.Subscription
+
+	closeOnce sync.Once
+}
+
+// Close unsubscribes from applied root notifications. It is safe to call Close
+// multiple times.
+func (s *applySubscription) Close() {
+	s.closeOnce.Do(func() {
+		s.ba.applySubsLock.Lock()
+		delete(s.ba.applySubs, s)
+		s.ba.applySubsLock.Unlock()
+
+		s.sub.Close()
+	})
+}
+
 // New constructs a new database backed storage Backend instance.
 func New(cfg *api.Config) (api.Backend, error) {
+	return NewWithContext(context.Background(), cfg)
+}
+
+// NewWithContext constructs a new database backed storage Backend instance, aborting in case
+// the context is canceled while the node database is being opened or verified.
+func NewWithContext(ctx context.Context, cfg *api.Config) (api.Backend, error) {
 	ndbCfg := cfg.ToNodeDB()
 
 	var (
@@ -58,7 +143,9 @@ func New(cfg *api.Config) (api.Backend, error) {
 	)
 	switch cfg.Backend {
 	case BackendNameBadgerDB:
-		ndb, err = badgerNodedb.New(ndbCfg)
+		ndb, err = openWithRetry(ctx, cfg, func() (nodedb.NodeDB, error) {
+			return badgerNodedb.NewWithContext(ctx, ndbCfg)
+		})
 	default:
 		err = errors.New("storage/database: unsupported backend")
 	}
@@ -66,12 +153,24 @@ func New(cfg *api.Config) (api.Backend, error) {
 		return nil, fmt.Errorf("storage/database: failed to create node database: %w", err)
 	}
 
-	rootCache, err := api.NewRootCache(ndb, nil, cfg.ApplyLockLRUSlots, cfg.InsecureSkipChecks)
+	var remoteSyncer syncer.ReadSyncer
+	if cfg.RemoteFallback != nil {
+		remoteSyncer = newBoundedReadSyncer(cfg.RemoteFallback, cfg.RemoteFallbackMaxConcurrency)
+	}
+
+	rootCache, err := api.NewRootCache(ndb, remoteSyncer, cfg.ApplyLockLRUSlots, cfg.InsecureSkipChecks)
 	if err != nil {
 		ndb.Close()
 		return nil, fmt.Errorf("storage/database: failed to create root cache: %w", err)
 	}
 
+	if cfg.VerifyOnStart {
+		if err = verifyRecentRoots(ctx, ndb, cfg.Namespace, verifyOnStartNumVersions); err != nil {
+			ndb.Close()
+			return nil, fmt.Errorf("storage/database: consistency check failed: %w", err)
+		}
+	}
+
 	// Satisfy the interface.
 	initCh := make(chan struct{})
 	close(initCh)
@@ -88,20 +187,151 @@ func New(cfg *api.Config) (api.Backend, error) {
 		return nil, fmt.Errorf("storage/database: failed to create checkpoint restorer: %w", err)
 	}
 
+	initMetrics()
+
+	var applySem chan struct{}
+	if cfg.MaxPendingApplies > 0 {
+		applySem = make(chan struct{}, cfg.MaxPendingApplies)
+	}
+
+	maxRootPrefixMatches := cfg.MaxRootPrefixMatches
+	if maxRootPrefixMatches <= 0 {
+		maxRootPrefixMatches = defaultMaxRootPrefixMatches
+	}
+
 	return &databaseBackend{
-		nodedb:       ndb,
-		checkpointer: checkpoint.NewCreateRestorer(creator, restorer),
-		rootCache:    rootCache,
-		signer:       cfg.Signer,
-		initCh:       initCh,
-		readOnly:     cfg.ReadOnly,
+		namespace:            cfg.Namespace,
+		nodedb:               ndb,
+		checkpointer:         checkpoint.NewCreateRestorer(creator, restorer),
+		rootCache:            rootCache,
+		signer:               cfg.Signer,
+		initCh:               initCh,
+		applySem:             applySem,
+		maxRootPrefixMatches: maxRootPrefixMatches,
+		maxValueSize:         cfg.MaxValueSize,
+		verifiedOnStart:      cfg.VerifyOnStart,
+		applyNotifier:        pubsub.NewBroker(false),
+		applySubs:            make(map[*applySubscription]struct{}),
+		readOnly:             cfg.ReadOnly,
 	}, nil
 }
 
+// openWithRetry opens the node database, retrying with exponential backoff in
+// case the database is locked by another process.
+func openWithRetry(ctx context.Context, cfg *api.Config, openFn func() (nodedb.NodeDB, error)) (nodedb.NodeDB, error) {
+	logger := logging.GetLogger("storage/database")
+
+	var ndb nodedb.NodeDB
+	open := func() error {
+		var err error
+		ndb, err = openFn()
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+			return backoff.Permanent(err)
+		case errors.Is(err, syscall.EWOULDBLOCK):
+			return ErrDatabaseLocked
+		default:
+			return backoff.Permanent(err)
+		}
+	}
+
+	boff := backoff.NewExponentialBackOff()
+	if cfg.OpenRetryInterval > 0 {
+		boff.InitialInterval = cfg.OpenRetryInterval
+	}
+	boff.MaxElapsedTime = 0
+
+	notify := func(err error, next time.Duration) {
+		logger.Warn("failed to open database, retrying",
+			"err", err,
+			"db", cfg.DB,
+			"next_attempt", next,
+		)
+	}
+
+	err := backoff.RetryNotify(open, backoff.WithContext(backoff.WithMaxRetries(boff, cfg.OpenRetries), ctx), notify)
+	if err != nil {
+		return nil, err
+	}
+	return ndb, nil
+}
+
+// PendingApplies returns the number of Apply/ApplyBatch calls that are either
+// waiting to be processed or are currently being processed.
+//
+// This can be used as a backpressure signal by callers. Note that the method is
+// not part of api.LocalBackend, so it is not reachable through a backend wrapped
+// by api.NewMetricsWrapper; use the oasis_storage_pending_applies metric instead.
+func (ba *databaseBackend) PendingApplies() int {
+	return int(atomic.LoadInt64(&ba.pendingApplies))
+}
+
+// beginApply registers a new pending apply and, in case a limit is configured,
+// blocks until the apply may proceed. The returned function must be called once
+// the apply is done.
+func (ba *databaseBackend) beginApply(ctx context.Context) (func(), error) {
+	pending := atomic.AddInt64(&ba.pendingApplies, 1)
+	pendingApplies.With(ba.getMetricLabels()).Set(float64(pending))
+
+	ba.highWatermarkLock.Lock()
+	if pending > ba.highWatermark {
+		ba.highWatermark = pending
+		pendingAppliesHighWatermark.With(ba.getMetricLabels()).Set(float64(pending))
+	}
+	ba.highWatermarkLock.Unlock()
+
+	done := func() {
+		pending := atomic.AddInt64(&ba.pendingApplies, -1)
+		pendingApplies.With(ba.getMetricLabels()).Set(float64(pending))
+	}
+
+	if ba.applySem == nil {
+		return done, nil
+	}
+
+	select {
+	case ba.applySem <- struct{}{}:
+	case <-ctx.Done():
+		done()
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		<-ba.applySem
+		done()
+	}, nil
+}
+
+// checkWriteLog checks that no value in the write log exceeds the configured maximum value size.
+func (ba *databaseBackend) checkWriteLog(writeLog api.WriteLog) error {
+	if ba.maxValueSize == 0 {
+		return nil
+	}
+	for _, entry := range writeLog {
+		if uint64(len(entry.Value)) > ba.maxValueSize {
+			return fmt.Errorf("%w: key %X has a value of %d bytes (max: %d)",
+				api.ErrValueTooLarge, entry.Key, len(entry.Value), ba.maxValueSize,
+			)
+		}
+	}
+	return nil
+}
+
 func (ba *databaseBackend) Apply(ctx context.Context, request *api.ApplyRequest) ([]*api.Receipt, error) {
 	if ba.readOnly {
 		return nil, fmt.Errorf("storage/database: failed to Apply: %w", api.ErrReadOnly)
 	}
+	if err := ba.checkWriteLog(request.WriteLog); err != nil {
+		return nil, fmt.Errorf("storage/database: failed to Apply: %w", err)
+	}
+
+	done, err := ba.beginApply(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("storage/database: failed to Apply: %w", err)
+	}
+	defer done()
 
 	newRoot, err := ba.rootCache.Apply(
 		ctx,
@@ -115,15 +345,33 @@ func (ba *databaseBackend) Apply(ctx context.Context, request *api.ApplyRequest)
 	if err != nil {
 		return nil, fmt.Errorf("storage/database: failed to Apply: %w", err)
 	}
+	if err = ba.nodedb.SetLastAppliedRoot(ctx, *newRoot); err != nil {
+		return nil, fmt.Errorf("storage/database: failed to record last applied root: %w", err)
+	}
+	ba.applyNotifier.Broadcast(*newRoot)
 
-	receipt, err := api.SignReceipt(ba.signer, request.Namespace, request.DstRound, []hash.Hash{*newRoot})
-	return []*api.Receipt{receipt}, err
+	receipt, err := ba.signReceipt(ctx, request.Namespace, request.DstRound, []hash.Hash{*newRoot})
+	if err != nil {
+		return nil, err
+	}
+	return []*api.Receipt{receipt}, nil
 }
 
 func (ba *databaseBackend) ApplyBatch(ctx context.Context, request *api.ApplyBatchRequest) ([]*api.Receipt, error) {
 	if ba.readOnly {
 		return nil, fmt.Errorf("storage/database: failed to ApplyBatch: %w", api.ErrReadOnly)
 	}
+	for _, op := range request.Ops {
+		if err := ba.checkWriteLog(op.WriteLog); err != nil {
+			return nil, fmt.Errorf("storage/database: failed to ApplyBatch: %w", err)
+		}
+	}
+
+	done, err := ba.beginApply(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("storage/database: failed to ApplyBatch: %w", err)
+	}
+	defer done()
 
 	newRoots := make([]hash.Hash, 0, len(request.Ops))
 	for _, op := range request.Ops {
@@ -133,12 +381,340 @@ func (ba *databaseBackend) ApplyBatch(ctx context.Context, request *api.ApplyBat
 		}
 		newRoots = append(newRoots, *newRoot)
 	}
+	if len(newRoots) > 0 {
+		if err := ba.nodedb.SetLastAppliedRoot(ctx, newRoots[len(newRoots)-1]); err != nil {
+			return nil, fmt.Errorf("storage/database: failed to record last applied root: %w", err)
+		}
+	}
+	for _, newRoot := range newRoots {
+		ba.applyNotifier.Broadcast(newRoot)
+	}
+
+	receipt, err := ba.signReceipt(ctx, request.Namespace, request.DstRound, newRoots)
+	if err != nil {
+		return nil, err
+	}
+	return []*api.Receipt{receipt}, nil
+}
+
+// ApplyChain applies each of the write logs on top of the root resulting from the previous one,
+// starting at the given base root, and returns the hashes of all resulting roots together with
+// a single receipt covering the final root. All roots are created in the given destination
+// version.
+//
+// All roots are computed before anything is persisted, so a chain containing an invalid write
+// log is never partially applied. Persisting the roots is not atomic though. In case it fails
+// midway, the roots persisted so far remain in the database (but are not reported as applied)
+// until they are discarded when the destination version is finalized without them.
+func (ba *databaseBackend) ApplyChain(
+	ctx context.Context,
+	base api.Root,
+	dstVersion uint64,
+	logs []api.WriteLog,
+) ([]hash.Hash, *api.Receipt, error) {
+	if ba.readOnly {
+		return nil, nil, fmt.Errorf("storage/database: failed to ApplyChain: %w", api.ErrReadOnly)
+	}
+	if len(logs) == 0 {
+		return nil, nil, fmt.Errorf("storage/database: failed to ApplyChain: no write logs")
+	}
+	for _, log := range logs {
+		if err := ba.checkWriteLog(log); err != nil {
+			return nil, nil, fmt.Errorf("storage/database: failed to ApplyChain: %w", err)
+		}
+	}
+
+	done, err := ba.beginApply(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage/database: failed to ApplyChain: %w", err)
+	}
+	defer done()
+
+	if !ba.nodedb.HasRoot(base) {
+		return nil, nil, fmt.Errorf("storage/database: failed to ApplyChain: %w", nodedb.ErrRootNotFound)
+	}
+
+	newRoots, err := ba.rootCache.ComputeChainRoots(ctx, base, dstVersion, logs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage/database: failed to ApplyChain: %w", err)
+	}
+
+	srcRoot := base
+	for i, log := range logs {
+		if _, err = ba.rootCache.Apply(ctx, base.Namespace, srcRoot.Version, srcRoot.Hash, dstVersion, newRoots[i], log); err != nil {
+			return nil, nil, fmt.Errorf("storage/database: failed to ApplyChain, log %d: %w", i, err)
+		}
+		srcRoot = api.Root{
+			Namespace: base.Namespace,
+			Version:   dstVersion,
+			Hash:      newRoots[i],
+		}
+	}
+
+	finalRoot := newRoots[len(newRoots)-1]
+	if err = ba.nodedb.SetLastAppliedRoot(ctx, finalRoot); err != nil {
+		return nil, nil, fmt.Errorf("storage/database: failed to record last applied root: %w", err)
+	}
+	for _, newRoot := range newRoots {
+		ba.applyNotifier.Broadcast(newRoot)
+	}
+
+	receipt, err := ba.signReceipt(ctx, base.Namespace, dstVersion, []hash.Hash{finalRoot})
+	if err != nil {
+		return nil, nil, err
+	}
+	return newRoots, receipt, nil
+}
+
+// EstimateApply computes the root that would result from applying the write log on top of the
+// given root, together with the number and size of the nodes that the apply would create,
+// without persisting anything.
+//
+// The write log is applied as if in the version following the earliest version containing the
+// given root (or the latest finalized version in case of an empty root), as node hashes depend
+// on the version.
+func (ba *databaseBackend) EstimateApply(ctx context.Context, root hash.Hash, log api.WriteLog) (*api.ApplyEstimate, error) {
+	var (
+		version uint64
+		err     error
+	)
+	switch root.IsEmpty() {
+	case true:
+		version, err = ba.nodedb.GetLatestVersion(ctx)
+	case false:
+		version, err = ba.nodedb.GetRootVersion(ctx, root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("storage/database: failed to EstimateApply: %w", err)
+	}
+
+	estimate, err := ba.rootCache.EstimateApply(
+		ctx,
+		api.Root{
+			Namespace: ba.namespace,
+			Version:   version,
+			Hash:      root,
+		},
+		version+1,
+		log,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage/database: failed to EstimateApply: %w", err)
+	}
+	return estimate, nil
+}
 
-	receipt, err := api.SignReceipt(ba.signer, request.Namespace, request.DstRound, newRoots)
-	return []*api.Receipt{receipt}, err
+// LastAppliedRoot returns the hash of the root resulting from the most recent
+// Apply or ApplyBatch call (in case of ApplyBatch, the root of the last
+// operation).
+//
+// In case nothing has been applied yet, ErrNoAppliedRoot is returned.
+func (ba *databaseBackend) LastAppliedRoot(ctx context.Context) (hash.Hash, error) {
+	return ba.nodedb.GetLastAppliedRoot(ctx)
+}
+
+// WatchApplies returns a channel that produces the hash of each newly applied
+// root.
+//
+// Broadcasting never blocks Apply. In case a subscriber falls behind, the oldest
+// pending roots are dropped. All subscriptions are closed on Cleanup.
+func (ba *databaseBackend) WatchApplies(ctx context.Context) (<-chan hash.Hash, pubsub.ClosableSubscription, error) {
+	ba.applySubsLock.Lock()
+	defer ba.applySubsLock.Unlock()
+
+	if ba.applySubsClosed {
+		return nil, nil, errBackendClosed
+	}
+
+	typedCh := make(chan hash.Hash)
+	sub := &applySubscription{
+		ba:  ba,
+		sub: ba.applyNotifier.SubscribeBuffered(watchAppliesBufferSize),
+	}
+	sub.sub.Unwrap(typedCh)
+	ba.applySubs[sub] = struct{}{}
+
+	return typedCh, sub, nil
+}
+
+// ResolveRootPrefix returns the hashes of all known roots whose hash starts with
+// the given byte prefix, capped at the configured maximum number of matches.
+//
+// In case there are no matching roots, an empty slice is returned.
+func (ba *databaseBackend) ResolveRootPrefix(ctx context.Context, prefix []byte) ([]hash.Hash, error) {
+	roots, err := ba.nodedb.GetRootsByPrefix(ctx, prefix, ba.maxRootPrefixMatches)
+	if err != nil {
+		return nil, fmt.Errorf("storage/database: failed to resolve root prefix: %w", err)
+	}
+	return roots, nil
+}
+
+// GetChildRoots returns the hashes of all known roots that have been applied with the given
+// root as their starting root.
+//
+// In case there are no such roots, an empty slice is returned.
+func (ba *databaseBackend) GetChildRoots(ctx context.Context, base hash.Hash) ([]hash.Hash, error) {
+	children, err := ba.nodedb.GetChildRoots(ctx, base)
+	if err != nil {
+		return nil, fmt.Errorf("storage/database: failed to get child roots: %w", err)
+	}
+	return children, nil
+}
+
+// IterateFinalizedRoots invokes the callback for each finalized root in ascending height order,
+// starting at the given height. Iteration stops early when the callback returns false.
+//
+// Roots are read from the database as the iteration progresses so the full set of roots is never
+// held in memory.
+func (ba *databaseBackend) IterateFinalizedRoots(
+	ctx context.Context,
+	since uint64,
+	cb func(root hash.Hash, height uint64) bool,
+) error {
+	if err := ba.nodedb.IterateFinalizedRoots(ctx, since, cb); err != nil {
+		return fmt.Errorf("storage/database: failed to iterate finalized roots: %w", err)
+	}
+	return nil
+}
+
+// HealthCheck returns the health status of the storage backend.
+//
+// The check only uses cached metadata and does not walk the database, so it is cheap enough to
+// be used as a frequent readiness probe.
+func (ba *databaseBackend) HealthCheck(ctx context.Context) (*api.StorageHealth, error) {
+	health := &api.StorageHealth{
+		DatabaseOpen:    atomic.LoadUint32(&ba.closed) == 0,
+		VerifiedOnStart: ba.verifiedOnStart,
+	}
+	if !health.DatabaseOpen {
+		return health, nil
+	}
+
+	lastRoot, err := ba.nodedb.GetLastAppliedRoot(ctx)
+	switch err {
+	case nil:
+		health.LastAppliedRoot = &lastRoot
+	case nodedb.ErrNoAppliedRoot:
+	default:
+		return nil, fmt.Errorf("storage/database: failed to get last applied root: %w", err)
+	}
+
+	if health.ValueLogSize, err = ba.nodedb.ValueLogSize(); err != nil {
+		return nil, fmt.Errorf("storage/database: failed to get value log size: %w", err)
+	}
+
+	return health, nil
+}
+
+// CurrentSigningKeyID returns the identifier of the key currently used to sign storage receipts.
+//
+// The identifier is the signer's public key. Each receipt already carries the public key of the
+// key that signed it, so receipts can be matched against the returned identifier without any
+// changes to the signed receipt body, which must stay identical across all storage nodes.
+func (ba *databaseBackend) CurrentSigningKeyID() (signature.PublicKey, error) {
+	if ba.signer == nil {
+		return signature.PublicKey{}, api.ErrCantProve
+	}
+	return ba.signer.Public(), nil
+}
+
+// ReSignReceipt re-signs the given receipt body with the current signing key, e.g., to re-issue
+// receipts signed with a key that has since been rotated.
+//
+// As the new receipt certifies that the roots are stored, all roots must be present in the node
+// database. Nothing is applied.
+func (ba *databaseBackend) ReSignReceipt(ctx context.Context, body api.ReceiptBody) (*api.Receipt, error) {
+	if body.Version != 1 {
+		return nil, fmt.Errorf("storage/database: failed to ReSignReceipt: unsupported receipt version %d", body.Version)
+	}
+	if !body.Namespace.Equal(&ba.namespace) {
+		return nil, fmt.Errorf("storage/database: failed to ReSignReceipt: namespace mismatch (expected: %s got: %s)",
+			ba.namespace, body.Namespace,
+		)
+	}
+	for _, rootHash := range body.Roots {
+		root := api.Root{
+			Namespace: body.Namespace,
+			Version:   body.Round,
+			Hash:      rootHash,
+		}
+		if !ba.nodedb.HasRoot(root) {
+			return nil, fmt.Errorf("storage/database: failed to ReSignReceipt: root %s: %w", rootHash, nodedb.ErrRootNotFound)
+		}
+	}
+
+	receipt, err := ba.signReceipt(ctx, body.Namespace, body.Round, body.Roots)
+	if err != nil {
+		return nil, fmt.Errorf("storage/database: failed to ReSignReceipt: %w", err)
+	}
+	return receipt, nil
+}
+
+// signReceipt signs a storage receipt for the given roots.
+//
+// As the signer may be slow (e.g., backed by an HSM), signing is aborted when
+// the context is cancelled. In that case no receipt is returned.
+func (ba *databaseBackend) signReceipt(ctx context.Context, ns common.Namespace, round uint64, roots []hash.Hash) (*api.Receipt, error) {
+	type signResult struct {
+		receipt *api.Receipt
+		err     error
+	}
+
+	// Buffered so that the signing goroutine can always finish, even if nobody is waiting.
+	resultCh := make(chan *signResult, 1)
+	go func() {
+		receipt, err := api.SignReceipt(ba.signer, ns, round, roots)
+		resultCh <- &signResult{receipt, err}
+	}()
+
+	select {
+	case result := <-resultCh:
+		return result.receipt, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// PinRoot pins the root with the given hash, exempting any version containing it
+// from pruning until it is unpinned.
+func (ba *databaseBackend) PinRoot(ctx context.Context, root hash.Hash) error {
+	if ba.readOnly {
+		return fmt.Errorf("storage/database: failed to PinRoot: %w", api.ErrReadOnly)
+	}
+	return ba.nodedb.PinRoot(ctx, root)
+}
+
+// UnpinRoot removes a pin previously set via PinRoot.
+func (ba *databaseBackend) UnpinRoot(ctx context.Context, root hash.Hash) error {
+	if ba.readOnly {
+		return fmt.Errorf("storage/database: failed to UnpinRoot: %w", api.ErrReadOnly)
+	}
+	return ba.nodedb.UnpinRoot(ctx, root)
+}
+
+// Compact compacts the underlying node database using the given number of
+// workers. This is best run during low-traffic periods.
+func (ba *databaseBackend) Compact(ctx context.Context, workers int) error {
+	if ba.readOnly {
+		return fmt.Errorf("storage/database: failed to Compact: %w", api.ErrReadOnly)
+	}
+	return ba.nodedb.Compact(ctx, workers)
 }
 
 func (ba *databaseBackend) Cleanup() {
+	ba.applySubsLock.Lock()
+	ba.applySubsClosed = true
+	subs := make([]*applySubscription, 0, len(ba.applySubs))
+	for sub := range ba.applySubs {
+		subs = append(subs, sub)
+	}
+	ba.applySubsLock.Unlock()
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+
+	atomic.StoreUint32(&ba.closed, 1)
 	ba.nodedb.Close()
 }
 
@@ -1,20 +1,39 @@
 package database
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	genesisTestHelpers "github.com/oasisprotocol/oasis-core/go/genesis/tests"
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 	"github.com/oasisprotocol/oasis-core/go/storage/tests"
 )
 
+const recvTimeout = 1 * time.Second
+
 func TestStorageDatabase(t *testing.T) {
 	for _, v := range []string{
 		BackendNameBadgerDB,
@@ -56,3 +75,1104 @@ func doTestImpl(t *testing.T, backend string) {
 
 	tests.StorageImplementationTests(t, localBackend, impl, testNs, 0)
 }
+
+func newTestBackend(t *testing.T) (*databaseBackend, common.Namespace, func()) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	testNs := common.NewTestNamespaceFromSeed([]byte("database backend test ns"), 0)
+	cfg := api.Config{
+		Backend:           BackendNameBadgerDB,
+		ApplyLockLRUSlots: 100,
+		Namespace:         testNs,
+		MaxCacheSize:      16 * 1024 * 1024,
+		NoFsync:           true,
+	}
+
+	var err error
+	cfg.Signer, err = memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner()")
+
+	dir, err := ioutil.TempDir("", "oasis-storage-database-test")
+	require.NoError(err, "TempDir()")
+
+	cfg.DB = filepath.Join(dir, DefaultFileName(BackendNameBadgerDB))
+	impl, err := New(&cfg)
+	if err != nil {
+		os.RemoveAll(dir)
+	}
+	require.NoError(err, "New()")
+
+	return impl.(*databaseBackend), testNs, func() {
+		impl.Cleanup()
+		os.RemoveAll(dir)
+	}
+}
+
+func populateTestBackend(t *testing.T, ba *databaseBackend, ns common.Namespace, entries map[string]string) api.Root {
+	require := require.New(t)
+	ctx := context.Background()
+
+	tree := mkvs.New(nil, ba.nodedb)
+	defer tree.Close()
+	for k, v := range entries {
+		err := tree.Insert(ctx, []byte(k), []byte(v))
+		require.NoError(err, "Insert()")
+	}
+	_, rootHash, err := tree.Commit(ctx, ns, 1)
+	require.NoError(err, "Commit()")
+
+	return api.Root{
+		Namespace: ns,
+		Version:   1,
+		Hash:      rootHash,
+	}
+}
+
+func TestExportState(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	root := populateTestBackend(t, ba, ns, map[string]string{
+		"key 2": "value 2",
+		"key 1": "value 1",
+		"key 3": "value 3",
+	})
+
+	var buf bytes.Buffer
+	err := ba.ExportState(ctx, root, &buf)
+	require.NoError(err, "ExportState()")
+
+	rawHeader, err := readExportRecord(&buf)
+	require.NoError(err, "reading header")
+	var header ExportHeader
+	err = cbor.Unmarshal(rawHeader, &header)
+	require.NoError(err, "decoding header")
+	require.EqualValues(exportFormatVersion, header.Version, "header version")
+	require.Equal(root, header.Root, "header root")
+
+	for _, expected := range [][2]string{
+		{"key 1", "value 1"},
+		{"key 2", "value 2"},
+		{"key 3", "value 3"},
+	} {
+		key, err := readExportRecord(&buf)
+		require.NoError(err, "reading key")
+		require.Equal(expected[0], string(key), "keys should be exported in order")
+		value, err := readExportRecord(&buf)
+		require.NoError(err, "reading value")
+		require.Equal(expected[1], string(value))
+	}
+	require.Equal(0, buf.Len(), "export should not contain any extra data")
+
+	// Exporting a non-existent root should fail.
+	root.Version = 42
+	err = ba.ExportState(ctx, root, &buf)
+	require.Error(err, "ExportState() should fail for a non-existent root")
+}
+
+func TestImportState(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	src, ns, srcCleanup := newTestBackend(t)
+	defer srcCleanup()
+
+	root := populateTestBackend(t, src, ns, map[string]string{
+		"key 1": "value 1",
+		"key 2": "value 2",
+		"key 3": "value 3",
+	})
+
+	var export bytes.Buffer
+	err := src.ExportState(ctx, root, &export)
+	require.NoError(err, "ExportState()")
+
+	// Importing into a non-empty backend should fail.
+	_, err = src.ImportState(ctx, root.Hash, bytes.NewReader(export.Bytes()))
+	require.Error(err, "ImportState() should fail for a non-empty backend")
+
+	dst, _, dstCleanup := newTestBackend(t)
+	defer dstCleanup()
+
+	// Importing with an unexpected root should fail.
+	var bogusRoot hash.Hash
+	bogusRoot.FromBytes([]byte("bogus root"))
+	_, err = dst.ImportState(ctx, bogusRoot, bytes.NewReader(export.Bytes()))
+	require.Error(err, "ImportState() should fail for an unexpected root")
+	require.False(dst.nodedb.HasRoot(root), "failed import should not record the root")
+
+	newRoot, err := dst.ImportState(ctx, root.Hash, bytes.NewReader(export.Bytes()))
+	require.NoError(err, "ImportState()")
+	require.Equal(root.Hash, newRoot, "imported root should match")
+	require.True(dst.nodedb.HasRoot(root), "imported root should exist")
+
+	tree := mkvs.NewWithRoot(nil, dst.nodedb, root)
+	defer tree.Close()
+	value, err := tree.Get(ctx, []byte("key 2"))
+	require.NoError(err, "Get()")
+	require.Equal([]byte("value 2"), value)
+}
+
+func TestImportStateRollback(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	src, ns, srcCleanup := newTestBackend(t)
+	defer srcCleanup()
+
+	root := populateTestBackend(t, src, ns, map[string]string{
+		"key 1": "value 1",
+		"key 2": "value 2",
+	})
+
+	// Craft a dump with a valid header but a tampered body so that the root
+	// check only fails once the tree is committed.
+	var export bytes.Buffer
+	err := writeExportRecord(&export, cbor.Marshal(ExportHeader{
+		Version: exportFormatVersion,
+		Root:    root,
+	}))
+	require.NoError(err, "writeExportRecord(header)")
+	for _, kv := range [][2]string{
+		{"key 1", "value 1"},
+		{"key 2", "tampered"},
+	} {
+		err = writeExportRecord(&export, []byte(kv[0]))
+		require.NoError(err, "writeExportRecord(key)")
+		err = writeExportRecord(&export, []byte(kv[1]))
+		require.NoError(err, "writeExportRecord(value)")
+	}
+
+	dst, _, dstCleanup := newTestBackend(t)
+	defer dstCleanup()
+
+	_, err = dst.ImportState(ctx, root.Hash, bytes.NewReader(export.Bytes()))
+	require.Error(err, "ImportState() should fail for a tampered dump")
+	require.True(errors.Is(err, api.ErrExpectedRootMismatch), "ImportState() should fail with a root mismatch")
+	require.False(dst.nodedb.HasRoot(root), "failed import should not record the root")
+	roots, err := dst.nodedb.GetRootsForVersion(ctx, root.Version)
+	require.NoError(err, "GetRootsForVersion()")
+	require.Empty(roots, "failed import should not record any roots")
+
+	// A subsequent valid import should succeed.
+	export.Reset()
+	err = src.ExportState(ctx, root, &export)
+	require.NoError(err, "ExportState()")
+	newRoot, err := dst.ImportState(ctx, root.Hash, bytes.NewReader(export.Bytes()))
+	require.NoError(err, "ImportState()")
+	require.Equal(root.Hash, newRoot, "imported root should match")
+}
+
+func TestReadExportRecordTooLarge(t *testing.T) {
+	require := require.New(t)
+
+	var buf bytes.Buffer
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], maxExportRecordSize+1)
+	buf.Write(length[:])
+
+	_, err := readExportRecord(&buf)
+	require.Error(err, "readExportRecord() should fail for an oversized record")
+
+	err = writeExportRecord(&buf, make([]byte, maxExportRecordSize+1))
+	require.Error(err, "writeExportRecord() should fail for an oversized record")
+}
+
+// blockingSigner is a signer that blocks until unblocked.
+type blockingSigner struct {
+	signature.Signer
+
+	unblockCh chan struct{}
+}
+
+func (s *blockingSigner) ContextSign(context signature.Context, message []byte) ([]byte, error) {
+	<-s.unblockCh
+	return s.Signer.ContextSign(context, message)
+}
+
+func TestSignReceiptCancel(t *testing.T) {
+	require := require.New(t)
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	signer := &blockingSigner{
+		Signer:    ba.signer,
+		unblockCh: make(chan struct{}),
+	}
+	defer close(signer.unblockCh)
+	ba.signer = signer
+
+	var root hash.Hash
+	root.Empty()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	receipt, err := ba.signReceipt(ctx, ns, 1, []hash.Hash{root})
+	require.Equal(context.DeadlineExceeded, err, "signReceipt() should fail with a cancelled context")
+	require.Nil(receipt, "signReceipt() should not return a receipt when cancelled")
+}
+
+func TestLastAppliedRoot(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	_, err := ba.LastAppliedRoot(ctx)
+	require.Equal(api.ErrNoAppliedRoot, err, "LastAppliedRoot() should fail on a fresh database")
+
+	// Compute the expected root without persisting anything.
+	wl := api.WriteLog{{Key: []byte("key"), Value: []byte("value")}}
+	tree := mkvs.New(nil, nil)
+	defer tree.Close()
+	err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(wl))
+	require.NoError(err, "ApplyWriteLog()")
+	_, dstRoot, err := tree.Commit(ctx, ns, 1)
+	require.NoError(err, "Commit()")
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+	_, err = ba.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  1,
+		SrcRoot:   emptyRoot,
+		DstRound:  1,
+		DstRoot:   dstRoot,
+		WriteLog:  wl,
+	})
+	require.NoError(err, "Apply()")
+
+	lastRoot, err := ba.LastAppliedRoot(ctx)
+	require.NoError(err, "LastAppliedRoot()")
+	require.Equal(dstRoot, lastRoot, "LastAppliedRoot() should return the applied root")
+}
+
+func TestEstimateApply(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	root := populateTestBackend(t, ba, ns, map[string]string{
+		"key 1": "value 1",
+		"key 2": "value 2",
+	})
+
+	wl := api.WriteLog{
+		{Key: []byte("key 2"), Value: []byte("new value 2")},
+		{Key: []byte("key 3"), Value: []byte("value 3")},
+	}
+	estimate, err := ba.EstimateApply(ctx, root.Hash, wl)
+	require.NoError(err, "EstimateApply()")
+	require.NotEqual(root.Hash, estimate.NewRoot, "EstimateApply() should compute a new root")
+	require.NotZero(estimate.NewNodes, "EstimateApply() should count new nodes")
+	require.NotZero(estimate.NewNodeBytes, "EstimateApply() should count new node bytes")
+
+	dstRoot := api.Root{Namespace: ns, Version: root.Version + 1, Hash: estimate.NewRoot}
+	require.False(ba.nodedb.HasRoot(dstRoot), "EstimateApply() should not persist the new root")
+
+	// Applying the write log should result in the estimated root.
+	_, err = ba.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  root.Version,
+		SrcRoot:   root.Hash,
+		DstRound:  dstRoot.Version,
+		DstRoot:   dstRoot.Hash,
+		WriteLog:  wl,
+	})
+	require.NoError(err, "Apply()")
+	require.True(ba.nodedb.HasRoot(dstRoot), "Apply() should persist the estimated root")
+
+	_, err = ba.EstimateApply(ctx, hash.NewFromBytes([]byte("unknown root")), wl)
+	require.True(errors.Is(err, nodedb.ErrRootNotFound), "EstimateApply() should fail for an unknown root")
+}
+
+func TestApplyChain(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	base := populateTestBackend(t, ba, ns, map[string]string{
+		"key 1": "value 1",
+	})
+
+	logs := []api.WriteLog{
+		{{Key: []byte("key 2"), Value: []byte("value 2")}},
+		{{Key: []byte("key 1"), Value: []byte("new value 1")}},
+		{{Key: []byte("key 2"), Value: nil}},
+	}
+	roots, receipt, err := ba.ApplyChain(ctx, base, base.Version+1, logs)
+	require.NoError(err, "ApplyChain()")
+	require.Len(roots, len(logs), "ApplyChain() should return all intermediate roots")
+
+	var body api.ReceiptBody
+	err = receipt.Open(&body)
+	require.NoError(err, "receipt.Open()")
+	require.Equal([]hash.Hash{roots[len(roots)-1]}, body.Roots, "receipt should cover the final root")
+	require.EqualValues(base.Version+1, body.Round, "receipt should be for the next version")
+
+	for i, root := range roots {
+		require.True(ba.nodedb.HasRoot(api.Root{Namespace: ns, Version: base.Version + 1, Hash: root}),
+			"ApplyChain() should persist root %d", i,
+		)
+	}
+	lastApplied, err := ba.LastAppliedRoot(ctx)
+	require.NoError(err, "LastAppliedRoot()")
+	require.Equal(roots[len(roots)-1], lastApplied, "last applied root should be the final root")
+
+	// Each log should be applied on top of the previous root.
+	children, err := ba.GetChildRoots(ctx, roots[0])
+	require.NoError(err, "GetChildRoots()")
+	require.Equal([]hash.Hash{roots[1]}, children, "second root should be derived from the first")
+
+	tree := mkvs.NewWithRoot(nil, ba.nodedb, api.Root{Namespace: ns, Version: base.Version + 1, Hash: roots[2]})
+	defer tree.Close()
+	value, err := tree.Get(ctx, []byte("key 1"))
+	require.NoError(err, "Get()")
+	require.EqualValues("new value 1", value, "final root should contain all updates")
+	value, err = tree.Get(ctx, []byte("key 2"))
+	require.NoError(err, "Get()")
+	require.Nil(value, "final root should contain all updates")
+
+	// A chain with an invalid write log should not be applied at all.
+	ba.maxValueSize = 8
+	finalRoot := api.Root{Namespace: ns, Version: base.Version + 1, Hash: roots[2]}
+	_, _, err = ba.ApplyChain(ctx, finalRoot, finalRoot.Version+1, []api.WriteLog{
+		{{Key: []byte("key 3"), Value: []byte("value 3")}},
+		{{Key: []byte("key 4"), Value: []byte("value too large")}},
+	})
+	require.True(errors.Is(err, api.ErrValueTooLarge), "ApplyChain() should fail with a too large value")
+	versionRoots, err := ba.nodedb.GetRootsForVersion(ctx, base.Version+2)
+	require.NoError(err, "GetRootsForVersion()")
+	require.Empty(versionRoots, "nothing should be applied")
+	ba.maxValueSize = 0
+
+	unknownRoot := base
+	unknownRoot.Hash = hash.NewFromBytes([]byte("unknown root"))
+	_, _, err = ba.ApplyChain(ctx, unknownRoot, unknownRoot.Version+1, logs)
+	require.True(errors.Is(err, nodedb.ErrRootNotFound), "ApplyChain() should fail for an unknown root")
+}
+
+func TestApplyChainCarriedRoot(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	base := populateTestBackend(t, ba, ns, map[string]string{
+		"key 1": "value 1",
+	})
+
+	// Carry the base root unchanged into the next, finalized version (e.g., an empty block).
+	_, err := ba.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  base.Version,
+		SrcRoot:   base.Hash,
+		DstRound:  base.Version + 1,
+		DstRoot:   base.Hash,
+	})
+	require.NoError(err, "Apply(no-op)")
+	err = ba.nodedb.Finalize(ctx, base.Version, []hash.Hash{base.Hash})
+	require.NoError(err, "Finalize()")
+	err = ba.nodedb.Finalize(ctx, base.Version+1, []hash.Hash{base.Hash})
+	require.NoError(err, "Finalize()")
+
+	// The chain should be applied in the requested version, following the latest version
+	// containing the base root.
+	carried := base
+	carried.Version++
+	logs := []api.WriteLog{
+		{{Key: []byte("key 2"), Value: []byte("value 2")}},
+		{{Key: []byte("key 3"), Value: []byte("value 3")}},
+	}
+	roots, receipt, err := ba.ApplyChain(ctx, carried, carried.Version+1, logs)
+	require.NoError(err, "ApplyChain()")
+
+	var body api.ReceiptBody
+	err = receipt.Open(&body)
+	require.NoError(err, "receipt.Open()")
+	require.EqualValues(carried.Version+1, body.Round, "receipt should be for the requested version")
+	for i, root := range roots {
+		require.True(ba.nodedb.HasRoot(api.Root{Namespace: ns, Version: carried.Version + 1, Hash: root}),
+			"ApplyChain() should persist root %d in the requested version", i,
+		)
+	}
+}
+
+func TestGetChildRoots(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	base := populateTestBackend(t, ba, ns, map[string]string{
+		"key 1": "value 1",
+	})
+
+	// Derive one root in the same and one in the next version.
+	var children []hash.Hash
+	for i, dstVersion := range []uint64{base.Version, base.Version + 1} {
+		wl := api.WriteLog{{Key: []byte("key 2"), Value: []byte(fmt.Sprintf("value %d", i))}}
+
+		tree := mkvs.NewWithRoot(nil, ba.nodedb, base)
+		err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(wl))
+		require.NoError(err, "ApplyWriteLog()")
+		_, dstRoot, err := tree.Commit(ctx, ns, dstVersion, mkvs.NoPersist())
+		require.NoError(err, "Commit()")
+		tree.Close()
+
+		_, err = ba.Apply(ctx, &api.ApplyRequest{
+			Namespace: ns,
+			SrcRound:  base.Version,
+			SrcRoot:   base.Hash,
+			DstRound:  dstVersion,
+			DstRoot:   dstRoot,
+			WriteLog:  wl,
+		})
+		require.NoError(err, "Apply()")
+		children = append(children, dstRoot)
+	}
+	sort.Slice(children, func(i, j int) bool { return bytes.Compare(children[i][:], children[j][:]) < 0 })
+
+	roots, err := ba.GetChildRoots(ctx, base.Hash)
+	require.NoError(err, "GetChildRoots()")
+	require.Equal(children, roots, "GetChildRoots() should return all derived roots")
+
+	// Store the same base root under the next version and derive another root from it there.
+	_, err = ba.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  base.Version,
+		SrcRoot:   base.Hash,
+		DstRound:  base.Version + 1,
+		DstRoot:   base.Hash,
+	})
+	require.NoError(err, "Apply(no-op)")
+
+	wl := api.WriteLog{{Key: []byte("key 3"), Value: []byte("value 3")}}
+	laterBase := base
+	laterBase.Version++
+	tree := mkvs.NewWithRoot(nil, ba.nodedb, laterBase)
+	err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(wl))
+	require.NoError(err, "ApplyWriteLog()")
+	_, dstRoot, err := tree.Commit(ctx, ns, laterBase.Version+1, mkvs.NoPersist())
+	require.NoError(err, "Commit()")
+	tree.Close()
+
+	_, err = ba.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  laterBase.Version,
+		SrcRoot:   laterBase.Hash,
+		DstRound:  laterBase.Version + 1,
+		DstRoot:   dstRoot,
+		WriteLog:  wl,
+	})
+	require.NoError(err, "Apply()")
+
+	roots, err = ba.GetChildRoots(ctx, base.Hash)
+	require.NoError(err, "GetChildRoots()")
+	require.Contains(roots, dstRoot, "GetChildRoots() should return roots derived in any version")
+	require.NotContains(roots, base.Hash, "GetChildRoots() should not return the root itself")
+	for _, child := range children {
+		require.Contains(roots, child, "GetChildRoots() should return roots derived in any version")
+	}
+
+	roots, err = ba.GetChildRoots(ctx, children[0])
+	require.NoError(err, "GetChildRoots(leaf)")
+	require.NotNil(roots, "GetChildRoots(leaf) should return an empty slice")
+	require.Empty(roots, "GetChildRoots(leaf) should return an empty slice")
+
+	roots, err = ba.GetChildRoots(ctx, hash.NewFromBytes([]byte("unknown root")))
+	require.NoError(err, "GetChildRoots(unknown)")
+	require.Empty(roots, "GetChildRoots(unknown) should return an empty slice")
+}
+
+func TestMaxValueSize(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+	ba.maxValueSize = 8
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+	wl := api.WriteLog{
+		{Key: []byte("small"), Value: []byte("value")},
+		{Key: []byte("large"), Value: []byte("value too large")},
+	}
+
+	_, err := ba.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  1,
+		SrcRoot:   emptyRoot,
+		DstRound:  1,
+		DstRoot:   emptyRoot,
+		WriteLog:  wl,
+	})
+	require.True(errors.Is(err, api.ErrValueTooLarge), "Apply() should fail with a too large value")
+
+	_, err = ba.ApplyBatch(ctx, &api.ApplyBatchRequest{
+		Namespace: ns,
+		DstRound:  1,
+		Ops: []api.ApplyOp{
+			{SrcRound: 1, SrcRoot: emptyRoot, DstRoot: emptyRoot, WriteLog: wl[:1]},
+			{SrcRound: 1, SrcRoot: emptyRoot, DstRoot: emptyRoot, WriteLog: wl[1:]},
+		},
+	})
+	require.True(errors.Is(err, api.ErrValueTooLarge), "ApplyBatch() should fail with a too large value")
+
+	_, err = ba.LastAppliedRoot(ctx)
+	require.Equal(api.ErrNoAppliedRoot, err, "nothing should be applied")
+	roots, err := ba.nodedb.GetRootsForVersion(ctx, 1)
+	require.NoError(err, "GetRootsForVersion()")
+	require.Empty(roots, "nothing should be applied")
+}
+
+func TestApplyDeletes(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	insertWl := api.WriteLog{
+		{Key: []byte("key 1"), Value: []byte("value 1")},
+		{Key: []byte("key 2"), Value: []byte("value 2")},
+		{Key: []byte("key 3"), Value: []byte("value 3")},
+	}
+	deleteWl := api.WriteLog{
+		{Key: []byte("key 2"), Value: nil},
+	}
+
+	// Compute the expected roots without persisting anything.
+	tree := mkvs.New(nil, nil)
+	defer tree.Close()
+	err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(insertWl))
+	require.NoError(err, "ApplyWriteLog(insert)")
+	_, insertRoot, err := tree.Commit(ctx, ns, 1)
+	require.NoError(err, "Commit(insert)")
+	err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(deleteWl))
+	require.NoError(err, "ApplyWriteLog(delete)")
+	_, deleteRoot, err := tree.Commit(ctx, ns, 2)
+	require.NoError(err, "Commit(delete)")
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+	_, err = ba.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  1,
+		SrcRoot:   emptyRoot,
+		DstRound:  1,
+		DstRoot:   insertRoot,
+		WriteLog:  insertWl,
+	})
+	require.NoError(err, "Apply(insert)")
+	_, err = ba.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  1,
+		SrcRoot:   insertRoot,
+		DstRound:  2,
+		DstRoot:   deleteRoot,
+		WriteLog:  deleteWl,
+	})
+	require.NoError(err, "Apply(delete)")
+
+	dstTree := mkvs.NewWithRoot(nil, ba.nodedb, api.Root{Namespace: ns, Version: 2, Hash: deleteRoot})
+	defer dstTree.Close()
+
+	// Delete then read.
+	value, err := dstTree.Get(ctx, []byte("key 2"))
+	require.NoError(err, "Get(deleted)")
+	require.Nil(value, "Get() should not return a value for a deleted key")
+	value, err = dstTree.Get(ctx, []byte("key 1"))
+	require.NoError(err, "Get()")
+	require.Equal([]byte("value 1"), value, "Get() should return the value for a remaining key")
+
+	// Delete then iterate.
+	it := dstTree.NewIterator(ctx)
+	defer it.Close()
+	var keys []string
+	for it.Rewind(); it.Valid(); it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	require.NoError(it.Err(), "iterator")
+	require.Equal([]string{"key 1", "key 3"}, keys, "iterator should skip deleted keys")
+}
+
+func TestHealthCheck(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	health, err := ba.HealthCheck(ctx)
+	require.NoError(err, "HealthCheck()")
+	require.True(health.DatabaseOpen, "database should be open")
+	require.Nil(health.LastAppliedRoot, "there should be no last applied root on a fresh database")
+	require.False(health.VerifiedOnStart, "consistency check should not be reported when disabled")
+
+	root := populateTestBackend(t, ba, ns, map[string]string{
+		"foo": "bar",
+	})
+	err = ba.nodedb.SetLastAppliedRoot(ctx, root.Hash)
+	require.NoError(err, "SetLastAppliedRoot()")
+
+	health, err = ba.HealthCheck(ctx)
+	require.NoError(err, "HealthCheck()")
+	require.NotNil(health.LastAppliedRoot, "last applied root should be reported")
+	require.Equal(root.Hash, *health.LastAppliedRoot, "last applied root should be correct")
+
+	ba.Cleanup()
+	health, err = ba.HealthCheck(ctx)
+	require.NoError(err, "HealthCheck()")
+	require.False(health.DatabaseOpen, "database should not be open after cleanup")
+}
+
+func TestPendingApplies(t *testing.T) {
+	require := require.New(t)
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+	ba.applySem = make(chan struct{}, 1)
+
+	require.Equal(0, ba.PendingApplies(), "PendingApplies() should be zero initially")
+
+	// Occupy the only apply slot.
+	done, err := ba.beginApply(context.Background())
+	require.NoError(err, "beginApply()")
+	require.Equal(1, ba.PendingApplies(), "PendingApplies() should count in-progress applies")
+	require.EqualValues(1, testutil.ToFloat64(pendingApplies.With(ba.getMetricLabels())), "pending applies metric")
+
+	// Further applies should block until the slot is released.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+	_, err = ba.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  1,
+		SrcRoot:   emptyRoot,
+		DstRound:  1,
+		DstRoot:   emptyRoot,
+	})
+	require.Error(err, "Apply() should block while the limit is reached")
+	require.True(errors.Is(err, context.DeadlineExceeded), "Apply() should fail with the context error")
+	require.Equal(1, ba.PendingApplies(), "PendingApplies() should not count aborted applies")
+
+	done()
+	require.Equal(0, ba.PendingApplies(), "PendingApplies() should be zero after all applies finish")
+	require.EqualValues(0, testutil.ToFloat64(pendingApplies.With(ba.getMetricLabels())), "pending applies metric")
+
+	// Metrics of backends for other runtimes should not be affected.
+	otherLabels := prometheus.Labels{"runtime": common.NewTestNamespaceFromSeed([]byte("other ns"), 0).String()}
+	require.EqualValues(0, testutil.ToFloat64(pendingAppliesHighWatermark.With(otherLabels)), "other runtime metric")
+}
+
+func TestResolveRootPrefix(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	root := populateTestBackend(t, ba, ns, map[string]string{
+		"foo": "bar",
+	})
+
+	roots, err := ba.ResolveRootPrefix(ctx, root.Hash[:4])
+	require.NoError(err, "ResolveRootPrefix()")
+	require.Equal([]hash.Hash{root.Hash}, roots, "ResolveRootPrefix() should return the matching root")
+
+	roots, err = ba.ResolveRootPrefix(ctx, root.Hash[:])
+	require.NoError(err, "ResolveRootPrefix()")
+	require.Equal([]hash.Hash{root.Hash}, roots, "ResolveRootPrefix() should match the full hash")
+
+	mismatch := append([]byte{}, root.Hash[:4]...)
+	mismatch[0] ^= 0xff
+	roots, err = ba.ResolveRootPrefix(ctx, mismatch)
+	require.NoError(err, "ResolveRootPrefix()")
+	require.Empty(roots, "ResolveRootPrefix() should return no roots for a non-matching prefix")
+	require.NotNil(roots, "ResolveRootPrefix() should return an empty slice for no match")
+
+	ba.maxRootPrefixMatches = 0
+	roots, err = ba.ResolveRootPrefix(ctx, nil)
+	require.NoError(err, "ResolveRootPrefix()")
+	require.Empty(roots, "ResolveRootPrefix() should respect the limit")
+}
+
+func TestWatchApplies(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	ch, sub, err := ba.WatchApplies(ctx)
+	require.NoError(err, "WatchApplies()")
+	defer sub.Close()
+
+	wl := api.WriteLog{{Key: []byte("key"), Value: []byte("value")}}
+	tree := mkvs.New(nil, nil)
+	defer tree.Close()
+	err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(wl))
+	require.NoError(err, "ApplyWriteLog()")
+	_, dstRoot, err := tree.Commit(ctx, ns, 1)
+	require.NoError(err, "Commit()")
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+	_, err = ba.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  1,
+		SrcRoot:   emptyRoot,
+		DstRound:  1,
+		DstRoot:   dstRoot,
+		WriteLog:  wl,
+	})
+	require.NoError(err, "Apply()")
+
+	select {
+	case root := <-ch:
+		require.Equal(dstRoot, root, "WatchApplies() should emit the applied root")
+	case <-time.After(recvTimeout):
+		t.Fatalf("failed to receive applied root")
+	}
+
+	// Cleanup should close all subscriptions.
+	ba.Cleanup()
+	select {
+	case _, ok := <-ch:
+		require.False(ok, "subscription channel should be closed on Cleanup")
+	case <-time.After(recvTimeout):
+		t.Fatalf("subscription channel was not closed on Cleanup")
+	}
+
+	_, _, err = ba.WatchApplies(ctx)
+	require.Error(err, "WatchApplies() should fail after Cleanup")
+}
+
+func TestRemoteFallback(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	remote, ns, remoteCleanup := newTestBackend(t)
+	defer remoteCleanup()
+
+	srcRoot := populateTestBackend(t, remote, ns, map[string]string{
+		"foo": "bar",
+		"moo": "goo",
+	})
+
+	dir, err := ioutil.TempDir("", "oasis-storage-database-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(dir)
+
+	signer, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner()")
+
+	impl, err := New(&api.Config{
+		Backend:                      BackendNameBadgerDB,
+		DB:                           filepath.Join(dir, DefaultFileName(BackendNameBadgerDB)),
+		Signer:                       signer,
+		ApplyLockLRUSlots:            100,
+		Namespace:                    ns,
+		NoFsync:                      true,
+		RemoteFallback:               remote,
+		RemoteFallbackMaxConcurrency: 1,
+	})
+	require.NoError(err, "New()")
+	defer impl.Cleanup()
+	local := impl.(*databaseBackend)
+
+	// Compute the expected root after applying the write log on top of the remote root.
+	wl := api.WriteLog{{Key: []byte("foo"), Value: []byte("baz")}}
+	tree := mkvs.NewWithRoot(remote, nil, srcRoot)
+	defer tree.Close()
+	err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(wl))
+	require.NoError(err, "ApplyWriteLog()")
+	_, dstRoot, err := tree.Commit(ctx, ns, 2)
+	require.NoError(err, "Commit()")
+
+	// The source root is not available locally, so missing nodes must be fetched remotely.
+	_, err = local.Apply(ctx, &api.ApplyRequest{
+		Namespace: ns,
+		SrcRound:  srcRoot.Version,
+		SrcRoot:   srcRoot.Hash,
+		DstRound:  2,
+		DstRoot:   dstRoot,
+		WriteLog:  wl,
+	})
+	require.NoError(err, "Apply()")
+
+	dstTreeRoot := api.Root{Namespace: ns, Version: 2, Hash: dstRoot}
+
+	// Updated nodes should be available locally without the remote.
+	localTree := mkvs.NewWithRoot(nil, local.nodedb, dstTreeRoot)
+	defer localTree.Close()
+	value, err := localTree.Get(ctx, []byte("foo"))
+	require.NoError(err, "Get()")
+	require.EqualValues([]byte("baz"), value, "updated nodes should be persisted locally")
+
+	// Reads against the source root should be served from the remote on demand.
+	fallbackTree, err := local.rootCache.GetTree(ctx, srcRoot)
+	require.NoError(err, "GetTree()")
+	defer fallbackTree.Close()
+	value, err = fallbackTree.Get(ctx, []byte("moo"))
+	require.NoError(err, "Get()")
+	require.EqualValues([]byte("goo"), value, "missing nodes should be fetched from the remote")
+}
+
+func TestOpenLocked(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-storage-database-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(dir)
+
+	cfg := api.Config{
+		Backend:           BackendNameBadgerDB,
+		DB:                filepath.Join(dir, DefaultFileName(BackendNameBadgerDB)),
+		ApplyLockLRUSlots: 100,
+		Namespace:         common.NewTestNamespaceFromSeed([]byte("database backend test ns"), 0),
+		NoFsync:           true,
+		OpenRetries:       2,
+		OpenRetryInterval: 10 * time.Millisecond,
+	}
+	impl, err := New(&cfg)
+	require.NoError(err, "New()")
+	defer impl.Cleanup()
+
+	// The database is held open by the first backend, so opening it again must fail once all
+	// retries have been exhausted.
+	_, err = New(&cfg)
+	require.Error(err, "New() should fail for a locked database")
+	require.True(errors.Is(err, ErrDatabaseLocked), "New() should fail with ErrDatabaseLocked")
+}
+
+// corruptingNodeDB is a node database that returns tampered leaf nodes.
+type corruptingNodeDB struct {
+	nodedb.NodeDB
+}
+
+func (c *corruptingNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, error) {
+	n, err := c.NodeDB.GetNode(root, ptr)
+	if err != nil {
+		return nil, err
+	}
+	if leaf, ok := n.(*node.LeafNode); ok {
+		tampered := &node.LeafNode{
+			Key:   leaf.Key,
+			Value: append([]byte("tampered "), leaf.Value...),
+		}
+		tampered.UpdateHash()
+		return tampered, nil
+	}
+	return n, nil
+}
+
+func TestVerifyRecentRoots(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	root := populateTestBackend(t, ba, ns, map[string]string{
+		"foo": "bar",
+		"moo": "goo",
+	})
+	err := ba.nodedb.Finalize(ctx, root.Version, []hash.Hash{root.Hash})
+	require.NoError(err, "Finalize()")
+
+	err = verifyRecentRoots(ctx, ba.nodedb, ns, verifyOnStartNumVersions)
+	require.NoError(err, "verifyRecentRoots() should succeed on an intact database")
+
+	err = verifyRecentRoots(ctx, &corruptingNodeDB{ba.nodedb}, ns, verifyOnStartNumVersions)
+	require.Error(err, "verifyRecentRoots() should fail on a corrupted database")
+	require.Contains(err.Error(), root.Hash.String(), "error should identify the corrupted root")
+}
+
+// countingNodeDB is a node database that counts node fetches by hash.
+type countingNodeDB struct {
+	nodedb.NodeDB
+
+	fetches map[hash.Hash]int
+}
+
+func (c *countingNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, error) {
+	c.fetches[ptr.Hash]++
+	return c.NodeDB.GetNode(root, ptr)
+}
+
+func TestVerifyRecentRootsSharedNodes(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	root := populateTestBackend(t, ba, ns, map[string]string{
+		"foo": "bar",
+		"moo": "goo",
+	})
+	err := ba.nodedb.Finalize(ctx, root.Version, []hash.Hash{root.Hash})
+	require.NoError(err, "Finalize()")
+
+	// Derive the next version so that most nodes are shared between the roots.
+	tree := mkvs.NewWithRoot(nil, ba.nodedb, root)
+	defer tree.Close()
+	err = tree.Insert(ctx, []byte("zoo"), []byte("boo"))
+	require.NoError(err, "Insert()")
+	_, rootHash, err := tree.Commit(ctx, ns, root.Version+1)
+	require.NoError(err, "Commit()")
+	err = ba.nodedb.Finalize(ctx, root.Version+1, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize()")
+
+	cdb := &countingNodeDB{NodeDB: ba.nodedb, fetches: make(map[hash.Hash]int)}
+	err = verifyRecentRoots(ctx, cdb, ns, verifyOnStartNumVersions)
+	require.NoError(err, "verifyRecentRoots()")
+	for h, n := range cdb.fetches {
+		require.Equal(1, n, "node %s should only be verified once", h)
+	}
+}
+
+func BenchmarkApplyDetectConflicts(b *testing.B) {
+	benchmarkApply(b, true)
+}
+
+func BenchmarkApplyNoDetectConflicts(b *testing.B) {
+	benchmarkApply(b, false)
+}
+
+func benchmarkApply(b *testing.B, detectConflicts bool) {
+	genesisTestHelpers.SetTestChainContext()
+
+	ctx := context.Background()
+	testNs := common.NewTestNamespaceFromSeed([]byte("database backend bench ns"), 0)
+
+	dir, err := ioutil.TempDir("", "oasis-storage-database-bench")
+	require.NoError(b, err, "TempDir()")
+	defer os.RemoveAll(dir)
+
+	signer, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(b, err, "NewSigner()")
+
+	impl, err := New(&api.Config{
+		Backend:           BackendNameBadgerDB,
+		DB:                filepath.Join(dir, DefaultFileName(BackendNameBadgerDB)),
+		Signer:            signer,
+		ApplyLockLRUSlots: 100,
+		// Skip known root checks so that we don't need to precompute roots.
+		InsecureSkipChecks: true,
+		Namespace:          testNs,
+		MaxCacheSize:       16 * 1024 * 1024,
+		NoFsync:            true,
+		DetectConflicts:    detectConflicts,
+	})
+	require.NoError(b, err, "New()")
+	defer impl.Cleanup()
+
+	var srcRoot hash.Hash
+	srcRoot.Empty()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var wl api.WriteLog
+		for i := 0; i < 100; i++ {
+			wl = append(wl, api.LogEntry{
+				Key:   []byte(fmt.Sprintf("key %d", i)),
+				Value: []byte(fmt.Sprintf("value %d %d", n, i)),
+			})
+		}
+
+		receipts, err := impl.Apply(ctx, &api.ApplyRequest{
+			Namespace: testNs,
+			SrcRound:  uint64(n),
+			SrcRoot:   srcRoot,
+			DstRound:  uint64(n) + 1,
+			WriteLog:  wl,
+		})
+		require.NoError(b, err, "Apply()")
+
+		var body api.ReceiptBody
+		err = receipts[0].Open(&body)
+		require.NoError(b, err, "Open()")
+		srcRoot = body.Roots[0]
+	}
+}
+
+func TestReSignReceipt(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	root := populateTestBackend(t, ba, ns, map[string]string{
+		"key 1": "value 1",
+	})
+	receipt, err := ba.signReceipt(ctx, ns, root.Version, []hash.Hash{root.Hash})
+	require.NoError(err, "signReceipt()")
+	var body api.ReceiptBody
+	err = receipt.Open(&body)
+	require.NoError(err, "Open()")
+
+	// Rotate the signing key.
+	ba.signer, err = memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner()")
+
+	newReceipt, err := ba.ReSignReceipt(ctx, body)
+	require.NoError(err, "ReSignReceipt()")
+	require.Equal(ba.signer.Public(), newReceipt.Signature.PublicKey, "receipt should be signed by the current key")
+	var newBody api.ReceiptBody
+	err = newReceipt.Open(&newBody)
+	require.NoError(err, "Open()")
+	require.Equal(body, newBody, "receipt body should not change")
+
+	// Receipts for roots that are not stored should not be re-signed.
+	unknownBody := body
+	unknownBody.Roots = []hash.Hash{hash.NewFromBytes([]byte("unknown root"))}
+	_, err = ba.ReSignReceipt(ctx, unknownBody)
+	require.True(errors.Is(err, nodedb.ErrRootNotFound), "ReSignReceipt() should fail for an unknown root")
+
+	otherNsBody := body
+	otherNsBody.Namespace = common.NewTestNamespaceFromSeed([]byte("other ns"), 0)
+	_, err = ba.ReSignReceipt(ctx, otherNsBody)
+	require.Error(err, "ReSignReceipt() should fail for a different namespace")
+}
+
+func TestCurrentSigningKeyID(t *testing.T) {
+	require := require.New(t)
+
+	ba, ns, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	keyID, err := ba.CurrentSigningKeyID()
+	require.NoError(err, "CurrentSigningKeyID")
+	require.Equal(ba.signer.Public(), keyID, "key ID should match the signer")
+
+	// Receipts should identify the key that signed them.
+	receipt, err := ba.signReceipt(context.Background(), ns, 1, []hash.Hash{hash.NewFromBytes([]byte("root"))})
+	require.NoError(err, "signReceipt")
+	require.Equal(keyID, receipt.Signature.PublicKey, "receipt should be signed by the current key")
+
+	// Without a signer there is no current signing key.
+	ba.signer = nil
+	_, err = ba.CurrentSigningKeyID()
+	require.Equal(api.ErrCantProve, err, "CurrentSigningKeyID should fail without a signer")
+}
@@ -0,0 +1,214 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+const (
+	// exportFormatVersion is the logical state export format version.
+	exportFormatVersion = 1
+
+	// maxExportRecordSize is the maximum size of a single export record.
+	maxExportRecordSize = 16 * 1024 * 1024
+
+	// importChunkSize is the approximate amount of key/value data that is
+	// buffered before being applied to the tree during import.
+	importChunkSize = 16 * 1024 * 1024
+)
+
+// ExportHeader is the header of a logical state export.
+//
+// A logical state export is a sequence of records, each encoded as a big-endian
+// uint32 length followed by that many bytes. The first record is the
+// CBOR-serialized ExportHeader. It is followed by alternating key and value
+// records for all entries in the exported tree, in key order. No record may
+// exceed 16 MiB.
+type ExportHeader struct {
+	// Version is the export format version.
+	Version uint16 `json:"version"`
+	// Root is the root of the exported tree.
+	Root api.Root `json:"root"`
+}
+
+func writeExportRecord(w io.Writer, data []byte) error {
+	if len(data) > maxExportRecordSize {
+		return fmt.Errorf("record too large (%d bytes)", len(data))
+	}
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func readExportRecord(r io.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > maxExportRecordSize {
+		return nil, fmt.Errorf("record too large (%d bytes)", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ExportState writes a logical dump of all key/value pairs in the tree with the
+// given root to the given writer, in key order.
+//
+// Unlike checkpoints, the export is self-describing and can be consumed without
+// any knowledge of the MKVS node format.
+func (ba *databaseBackend) ExportState(ctx context.Context, root api.Root, w io.Writer) error {
+	if !ba.nodedb.HasRoot(root) {
+		return fmt.Errorf("storage/database: failed to ExportState: %w", api.ErrRootNotFound)
+	}
+
+	tree, err := ba.rootCache.GetTree(ctx, root)
+	if err != nil {
+		return fmt.Errorf("storage/database: failed to ExportState: %w", err)
+	}
+	defer tree.Close()
+
+	bw := bufio.NewWriter(w)
+	header := ExportHeader{
+		Version: exportFormatVersion,
+		Root:    root,
+	}
+	if err = writeExportRecord(bw, cbor.Marshal(header)); err != nil {
+		return fmt.Errorf("storage/database: failed to write export header: %w", err)
+	}
+
+	it := tree.NewIterator(ctx)
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		if err = writeExportRecord(bw, it.Key()); err != nil {
+			return fmt.Errorf("storage/database: failed to write export key: %w", err)
+		}
+		if err = writeExportRecord(bw, it.Value()); err != nil {
+			return fmt.Errorf("storage/database: failed to write export value: %w", err)
+		}
+	}
+	if err = it.Err(); err != nil {
+		return fmt.Errorf("storage/database: failed to iterate tree: %w", err)
+	}
+
+	return bw.Flush()
+}
+
+// ImportState imports a logical dump produced by ExportState into the backend
+// and returns the hash of the resulting root.
+//
+// The import is refused in case the backend already contains any roots for the
+// exported version. In case the resulting root does not match the expected root,
+// no root is recorded and an error is returned.
+func (ba *databaseBackend) ImportState(ctx context.Context, expectedRoot hash.Hash, r io.Reader) (hash.Hash, error) {
+	if ba.readOnly {
+		return hash.Hash{}, fmt.Errorf("storage/database: failed to ImportState: %w", api.ErrReadOnly)
+	}
+
+	br := bufio.NewReader(r)
+	rawHeader, err := readExportRecord(br)
+	if err != nil {
+		return hash.Hash{}, fmt.Errorf("storage/database: failed to read export header: %w", err)
+	}
+	var header ExportHeader
+	if err = cbor.Unmarshal(rawHeader, &header); err != nil {
+		return hash.Hash{}, fmt.Errorf("storage/database: malformed export header: %w", err)
+	}
+	if header.Version != exportFormatVersion {
+		return hash.Hash{}, fmt.Errorf("storage/database: unsupported export format version: %d", header.Version)
+	}
+	if !header.Root.Hash.Equal(&expectedRoot) {
+		return hash.Hash{}, fmt.Errorf("storage/database: failed to ImportState: %w", api.ErrExpectedRootMismatch)
+	}
+
+	// Refuse to import into a non-empty backend.
+	roots, err := ba.nodedb.GetRootsForVersion(ctx, header.Root.Version)
+	if err != nil {
+		return hash.Hash{}, fmt.Errorf("storage/database: failed to get roots for version: %w", err)
+	}
+	if len(roots) > 0 {
+		return hash.Hash{}, fmt.Errorf("storage/database: refusing to import into non-empty version %d", header.Root.Version)
+	}
+
+	tree := mkvs.New(nil, ba.nodedb)
+	defer tree.Close()
+
+	// Apply the dump in bounded chunks to avoid buffering it as a whole.
+	var (
+		chunk     api.WriteLog
+		chunkSize int
+	)
+	applyChunk := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		if err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(chunk)); err != nil {
+			return fmt.Errorf("storage/database: failed to apply export chunk: %w", err)
+		}
+		chunk = nil
+		chunkSize = 0
+		return nil
+	}
+	for {
+		if err = ctx.Err(); err != nil {
+			return hash.Hash{}, err
+		}
+
+		var key, value []byte
+		key, err = readExportRecord(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return hash.Hash{}, fmt.Errorf("storage/database: failed to read export key: %w", err)
+		}
+		if value, err = readExportRecord(br); err != nil {
+			return hash.Hash{}, fmt.Errorf("storage/database: failed to read export value: %w", err)
+		}
+		chunk = append(chunk, api.LogEntry{Key: key, Value: value})
+		chunkSize += len(key) + len(value)
+
+		if chunkSize >= importChunkSize {
+			if err = applyChunk(); err != nil {
+				return hash.Hash{}, err
+			}
+		}
+	}
+	if err = applyChunk(); err != nil {
+		return hash.Hash{}, err
+	}
+
+	// Only the final root is committed, so nothing is recorded on mismatch.
+	_, err = tree.CommitKnown(ctx, header.Root)
+	switch err {
+	case nil:
+	case mkvs.ErrKnownRootMismatch:
+		return hash.Hash{}, fmt.Errorf("storage/database: failed to ImportState: %w", api.ErrExpectedRootMismatch)
+	default:
+		return hash.Hash{}, fmt.Errorf("storage/database: failed to ImportState: %w", err)
+	}
+	return expectedRoot, nil
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pendingApplies = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_storage_pending_applies",
+			Help: "Number of pending storage apply calls.",
+		},
+		[]string{"runtime"},
+	)
+	pendingAppliesHighWatermark = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_storage_pending_applies_high_watermark",
+			Help: "Highest observed number of pending storage apply calls.",
+		},
+		[]string{"runtime"},
+	)
+
+	databaseCollectors = []prometheus.Collector{
+		pendingApplies,
+		pendingAppliesHighWatermark,
+	}
+
+	metricsOnce sync.Once
+)
+
+func initMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(databaseCollectors...)
+	})
+}
+
+func (ba *databaseBackend) getMetricLabels() prometheus.Labels {
+	return prometheus.Labels{
+		"runtime": ba.namespace.String(),
+	}
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+// defaultRemoteFallbackMaxConcurrency is the default maximum number of concurrent
+// requests to the remote fallback backend.
+const defaultRemoteFallbackMaxConcurrency = 4
+
+// boundedReadSyncer is a read syncer that limits the number of concurrent
+// requests to the underlying read syncer.
+type boundedReadSyncer struct {
+	rs  syncer.ReadSyncer
+	sem chan struct{}
+}
+
+func (b *boundedReadSyncer) acquire(ctx context.Context) error {
+	select {
+	case b.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *boundedReadSyncer) release() {
+	<-b.sem
+}
+
+// Implements syncer.ReadSyncer.
+func (b *boundedReadSyncer) SyncGet(ctx context.Context, request *syncer.GetRequest) (*syncer.ProofResponse, error) {
+	if err := b.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer b.release()
+
+	return b.rs.SyncGet(ctx, request)
+}
+
+// Implements syncer.ReadSyncer.
+func (b *boundedReadSyncer) SyncGetPrefixes(ctx context.Context, request *syncer.GetPrefixesRequest) (*syncer.ProofResponse, error) {
+	if err := b.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer b.release()
+
+	return b.rs.SyncGetPrefixes(ctx, request)
+}
+
+// Implements syncer.ReadSyncer.
+func (b *boundedReadSyncer) SyncIterate(ctx context.Context, request *syncer.IterateRequest) (*syncer.ProofResponse, error) {
+	if err := b.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer b.release()
+
+	return b.rs.SyncIterate(ctx, request)
+}
+
+// newBoundedReadSyncer creates a new read syncer that allows at most maxConcurrency
+// concurrent requests to the given read syncer.
+func newBoundedReadSyncer(rs syncer.ReadSyncer, maxConcurrency int) syncer.ReadSyncer {
+	if maxConcurrency <= 0 {
+		maxConcurrency = defaultRemoteFallbackMaxConcurrency
+	}
+	return &boundedReadSyncer{
+		rs:  rs,
+		sem: make(chan struct{}, maxConcurrency),
+	}
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/storage/api"
+	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// verifyRecentRoots re-verifies the node hashes of all roots in the given number
+// of most recent finalized versions.
+func verifyRecentRoots(ctx context.Context, ndb nodedb.NodeDB, ns common.Namespace, numVersions uint64) error {
+	logger := logging.GetLogger("storage/database")
+
+	latestVersion, err := ndb.GetLatestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+	earliestVersion, err := ndb.GetEarliestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get earliest version: %w", err)
+	}
+
+	startVersion := earliestVersion
+	if latestVersion-earliestVersion >= numVersions {
+		startVersion = latestVersion - numVersions + 1
+	}
+
+	logger.Info("verifying recent roots",
+		"start_version", startVersion,
+		"end_version", latestVersion,
+	)
+
+	// Consecutive roots share most of their nodes, so only verify each node once.
+	verified := make(map[hash.Hash]struct{})
+	for version := startVersion; version <= latestVersion; version++ {
+		roots, err := ndb.GetRootsForVersion(ctx, version)
+		if err != nil {
+			return fmt.Errorf("failed to get roots for version %d: %w", version, err)
+		}
+
+		for _, rootHash := range roots {
+			if rootHash.IsEmpty() {
+				continue
+			}
+			root := api.Root{
+				Namespace: ns,
+				Version:   version,
+				Hash:      rootHash,
+			}
+
+			ptr := &node.Pointer{
+				Clean: true,
+				Hash:  rootHash,
+			}
+			if err = verifyNode(ctx, ndb, root, ptr, verified); err != nil {
+				logger.Error("root failed verification",
+					"root", root,
+					"err", err,
+				)
+				return fmt.Errorf("root %s (version %d) is corrupted: %w", rootHash, version, err)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyNode verifies that the node referenced by the given pointer and all of
+// its descendants are present and match their expected hashes. Nodes in the
+// verified set are skipped and newly verified nodes are added to it.
+func verifyNode(ctx context.Context, ndb nodedb.NodeDB, root api.Root, ptr *node.Pointer, verified map[hash.Hash]struct{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, ok := verified[ptr.Hash]; ok {
+		return nil
+	}
+
+	n, err := ndb.GetNode(root, ptr)
+	if err != nil {
+		return fmt.Errorf("failed to get node %s: %w", ptr.Hash, err)
+	}
+	if h := n.GetHash(); !h.Equal(&ptr.Hash) {
+		return fmt.Errorf("node hash mismatch (expected: %s got: %s)", ptr.Hash, h)
+	}
+
+	if n, ok := n.(*node.InternalNode); ok {
+		for _, child := range []*node.Pointer{n.LeafNode, n.Left, n.Right} {
+			if child == nil {
+				continue
+			}
+			if err = verifyNode(ctx, ndb, root, child, verified); err != nil {
+				return err
+			}
+		}
+	}
+	verified[ptr.Hash] = struct{}{}
+	return nil
+}
@@ -11,7 +11,12 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 )
 
-const moduleName = "storage/mkvs/checkpoint"
+const (
+	moduleName = "storage/mkvs/checkpoint"
+
+	// checkpointVersion is the checkpoint format version used when creating new checkpoints.
+	checkpointVersion = 1
+)
 
 var (
 	// ErrCheckpointNotFound is the error when a checkpoint is not found.
@@ -35,8 +40,33 @@ var (
 
 	// ErrChunkCorrupted is the error when a chunk is corrupted.
 	ErrChunkCorrupted = errors.New(moduleName, 7, "chunk: corrupted chunk")
+
+	// ErrCheckpointAlreadyExists is the error when a checkpoint for the given version already
+	// exists.
+	ErrCheckpointAlreadyExists = errors.New(moduleName, 8, "checkpoint: already exists")
+
+	// ErrUnsupportedVersion is the error when a checkpoint uses an unsupported format version.
+	ErrUnsupportedVersion = errors.New(moduleName, 9, "checkpoint: unsupported format version")
 )
 
+// SupportedVersions is the list of checkpoint format versions that can be served and restored,
+// ordered from the most preferred to the least preferred version.
+//
+// New checkpoints are always created using the most preferred version.
+var SupportedVersions = []uint16{
+	checkpointVersion,
+}
+
+// IsVersionSupported returns true iff the given checkpoint format version is supported.
+func IsVersionSupported(version uint16) bool {
+	for _, v := range SupportedVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
 // ChunkProvider is a chunk provider.
 type ChunkProvider interface {
 	// GetCheckpoints returns a list of checkpoint metadata for all known checkpoints.
@@ -91,6 +91,11 @@ func TestFileCheckpointCreator(t *testing.T) {
 	require.Len(cps, 1, "there should be one checkpoint")
 	require.Equal(cp, cps[0], "checkpoint returned by GetCheckpoint should be correct")
 
+	// There should be no checkpoints in unsupported formats.
+	cps, err = fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: 42})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 0, "there should be no checkpoints in unsupported formats")
+
 	gcp, err := fc.GetCheckpoint(ctx, 1, root)
 	require.NoError(err, "GetCheckpoint")
 	require.Equal(cp, gcp)
@@ -132,6 +137,13 @@ func TestFileCheckpointCreator(t *testing.T) {
 	require.Error(err, "RestoreChunk should fail when no restore is in progress")
 	require.True(errors.Is(err, ErrNoRestoreInProgress))
 
+	// Restoring checkpoints in unsupported formats should fail.
+	unsupportedCp := *cp
+	unsupportedCp.Version = 42
+	err = rs.StartRestore(ctx, &unsupportedCp)
+	require.Error(err, "StartRestore should fail with an unsupported format")
+	require.True(errors.Is(err, ErrUnsupportedVersion))
+
 	// Generate a bogus manifest which does not verify by corrupting chunk at index 1.
 	bogusCp, err := fc.GetCheckpoint(ctx, 1, root)
 	require.NoError(err, "GetCheckpoint")
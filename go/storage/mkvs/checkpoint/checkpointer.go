@@ -47,7 +47,14 @@ type CreationParameters struct {
 	// Interval is the expected runtime state checkpoint interval (in rounds).
 	Interval uint64
 
+	// InitialVersion is the initial version. Periodic checkpoints are created at versions that
+	// are a multiple of Interval past the initial version.
+	InitialVersion uint64
+
 	// NumKept is the expected minimum number of checkpoints to keep.
+	//
+	// Forced checkpoints are included in this number, so forcing a checkpoint may cause an older
+	// periodic checkpoint to be garbage collected early.
 	NumKept uint64
 
 	// ChunkSize is the chunk size parameter for checkpoint creation.
@@ -58,6 +65,17 @@ type CreationParameters struct {
 type Checkpointer interface {
 	// NotifyNewVersion notifies the checkpointer that a new version has been finalized.
 	NotifyNewVersion(version uint64)
+
+	// ForceCheckpoint immediately creates a checkpoint for the given version, regardless of the
+	// configured checkpoint interval, and returns once the checkpoint has been created.
+	//
+	// Forced checkpoints do not affect the versions at which periodic checkpoints are created.
+	ForceCheckpoint(ctx context.Context, version uint64) error
+}
+
+type forceCheckpointRequest struct {
+	version uint64
+	errCh   chan error
 }
 
 type checkpointer struct {
@@ -66,6 +84,7 @@ type checkpointer struct {
 	ndb      db.NodeDB
 	creator  Creator
 	notifyCh *channels.RingChannel
+	forceCh  chan *forceCheckpointRequest
 	statusCh chan struct{}
 
 	logger *logging.Logger
@@ -76,6 +95,93 @@ func (c *checkpointer) NotifyNewVersion(version uint64) {
 	c.notifyCh.In() <- version
 }
 
+// Implements Checkpointer.
+func (c *checkpointer) ForceCheckpoint(ctx context.Context, version uint64) error {
+	req := &forceCheckpointRequest{
+		version: version,
+		errCh:   make(chan error, 1),
+	}
+
+	select {
+	case c.forceCh <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-req.errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *checkpointer) getParameters(ctx context.Context) (*CreationParameters, error) {
+	params := c.cfg.Parameters
+	if params == nil && c.cfg.GetParameters != nil {
+		var err error
+		if params, err = c.cfg.GetParameters(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if params == nil {
+		return nil, fmt.Errorf("checkpointer: no checkpoint parameters")
+	}
+	return params, nil
+}
+
+func (c *checkpointer) forceCheckpoint(ctx context.Context, version uint64) error {
+	params, err := c.getParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("checkpointer: failed to get checkpoint parameters: %w", err)
+	}
+
+	// Make sure the version is available.
+	earlyVersion, err := c.ndb.GetEarliestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("checkpointer: failed to get earliest version: %w", err)
+	}
+	latestVersion, err := c.ndb.GetLatestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("checkpointer: failed to get latest version: %w", err)
+	}
+	if version < earlyVersion || version > latestVersion {
+		return fmt.Errorf("checkpointer: version %d not available (earliest: %d latest: %d)",
+			version, earlyVersion, latestVersion,
+		)
+	}
+
+	// Make sure the version has not yet been checkpointed.
+	cps, err := c.creator.GetCheckpoints(ctx, &GetCheckpointsRequest{
+		Version:   checkpointVersion,
+		Namespace: c.cfg.Namespace,
+	})
+	if err != nil {
+		return fmt.Errorf("checkpointer: failed to get existing checkpoints: %w", err)
+	}
+	var numRoots int
+	for _, cp := range cps {
+		if cp.Root.Version == version {
+			numRoots++
+		}
+	}
+	if numRoots >= c.cfg.RootsPerVersion {
+		return ErrCheckpointAlreadyExists
+	}
+
+	c.logger.Info("forcing checkpoint of version",
+		"version", version,
+	)
+
+	if err = c.checkpoint(ctx, version, params); err != nil {
+		return err
+	}
+
+	// Garbage collect old checkpoints the same way as after periodic checkpoints. The list of
+	// existing checkpoints is from before the forced checkpoint so it is never collected here.
+	return c.garbageCollectAll(ctx, cps, params.NumKept)
+}
+
 func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *CreationParameters) (err error) {
 	var rootHashes []hash.Hash
 	if c.cfg.GetRoots == nil {
@@ -141,20 +247,18 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 		return fmt.Errorf("checkpointer: failed to get existing checkpoints: %w", err)
 	}
 
-	// Check if we need to create a new checkpoint based on the list of existing checkpoints.
+	// Check if we need to create a new checkpoint based on the list of existing checkpoints. Only
+	// periodic checkpoints are considered so that forced checkpoints do not shift the schedule.
 	var lastCheckpointVersion uint64
-	var cpVersions []uint64
 	cpsByVersion := make(map[uint64][]node.Root)
 	for _, cp := range cps {
-		if cpsByVersion[cp.Root.Version] == nil {
-			cpVersions = append(cpVersions, cp.Root.Version)
-		}
 		cpsByVersion[cp.Root.Version] = append(cpsByVersion[cp.Root.Version], cp.Root)
-		if len(cpsByVersion[cp.Root.Version]) == c.cfg.RootsPerVersion && cp.Root.Version > lastCheckpointVersion {
+		if len(cpsByVersion[cp.Root.Version]) == c.cfg.RootsPerVersion &&
+			isPeriodicVersion(cp.Root.Version, params) &&
+			cp.Root.Version > lastCheckpointVersion {
 			lastCheckpointVersion = cp.Root.Version
 		}
 	}
-	sort.Slice(cpVersions, func(i, j int) bool { return cpVersions[i] < cpVersions[j] })
 
 	// Make sure to not start earlier than the earliest version.
 	earlyVersion, err := c.ndb.GetEarliestVersion(ctx)
@@ -167,7 +271,7 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 
 	// Checkpoint any missing versions.
 	cpInterval := params.Interval
-	for cpVersion := lastCheckpointVersion + cpInterval; cpVersion < version; cpVersion = cpVersion + cpInterval {
+	for cpVersion := nextPeriodicVersion(lastCheckpointVersion, params); cpVersion < version; cpVersion = cpVersion + cpInterval {
 		c.logger.Info("checkpointing version",
 			"version", cpVersion,
 		)
@@ -181,29 +285,76 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 		}
 	}
 
-	// Garbage collect old checkpoints.
-	if int(params.NumKept) < len(cpVersions) {
-		c.logger.Info("performing checkpoint garbage collection",
-			"num_checkpoints", len(cpVersions),
-			"num_kept", params.NumKept,
-		)
+	return c.garbageCollectAll(ctx, cps, params.NumKept)
+}
+
+// isPeriodicVersion returns true iff the given version is one at which periodic checkpoints are
+// created.
+func isPeriodicVersion(version uint64, params *CreationParameters) bool {
+	return version >= params.InitialVersion && (version-params.InitialVersion)%params.Interval == 0
+}
+
+// nextPeriodicVersion returns the first version after the given version at which a periodic
+// checkpoint is created. The initial version itself is never checkpointed periodically.
+func nextPeriodicVersion(version uint64, params *CreationParameters) uint64 {
+	if version < params.InitialVersion {
+		version = params.InitialVersion
+	}
+	return params.InitialVersion + ((version-params.InitialVersion)/params.Interval+1)*params.Interval
+}
 
-		for _, version := range cpVersions[:len(cpVersions)-int(params.NumKept)] {
-			for _, root := range cpsByVersion[version] {
-				if err = c.creator.DeleteCheckpoint(ctx, checkpointVersion, root); err != nil {
-					c.logger.Warn("failed to garbage collect checkpoint",
-						"root", root,
-						"err", err,
-					)
-					continue
-				}
+// garbageCollectAll garbage collects old checkpoints of all supported format versions, given the
+// existing checkpoints of the current format version. Checkpoints using older format versions are
+// no longer created, but are served until they are garbage collected.
+func (c *checkpointer) garbageCollectAll(ctx context.Context, cps []*Metadata, numKept uint64) error {
+	for _, cpFormat := range SupportedVersions {
+		if cpFormat != checkpointVersion {
+			var err error
+			if cps, err = c.creator.GetCheckpoints(ctx, &GetCheckpointsRequest{
+				Version:   cpFormat,
+				Namespace: c.cfg.Namespace,
+			}); err != nil {
+				return fmt.Errorf("checkpointer: failed to get existing checkpoints: %w", err)
 			}
 		}
+		c.garbageCollect(ctx, cpFormat, cps, numKept)
 	}
-
 	return nil
 }
 
+func (c *checkpointer) garbageCollect(ctx context.Context, cpFormat uint16, cps []*Metadata, numKept uint64) {
+	var cpVersions []uint64
+	cpsByVersion := make(map[uint64][]node.Root)
+	for _, cp := range cps {
+		if cpsByVersion[cp.Root.Version] == nil {
+			cpVersions = append(cpVersions, cp.Root.Version)
+		}
+		cpsByVersion[cp.Root.Version] = append(cpsByVersion[cp.Root.Version], cp.Root)
+	}
+	if int(numKept) >= len(cpVersions) {
+		return
+	}
+	sort.Slice(cpVersions, func(i, j int) bool { return cpVersions[i] < cpVersions[j] })
+
+	c.logger.Info("performing checkpoint garbage collection",
+		"format", cpFormat,
+		"num_checkpoints", len(cpVersions),
+		"num_kept", numKept,
+	)
+
+	for _, version := range cpVersions[:len(cpVersions)-int(numKept)] {
+		for _, root := range cpsByVersion[version] {
+			if err := c.creator.DeleteCheckpoint(ctx, cpFormat, root); err != nil {
+				c.logger.Warn("failed to garbage collect checkpoint",
+					"root", root,
+					"err", err,
+				)
+				continue
+			}
+		}
+	}
+}
+
 func (c *checkpointer) worker(ctx context.Context) {
 	c.logger.Debug("storage checkpointer started",
 		"check_interval", c.cfg.CheckInterval,
@@ -220,30 +371,27 @@ func (c *checkpointer) worker(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
+		case req := <-c.forceCh:
+			req.errCh <- c.forceCheckpoint(ctx, req.version)
 		case <-ticker.C:
 			var version uint64
 			select {
 			case <-ctx.Done():
 				return
+			case req := <-c.forceCh:
+				req.errCh <- c.forceCheckpoint(ctx, req.version)
+				continue
 			case v := <-c.notifyCh.Out():
 				version = v.(uint64)
 			}
 
 			// Fetch current checkpoint parameters.
-			params := c.cfg.Parameters
-			if params == nil && c.cfg.GetParameters != nil {
-				var err error
-				params, err = c.cfg.GetParameters(ctx)
-				if err != nil {
-					c.logger.Error("failed to get checkpoint parameters",
-						"err", err,
-						"version", version,
-					)
-					continue
-				}
-			}
-			if params == nil {
-				c.logger.Error("no checkpoint parameters")
+			params, err := c.getParameters(ctx)
+			if err != nil {
+				c.logger.Error("failed to get checkpoint parameters",
+					"err", err,
+					"version", version,
+				)
 				continue
 			}
 
@@ -252,7 +400,7 @@ func (c *checkpointer) worker(ctx context.Context) {
 				continue
 			}
 
-			if err := c.maybeCheckpoint(ctx, version, params); err != nil {
+			if err = c.maybeCheckpoint(ctx, version, params); err != nil {
 				c.logger.Error("failed to checkpoint",
 					"version", version,
 					"err", err,
@@ -282,6 +430,7 @@ func NewCheckpointer(
 		ndb:      ndb,
 		creator:  creator,
 		notifyCh: channels.NewRingChannel(1),
+		forceCh:  make(chan *forceCheckpointRequest),
 		statusCh: make(chan struct{}),
 		logger:   logging.GetLogger("storage/mkvs/checkpoint/"+cfg.Name).With("namespace", cfg.Namespace),
 	}
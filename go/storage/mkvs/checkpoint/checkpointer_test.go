@@ -96,6 +96,177 @@ func testCheckpointer(t *testing.T, earliestVersion uint64) {
 	}
 }
 
+func testForceCheckpoint(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "mkvs.checkpointer")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	// Create a checkpointer with periodic checkpoints disabled.
+	ctx := context.Background()
+	cp, err := NewCheckpointer(ctx, ndb, fc, CheckpointerConfig{
+		Name:            "test",
+		Namespace:       testNs,
+		CheckInterval:   testCheckInterval,
+		RootsPerVersion: 1,
+		Parameters: &CreationParameters{
+			Interval:  0,
+			NumKept:   testNumKept,
+			ChunkSize: 16 * 1024,
+		},
+	})
+	require.NoError(err, "NewCheckpointer")
+
+	// Finalize a few rounds.
+	var root node.Root
+	root.Empty()
+	root.Namespace = testNs
+
+	numRounds := uint64(testNumKept + 3)
+	for round := uint64(0); round < numRounds; round++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("round %d", round)), []byte(fmt.Sprintf("value %d", round)))
+		require.NoError(err, "Insert")
+
+		_, rootHash, err := tree.Commit(ctx, testNs, round)
+		require.NoError(err, "Commit")
+
+		root.Version = round
+		root.Hash = rootHash
+
+		err = ndb.Finalize(ctx, root.Version, []hash.Hash{root.Hash})
+		require.NoError(err, "Finalize")
+	}
+
+	err = cp.ForceCheckpoint(ctx, 1)
+	require.NoError(err, "ForceCheckpoint")
+
+	cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
+		Version:   checkpointVersion,
+		Namespace: testNs,
+	})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 1, "forced checkpoint should be created")
+	require.EqualValues(1, cps[0].Root.Version, "forced checkpoint should be for the requested version")
+
+	err = cp.ForceCheckpoint(ctx, 1)
+	require.Equal(ErrCheckpointAlreadyExists, err, "ForceCheckpoint should fail for existing checkpoints")
+
+	err = cp.ForceCheckpoint(ctx, 100)
+	require.Error(err, "ForceCheckpoint should fail for unavailable versions")
+
+	// Forced checkpoints should be garbage collected like periodic ones.
+	for version := uint64(2); version < numRounds; version++ {
+		err = cp.ForceCheckpoint(ctx, version)
+		require.NoError(err, "ForceCheckpoint")
+	}
+	cps, err = fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
+		Version:   checkpointVersion,
+		Namespace: testNs,
+	})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, testNumKept+1, "old checkpoints should be garbage collected")
+	for _, meta := range cps {
+		require.True(meta.Root.Version >= numRounds-testNumKept-1, "only the latest checkpoints should be kept")
+	}
+}
+
+func testForceCheckpointSchedule(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "mkvs.checkpointer")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	const (
+		initialVersion = 2
+		interval       = 3
+	)
+	ctx := context.Background()
+	cp, err := NewCheckpointer(ctx, ndb, fc, CheckpointerConfig{
+		Name:            "test",
+		Namespace:       testNs,
+		CheckInterval:   testCheckInterval,
+		RootsPerVersion: 1,
+		Parameters: &CreationParameters{
+			Interval:       interval,
+			InitialVersion: initialVersion,
+			NumKept:        10,
+			ChunkSize:      16 * 1024,
+		},
+	})
+	require.NoError(err, "NewCheckpointer")
+
+	var root node.Root
+	root.Empty()
+	root.Version = initialVersion
+	root.Namespace = testNs
+
+	for round := uint64(initialVersion); round < initialVersion+13; round++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("round %d", round)), []byte(fmt.Sprintf("value %d", round)))
+		require.NoError(err, "Insert")
+
+		_, rootHash, err := tree.Commit(ctx, testNs, round)
+		require.NoError(err, "Commit")
+
+		root.Version = round
+		root.Hash = rootHash
+
+		err = ndb.Finalize(ctx, root.Version, []hash.Hash{root.Hash})
+		require.NoError(err, "Finalize")
+
+		// Force an off-schedule checkpoint.
+		if round == initialVersion+5 {
+			err = cp.ForceCheckpoint(ctx, initialVersion+4)
+			require.NoError(err, "ForceCheckpoint")
+		}
+
+		cp.NotifyNewVersion(round)
+		select {
+		case <-cp.(*checkpointer).statusCh:
+		case <-time.After(2 * testCheckInterval):
+			t.Fatalf("failed to wait for checkpointer to checkpoint")
+		}
+	}
+
+	cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
+		Version:   checkpointVersion,
+		Namespace: testNs,
+	})
+	require.NoError(err, "GetCheckpoints")
+	var versions []uint64
+	for _, meta := range cps {
+		versions = append(versions, meta.Root.Version)
+	}
+	require.ElementsMatch(
+		[]uint64{initialVersion + 3, initialVersion + 4, initialVersion + 6, initialVersion + 9},
+		versions,
+		"forced checkpoints should not shift periodic checkpoints",
+	)
+}
+
 func TestCheckpointer(t *testing.T) {
 	t.Run("Basic", func(t *testing.T) {
 		testCheckpointer(t, 0)
@@ -103,4 +274,6 @@ func TestCheckpointer(t *testing.T) {
 	t.Run("NonZeroEarliestVersion", func(t *testing.T) {
 		testCheckpointer(t, 1000)
 	})
+	t.Run("ForceCheckpoint", testForceCheckpoint)
+	t.Run("ForceCheckpointSchedule", testForceCheckpointSchedule)
 }
@@ -77,6 +77,10 @@ func createChunk(
 }
 
 func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io.Reader) error {
+	if !IsVersionSupported(chunk.Version) {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, chunk.Version)
+	}
+
 	hb := hash.NewBuilder()
 	tr := io.TeeReader(r, hb)
 	sr := snappy.NewReader(tr)
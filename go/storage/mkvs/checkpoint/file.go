@@ -20,7 +20,6 @@ import (
 const (
 	chunksDir              = "chunks"
 	checkpointMetadataFile = "meta"
-	checkpointVersion      = 1
 )
 
 type fileCreator struct {
@@ -28,16 +27,26 @@ type fileCreator struct {
 	ndb     db.NodeDB
 }
 
+// checkpointDir returns the directory holding the checkpoint of the given format version for the
+// given root.
+//
+// Checkpoints using the initial format version are stored under a directory named after the root
+// hash while other format versions have the version appended so that multiple formats of the
+// same root can coexist.
+func (fc *fileCreator) checkpointDir(version uint16, root node.Root) string {
+	dir := root.Hash.String()
+	if version != 1 {
+		dir = dir + ".v" + strconv.FormatUint(uint64(version), 10)
+	}
+	return filepath.Join(fc.dataDir, strconv.FormatUint(root.Version, 10), dir)
+}
+
 func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) (meta *Metadata, err error) {
 	tree := mkvs.NewWithRoot(nil, fc.ndb, root)
 	defer tree.Close()
 
 	// Create checkpoint directory.
-	checkpointDir := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(root.Version, 10),
-		root.Hash.String(),
-	)
+	checkpointDir := fc.checkpointDir(checkpointVersion, root)
 	if err = common.Mkdir(checkpointDir); err != nil {
 		return nil, fmt.Errorf("checkpoint: failed to create checkpoint directory: %w", err)
 	}
@@ -105,8 +114,8 @@ func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chu
 }
 
 func (fc *fileCreator) GetCheckpoints(ctx context.Context, request *GetCheckpointsRequest) ([]*Metadata, error) {
-	// Currently we only support a single version so we report no checkpoints for other versions.
-	if request.Version != checkpointVersion {
+	// Report no checkpoints for unsupported versions.
+	if !IsVersionSupported(request.Version) {
 		return []*Metadata{}, nil
 	}
 
@@ -132,6 +141,10 @@ func (fc *fileCreator) GetCheckpoints(ctx context.Context, request *GetCheckpoin
 		if err = cbor.Unmarshal(data, &cp); err != nil {
 			return nil, fmt.Errorf("checkpoint: corrupted checkpoint metadata at %s: %w", m, err)
 		}
+		// Checkpoints of all format versions share the same root version directory.
+		if cp.Version != request.Version {
+			continue
+		}
 
 		cps = append(cps, &cp)
 	}
@@ -139,17 +152,11 @@ func (fc *fileCreator) GetCheckpoints(ctx context.Context, request *GetCheckpoin
 }
 
 func (fc *fileCreator) GetCheckpoint(ctx context.Context, version uint16, root node.Root) (*Metadata, error) {
-	// Currently we only support a single version.
-	if version != checkpointVersion {
+	if !IsVersionSupported(version) {
 		return nil, ErrCheckpointNotFound
 	}
 
-	checkpointFilename := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(root.Version, 10),
-		root.Hash.String(),
-		checkpointMetadataFile,
-	)
+	checkpointFilename := filepath.Join(fc.checkpointDir(version, root), checkpointMetadataFile)
 	data, err := ioutil.ReadFile(checkpointFilename)
 	if err != nil {
 		return nil, ErrCheckpointNotFound
@@ -163,13 +170,12 @@ func (fc *fileCreator) GetCheckpoint(ctx context.Context, version uint16, root n
 }
 
 func (fc *fileCreator) DeleteCheckpoint(ctx context.Context, version uint16, root node.Root) error {
-	// Currently we only support a single version.
-	if version != checkpointVersion {
+	if !IsVersionSupported(version) {
 		return ErrCheckpointNotFound
 	}
 
 	versionDir := filepath.Join(fc.dataDir, strconv.FormatUint(root.Version, 10))
-	checkpointDir := filepath.Join(versionDir, root.Hash.String())
+	checkpointDir := fc.checkpointDir(version, root)
 	checkpointFilename := filepath.Join(checkpointDir, checkpointMetadataFile)
 	if err := os.Remove(checkpointFilename); err != nil {
 		return ErrCheckpointNotFound
@@ -202,15 +208,12 @@ func (fc *fileCreator) DeleteCheckpoint(ctx context.Context, version uint16, roo
 }
 
 func (fc *fileCreator) GetCheckpointChunk(ctx context.Context, chunk *ChunkMetadata, w io.Writer) error {
-	// Currently we only support a single version.
-	if chunk.Version != checkpointVersion {
+	if !IsVersionSupported(chunk.Version) {
 		return ErrChunkNotFound
 	}
 
 	chunkFilename := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(chunk.Root.Version, 10),
-		chunk.Root.Hash.String(),
+		fc.checkpointDir(chunk.Version, chunk.Root),
 		chunksDir,
 		strconv.FormatUint(chunk.Index, 10),
 	)
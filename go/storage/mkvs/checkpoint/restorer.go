@@ -3,6 +3,7 @@ package checkpoint
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"sync"
 
@@ -30,6 +31,9 @@ func (rs *restorer) StartRestore(ctx context.Context, checkpoint *Metadata) erro
 	if rs.currentCheckpoint != nil {
 		return ErrRestoreAlreadyInProgress
 	}
+	if !IsVersionSupported(checkpoint.Version) {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, checkpoint.Version)
+	}
 
 	if err := rs.ndb.StartMultipartInsert(checkpoint.Root.Version); err != nil {
 		return err
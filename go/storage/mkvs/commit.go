@@ -21,8 +21,26 @@ func NoPersist() CommitOption {
 	}
 }
 
+// CommitStats are statistics about the nodes created by a Commit.
+type CommitStats struct {
+	// Nodes is the number of new nodes created by the commit.
+	Nodes uint64
+	// Bytes is the total size of all new nodes in their serialized form.
+	Bytes uint64
+}
+
+// WithCommitStats returns a commit option that makes the Commit collect statistics about the
+// new nodes into the given stats. Combined with NoPersist, this makes it possible to estimate
+// the cost of a commit without touching the database.
+func WithCommitStats(stats *CommitStats) CommitOption {
+	return func(o *commitOptions) {
+		o.stats = stats
+	}
+}
+
 type commitOptions struct {
 	noPersist bool
+	stats     *CommitStats
 }
 
 // Implements Tree.
@@ -82,9 +100,13 @@ func (t *tree) commitWithHooks(
 	}
 	defer batch.Reset()
 
+	if opts.stats != nil {
+		*opts.stats = CommitStats{}
+	}
+
 	subtree := batch.MaybeStartSubtree(nil, 0, t.cache.pendingRoot)
 
-	rootHash, err := doCommit(ctx, t.cache, batch, subtree, 0, t.cache.pendingRoot, &version)
+	rootHash, err := doCommit(ctx, t.cache, batch, subtree, 0, t.cache.pendingRoot, &version, opts.stats)
 	if err != nil {
 		return nil, hash.Hash{}, err
 	}
@@ -150,6 +172,9 @@ func (t *tree) commitWithHooks(
 // doCommit commits all dirty nodes and values into the underlying node
 // database. This operation may cause committed nodes and values to be
 // evicted from the in-memory cache.
+//
+// In case stats is not nil, statistics about the committed nodes are
+// accumulated into it.
 func doCommit(
 	ctx context.Context,
 	cache *cache,
@@ -158,6 +183,7 @@ func doCommit(
 	depth node.Depth,
 	ptr *node.Pointer,
 	version *uint64,
+	stats *CommitStats,
 ) (h hash.Hash, err error) {
 	if ptr == nil {
 		h.Empty()
@@ -188,13 +214,13 @@ func doCommit(
 		}
 
 		// Commit internal leaf (considered to be on the same depth as the internal node).
-		if _, err = doCommit(ctx, cache, batch, subtree, depth, n.LeafNode, version); err != nil {
+		if _, err = doCommit(ctx, cache, batch, subtree, depth, n.LeafNode, version, stats); err != nil {
 			return
 		}
 
 		for _, subNode := range []*node.Pointer{n.Left, n.Right} {
 			newSubtree := batch.MaybeStartSubtree(subtree, depth+1, subNode)
-			if _, err = doCommit(ctx, cache, batch, newSubtree, depth+1, subNode, version); err != nil {
+			if _, err = doCommit(ctx, cache, batch, newSubtree, depth+1, subNode, version, stats); err != nil {
 				return
 			}
 			if newSubtree != subtree {
@@ -213,6 +239,9 @@ func doCommit(
 		if err = subtree.PutNode(depth, ptr); err != nil {
 			return
 		}
+		if err = stats.addNode(n); err != nil {
+			return
+		}
 
 		batch.OnCommit(func() {
 			n.Clean = true
@@ -233,6 +262,9 @@ func doCommit(
 		if err = subtree.PutNode(depth, ptr); err != nil {
 			return
 		}
+		if err = stats.addNode(n); err != nil {
+			return
+		}
 
 		batch.OnCommit(func() {
 			n.Clean = true
@@ -248,3 +280,17 @@ func doCommit(
 	h = ptr.Hash
 	return
 }
+
+func (s *CommitStats) addNode(n node.Node) error {
+	if s == nil {
+		return nil
+	}
+
+	data, err := n.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	s.Nodes++
+	s.Bytes += uint64(len(data))
+	return nil
+}
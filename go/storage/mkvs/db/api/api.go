@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -51,6 +52,12 @@ var (
 	// ErrInvalidMultipartVersion indicates that a Finalize, NewBatch or Commit was called with a version
 	// that doesn't match the current multipart restore as set with StartMultipartRestore.
 	ErrInvalidMultipartVersion = errors.New(ModuleName, 14, "mkvs: operation called with different version than current multipart version")
+	// ErrRootPinned indicates that a version cannot be pruned as it contains a pinned root.
+	ErrRootPinned = errors.New(ModuleName, 15, "mkvs: version contains a pinned root")
+	// ErrNoAppliedRoot indicates that no applied root has been recorded yet.
+	ErrNoAppliedRoot = errors.New(ModuleName, 16, "mkvs: no applied root recorded")
+	// ErrCompactionInProgress indicates that a compaction is already in progress.
+	ErrCompactionInProgress = errors.New(ModuleName, 17, "mkvs: compaction already in progress")
 )
 
 // Config is the node database backend configuration.
@@ -75,6 +82,12 @@ type Config struct { // nolint: maligned
 
 	// DiscardWriteLogs will cause all write logs to be discarded.
 	DiscardWriteLogs bool
+
+	// DetectConflicts enables the underlying database's transaction conflict detection.
+	DetectConflicts bool
+
+	// LogSamplingWindow is the window for collapsing identical database log messages (0 = disabled).
+	LogSamplingWindow time.Duration
 }
 
 // NodeDB is the persistence layer used for persisting the in-memory tree.
@@ -117,6 +130,23 @@ type NodeDB interface {
 	// HasRoot checks whether the given root exists.
 	HasRoot(root node.Root) bool
 
+	// GetRootsByPrefix returns up to limit distinct root hashes starting with the given byte
+	// prefix, ordered by the earliest version they appear in.
+	GetRootsByPrefix(ctx context.Context, prefix []byte, limit int) ([]hash.Hash, error)
+
+	// GetRootVersion returns the earliest version under which the root with the given hash is
+	// stored. In case the root is not stored under any version, ErrRootNotFound is returned.
+	GetRootVersion(ctx context.Context, rootHash hash.Hash) (uint64, error)
+
+	// GetChildRoots returns the hashes of all stored roots that have been derived from the root
+	// with the given hash, ordered by hash. In case there are no such roots, an empty slice is
+	// returned.
+	GetChildRoots(ctx context.Context, rootHash hash.Hash) ([]hash.Hash, error)
+
+	// IterateFinalizedRoots invokes the callback for each finalized root in ascending version
+	// order, starting at the given version. Iteration stops early when the callback returns false.
+	IterateFinalizedRoots(ctx context.Context, startVersion uint64, cb func(root hash.Hash, version uint64) bool) error
+
 	// Finalize finalizes the specified version. The passed list of roots are the
 	// roots within the version that have been finalized. All non-finalized roots
 	// can be discarded.
@@ -125,11 +155,36 @@ type NodeDB interface {
 	// Prune removes all roots recorded under the given version.
 	//
 	// Only the earliest version can be pruned, passing any other version will result in an error.
+	// Versions containing pinned roots cannot be pruned until the roots are unpinned and since
+	// pruning is in version order, this also holds back pruning of all later versions.
 	Prune(ctx context.Context, version uint64) error
 
+	// PinRoot pins the root with the given hash, preventing any version containing it from being
+	// pruned until the root is unpinned. Pins are persisted.
+	//
+	// Pinning a root that does not exist results in ErrRootNotFound.
+	PinRoot(ctx context.Context, rootHash hash.Hash) error
+
+	// UnpinRoot removes a pin previously set via PinRoot.
+	UnpinRoot(ctx context.Context, rootHash hash.Hash) error
+
+	// SetLastAppliedRoot records the hash of the most recently applied root.
+	SetLastAppliedRoot(ctx context.Context, rootHash hash.Hash) error
+
+	// GetLastAppliedRoot returns the hash of the most recently applied root as recorded via
+	// SetLastAppliedRoot. In case no root has been recorded, ErrNoAppliedRoot is returned.
+	GetLastAppliedRoot(ctx context.Context) (hash.Hash, error)
+
+	// Compact compacts the database using the given number of workers. Only a single compaction
+	// may run at a time, concurrent calls return ErrCompactionInProgress.
+	Compact(ctx context.Context, workers int) error
+
 	// Size returns the size of the database in bytes.
 	Size() (int64, error)
 
+	// ValueLogSize returns the size of the database value log in bytes.
+	ValueLogSize() (int64, error)
+
 	// Sync syncs the database to disk. This is useful if the NoFsync option is used to explicitly
 	// perform a sync.
 	Sync() error
@@ -231,6 +286,22 @@ func (d *nopNodeDB) HasRoot(root node.Root) bool {
 	return false
 }
 
+func (d *nopNodeDB) GetRootsByPrefix(ctx context.Context, prefix []byte, limit int) ([]hash.Hash, error) {
+	return []hash.Hash{}, nil
+}
+
+func (d *nopNodeDB) GetRootVersion(ctx context.Context, rootHash hash.Hash) (uint64, error) {
+	return 0, ErrRootNotFound
+}
+
+func (d *nopNodeDB) GetChildRoots(ctx context.Context, rootHash hash.Hash) ([]hash.Hash, error) {
+	return []hash.Hash{}, nil
+}
+
+func (d *nopNodeDB) IterateFinalizedRoots(ctx context.Context, startVersion uint64, cb func(root hash.Hash, version uint64) bool) error {
+	return nil
+}
+
 func (d *nopNodeDB) StartMultipartInsert(version uint64) error {
 	return nil
 }
@@ -247,10 +318,34 @@ func (d *nopNodeDB) Prune(ctx context.Context, version uint64) error {
 	return nil
 }
 
+func (d *nopNodeDB) PinRoot(ctx context.Context, rootHash hash.Hash) error {
+	return nil
+}
+
+func (d *nopNodeDB) UnpinRoot(ctx context.Context, rootHash hash.Hash) error {
+	return nil
+}
+
+func (d *nopNodeDB) SetLastAppliedRoot(ctx context.Context, rootHash hash.Hash) error {
+	return nil
+}
+
+func (d *nopNodeDB) GetLastAppliedRoot(ctx context.Context) (hash.Hash, error) {
+	return hash.Hash{}, ErrNoAppliedRoot
+}
+
+func (d *nopNodeDB) Compact(ctx context.Context, workers int) error {
+	return nil
+}
+
 func (d *nopNodeDB) Size() (int64, error) {
 	return 0, nil
 }
 
+func (d *nopNodeDB) ValueLogSize() (int64, error) {
+	return 0, nil
+}
+
 func (d *nopNodeDB) Sync() error {
 	return nil
 }
@@ -2,10 +2,14 @@
 package badger
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/dgraph-io/badger/v2/options"
@@ -60,10 +64,24 @@ var (
 	//
 	// Value is empty.
 	multipartRestoreNodeLogKeyFmt = keyformat.New(0x05, &hash.Hash{})
+	// pinnedRootKeyFmt is the key format for pinned roots (root hash).
+	//
+	// Value is empty.
+	pinnedRootKeyFmt = keyformat.New(0x06, &hash.Hash{})
 )
 
 // New creates a new BadgerDB-backed node database.
 func New(cfg *api.Config) (api.NodeDB, error) {
+	return NewWithContext(context.Background(), cfg)
+}
+
+// NewWithContext creates a new BadgerDB-backed node database, aborting the open in case the
+// context is canceled.
+//
+// Opening a large database may take a long time as the value log needs to be replayed. In case
+// the context is canceled while the underlying database is still being opened, ctx.Err() is
+// returned immediately and the database is closed in the background once the open completes.
+func NewWithContext(ctx context.Context, cfg *api.Config) (api.NodeDB, error) {
 	db := &badgerNodeDB{
 		logger:           logging.GetLogger("mkvs/db/badger"),
 		namespace:        cfg.Namespace,
@@ -72,7 +90,7 @@ func New(cfg *api.Config) (api.NodeDB, error) {
 	}
 
 	opts := badger.DefaultOptions(cfg.DB)
-	opts = opts.WithLogger(cmnBadger.NewLogAdapter(db.logger))
+	opts = opts.WithLogger(cmnBadger.NewSampledLogAdapter(db.logger, cfg.LogSamplingWindow))
 	opts = opts.WithSyncWrites(!cfg.NoFsync)
 	// Allow value log truncation if required (this is needed to recover the
 	// value log file which can get corrupted in crashes).
@@ -80,7 +98,10 @@ func New(cfg *api.Config) (api.NodeDB, error) {
 	opts = opts.WithCompression(options.Snappy)
 	opts = opts.WithBlockCacheSize(cfg.MaxCacheSize)
 	opts = opts.WithReadOnly(cfg.ReadOnly)
-	opts = opts.WithDetectConflicts(false)
+	// MKVS applies are keyed by root and the storage backend's apply locks ensure a single writer
+	// per root, so writes are logically conflict-free and conflict detection is not needed unless
+	// explicitly requested.
+	opts = opts.WithDetectConflicts(cfg.DetectConflicts)
 
 	if cfg.MemoryOnly {
 		db.logger.Warn("using memory-only mode, data will not be persisted")
@@ -88,8 +109,8 @@ func New(cfg *api.Config) (api.NodeDB, error) {
 	}
 
 	var err error
-	if db.db, err = badger.OpenManaged(opts); err != nil {
-		return nil, fmt.Errorf("mkvs/badger: failed to open database: %w", err)
+	if db.db, err = openManaged(ctx, opts); err != nil {
+		return nil, err
 	}
 
 	// Make sure that we can discard any deleted/invalid metadata.
@@ -100,18 +121,57 @@ func New(cfg *api.Config) (api.NodeDB, error) {
 		_ = db.db.Close()
 		return nil, fmt.Errorf("mkvs/badger: failed to load metadata: %w", err)
 	}
+	if err = ctx.Err(); err != nil {
+		_ = db.db.Close()
+		return nil, err
+	}
 
 	// Cleanup any multipart restore remnants, since they can't be used anymore.
 	if err = db.cleanMultipartLocked(true); err != nil {
 		_ = db.db.Close()
 		return nil, fmt.Errorf("mkvs/badger: failed to clean leftovers from multipart restore: %w", err)
 	}
+	if err = ctx.Err(); err != nil {
+		_ = db.db.Close()
+		return nil, err
+	}
 
 	db.gc = cmnBadger.NewGCWorker(db.logger, db.db)
 
 	return db, nil
 }
 
+// openManaged opens the managed BadgerDB database, returning early in case the context is
+// canceled before the open completes.
+func openManaged(ctx context.Context, opts badger.Options) (*badger.DB, error) {
+	type openResult struct {
+		db  *badger.DB
+		err error
+	}
+
+	ch := make(chan openResult, 1)
+	go func() {
+		db, err := badger.OpenManaged(opts)
+		ch <- openResult{db, err}
+	}()
+
+	select {
+	case res := <-ch:
+		if res.err != nil {
+			return nil, fmt.Errorf("mkvs/badger: failed to open database: %w", res.err)
+		}
+		return res.db, nil
+	case <-ctx.Done():
+		// The open cannot be interrupted, so make sure to release the database once it is done.
+		go func() {
+			if res := <-ch; res.err == nil {
+				_ = res.db.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
 type badgerNodeDB struct { // nolint: maligned
 	logger *logging.Logger
 
@@ -130,6 +190,12 @@ type badgerNodeDB struct { // nolint: maligned
 	// cannot be detected.
 	metaUpdateLock sync.Mutex
 	meta           metadata
+	// pinnedRoots is the set of pinned roots. Protected by metaUpdateLock.
+	pinnedRoots map[hash.Hash]bool
+
+	// compacting is non-zero while a compaction is in progress. It must only be accessed
+	// atomically.
+	compacting uint32
 
 	closeOnce sync.Once
 }
@@ -138,6 +204,24 @@ func (d *badgerNodeDB) load() error {
 	tx := d.db.NewTransactionAt(tsMetadata, true)
 	defer tx.Discard()
 
+	// Load pinned roots.
+	d.pinnedRoots = make(map[hash.Hash]bool)
+	if err := func() error {
+		it := tx.NewIterator(badger.IteratorOptions{Prefix: pinnedRootKeyFmt.Encode()})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var h hash.Hash
+			if !pinnedRootKeyFmt.Decode(it.Item().Key(), &h) {
+				return fmt.Errorf("malformed pinned root key")
+			}
+			d.pinnedRoots[h] = true
+		}
+		return nil
+	}(); err != nil {
+		return err
+	}
+
 	// Load metadata.
 	item, err := tx.Get(metadataKeyFmt.Encode())
 	switch err {
@@ -471,6 +555,194 @@ func (d *badgerNodeDB) GetRootsForVersion(ctx context.Context, version uint64) (
 	return
 }
 
+func (d *badgerNodeDB) GetRootsByPrefix(ctx context.Context, prefix []byte, limit int) ([]hash.Hash, error) {
+	tx := d.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+
+	it := tx.NewIterator(badger.IteratorOptions{Prefix: rootsMetadataKeyFmt.Encode()})
+	defer it.Close()
+
+	roots := []hash.Hash{}
+	seen := make(map[hash.Hash]bool)
+	for it.Rewind(); it.Valid() && len(roots) < limit; it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var rootsMeta rootsMetadata
+		if err := it.Item().Value(func(val []byte) error { return cbor.Unmarshal(val, &rootsMeta) }); err != nil {
+			return nil, fmt.Errorf("mkvs/badger: error reading roots metadata: %w", err)
+		}
+
+		// Sort the roots within the version so that results are deterministic.
+		matching := make([]hash.Hash, 0, len(rootsMeta.Roots))
+		for rootHash := range rootsMeta.Roots {
+			if !seen[rootHash] && bytes.HasPrefix(rootHash[:], prefix) {
+				matching = append(matching, rootHash)
+			}
+		}
+		sort.Slice(matching, func(i, j int) bool { return bytes.Compare(matching[i][:], matching[j][:]) < 0 })
+
+		for _, rootHash := range matching {
+			if len(roots) >= limit {
+				break
+			}
+			seen[rootHash] = true
+			roots = append(roots, rootHash)
+		}
+	}
+	return roots, nil
+}
+
+func (d *badgerNodeDB) GetRootVersion(ctx context.Context, rootHash hash.Hash) (uint64, error) {
+	tx := d.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+
+	it := tx.NewIterator(badger.IteratorOptions{Prefix: rootsMetadataKeyFmt.Encode()})
+	defer it.Close()
+
+	for it.Seek(rootsMetadataKeyFmt.Encode(d.meta.getEarliestVersion())); it.Valid(); it.Next() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		var version uint64
+		if !rootsMetadataKeyFmt.Decode(it.Item().Key(), &version) {
+			return 0, fmt.Errorf("mkvs/badger: malformed roots metadata key")
+		}
+
+		var rootsMeta rootsMetadata
+		if err := it.Item().Value(func(val []byte) error { return cbor.Unmarshal(val, &rootsMeta) }); err != nil {
+			return 0, fmt.Errorf("mkvs/badger: error reading roots metadata: %w", err)
+		}
+		if _, ok := rootsMeta.Roots[rootHash]; ok {
+			return version, nil
+		}
+	}
+	return 0, api.ErrRootNotFound
+}
+
+func (d *badgerNodeDB) GetChildRoots(ctx context.Context, rootHash hash.Hash) ([]hash.Hash, error) {
+	children := []hash.Hash{}
+
+	// Roots derived from an empty root are not linked to it.
+	if rootHash.IsEmpty() {
+		return children, nil
+	}
+
+	tx := d.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+
+	it := tx.NewIterator(badger.IteratorOptions{Prefix: rootsMetadataKeyFmt.Encode()})
+	defer it.Close()
+
+	// The same root can be stored under multiple versions and each of those can have derived
+	// roots, so all versions need to be checked. Derived roots are either in the same or in the
+	// next version. Skip any derived roots that have since been discarded during finalization.
+	seen := make(map[hash.Hash]bool)
+	var (
+		pending        []hash.Hash
+		pendingVersion uint64
+	)
+	for it.Seek(rootsMetadataKeyFmt.Encode(d.meta.getEarliestVersion())); it.Valid(); it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var version uint64
+		if !rootsMetadataKeyFmt.Decode(it.Item().Key(), &version) {
+			return nil, fmt.Errorf("mkvs/badger: malformed roots metadata key")
+		}
+
+		var rootsMeta rootsMetadata
+		if err := it.Item().Value(func(val []byte) error { return cbor.Unmarshal(val, &rootsMeta) }); err != nil {
+			return nil, fmt.Errorf("mkvs/badger: error reading roots metadata: %w", err)
+		}
+
+		// Resolve derived roots from the previous version that may be in this version.
+		if version == pendingVersion+1 {
+			for _, child := range pending {
+				if _, ok := rootsMeta.Roots[child]; ok && !seen[child] {
+					seen[child] = true
+					children = append(children, child)
+				}
+			}
+		}
+		pending = nil
+
+		for _, child := range rootsMeta.Roots[rootHash] {
+			// The root itself is linked when stored under a later version without changes.
+			if seen[child] || child.Equal(&rootHash) {
+				continue
+			}
+			if _, ok := rootsMeta.Roots[child]; ok {
+				seen[child] = true
+				children = append(children, child)
+				continue
+			}
+			pending = append(pending, child)
+			pendingVersion = version
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return bytes.Compare(children[i][:], children[j][:]) < 0 })
+
+	return children, nil
+}
+
+func (d *badgerNodeDB) IterateFinalizedRoots(
+	ctx context.Context,
+	startVersion uint64,
+	cb func(root hash.Hash, version uint64) bool,
+) error {
+	lastFinalizedVersion, exists := d.meta.getLastFinalizedVersion()
+	if !exists {
+		return nil
+	}
+	if earliestVersion := d.meta.getEarliestVersion(); startVersion < earliestVersion {
+		startVersion = earliestVersion
+	}
+
+	tx := d.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+
+	it := tx.NewIterator(badger.IteratorOptions{Prefix: rootsMetadataKeyFmt.Encode()})
+	defer it.Close()
+
+	for it.Seek(rootsMetadataKeyFmt.Encode(startVersion)); it.Valid(); it.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var version uint64
+		if !rootsMetadataKeyFmt.Decode(it.Item().Key(), &version) {
+			return fmt.Errorf("mkvs/badger: malformed roots metadata key")
+		}
+		if version > lastFinalizedVersion {
+			break
+		}
+
+		var rootsMeta rootsMetadata
+		if err := it.Item().Value(func(val []byte) error { return cbor.Unmarshal(val, &rootsMeta) }); err != nil {
+			return fmt.Errorf("mkvs/badger: error reading roots metadata: %w", err)
+		}
+
+		// Non-finalized roots are removed from the metadata during finalization. Sort the roots
+		// within the version so that results are deterministic.
+		roots := make([]hash.Hash, 0, len(rootsMeta.Roots))
+		for rootHash := range rootsMeta.Roots {
+			roots = append(roots, rootHash)
+		}
+		sort.Slice(roots, func(i, j int) bool { return bytes.Compare(roots[i][:], roots[j][:]) < 0 })
+
+		for _, rootHash := range roots {
+			if !cb(rootHash, version) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
 func (d *badgerNodeDB) HasRoot(root node.Root) bool {
 	if err := d.sanityCheckNamespace(root.Namespace); err != nil {
 		return false
@@ -703,6 +975,13 @@ func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 		return err
 	}
 
+	// Make sure that the version does not contain any pinned roots.
+	for rootHash := range rootsMeta.Roots {
+		if d.pinnedRoots[rootHash] {
+			return api.ErrRootPinned
+		}
+	}
+
 	maybeLoneRoots := make(map[hash.Hash]bool)
 	for rootHash, derivedRoots := range rootsMeta.Roots {
 		if len(derivedRoots) == 0 {
@@ -780,6 +1059,129 @@ func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 	return nil
 }
 
+func (d *badgerNodeDB) PinRoot(ctx context.Context, rootHash hash.Hash) error {
+	return d.setRootPinned(ctx, rootHash, true)
+}
+
+func (d *badgerNodeDB) UnpinRoot(ctx context.Context, rootHash hash.Hash) error {
+	return d.setRootPinned(ctx, rootHash, false)
+}
+
+func (d *badgerNodeDB) setRootPinned(ctx context.Context, rootHash hash.Hash, pinned bool) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	if d.pinnedRoots[rootHash] == pinned {
+		return nil
+	}
+	// Only allow pinning roots that actually exist. This must be checked while holding the
+	// metadata update lock so that the root cannot be pruned before the pin is persisted.
+	if pinned {
+		if _, err := d.GetRootVersion(ctx, rootHash); err != nil {
+			return err
+		}
+	}
+
+	tx := d.db.NewTransactionAt(tsMetadata, true)
+	defer tx.Discard()
+
+	var err error
+	switch pinned {
+	case true:
+		err = tx.Set(pinnedRootKeyFmt.Encode(&rootHash), []byte{})
+	case false:
+		err = tx.Delete(pinnedRootKeyFmt.Encode(&rootHash))
+	}
+	if err != nil {
+		return fmt.Errorf("mkvs/badger: failed to update pinned root: %w", err)
+	}
+	if err = tx.CommitAt(tsMetadata, nil); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to commit pinned root: %w", err)
+	}
+
+	switch pinned {
+	case true:
+		d.pinnedRoots[rootHash] = true
+	case false:
+		delete(d.pinnedRoots, rootHash)
+	}
+	return nil
+}
+
+func (d *badgerNodeDB) SetLastAppliedRoot(ctx context.Context, rootHash hash.Hash) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	tx := d.db.NewTransactionAt(tsMetadata, true)
+	defer tx.Discard()
+	if err := d.meta.setLastAppliedRoot(tx, rootHash); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to set last applied root: %w", err)
+	}
+	if err := tx.CommitAt(tsMetadata, nil); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to commit metadata: %w", err)
+	}
+	return nil
+}
+
+func (d *badgerNodeDB) GetLastAppliedRoot(ctx context.Context) (hash.Hash, error) {
+	rootHash, ok := d.meta.getLastAppliedRoot()
+	if !ok {
+		return hash.Hash{}, api.ErrNoAppliedRoot
+	}
+	return rootHash, nil
+}
+
+func (d *badgerNodeDB) Compact(ctx context.Context, workers int) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+	if workers < 1 {
+		return fmt.Errorf("mkvs/badger: invalid number of compaction workers: %d", workers)
+	}
+	if !atomic.CompareAndSwapUint32(&d.compacting, 0, 1) {
+		return api.ErrCompactionInProgress
+	}
+	defer atomic.StoreUint32(&d.compacting, 0)
+
+	// Flatten cannot be interrupted, so only check the context before starting.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	sizeBefore, _ := d.Size()
+	start := time.Now()
+
+	d.logger.Info("starting compaction",
+		"workers", workers,
+		"size", sizeBefore,
+	)
+
+	if err := d.db.Flatten(workers); err != nil {
+		d.logger.Error("compaction failed",
+			"err", err,
+			"duration", time.Since(start),
+		)
+		return fmt.Errorf("mkvs/badger: failed to compact: %w", err)
+	}
+
+	sizeAfter, _ := d.Size()
+	d.logger.Info("compaction completed",
+		"duration", time.Since(start),
+		"size", sizeAfter,
+		"reclaimed_bytes", sizeBefore-sizeAfter,
+	)
+
+	return nil
+}
+
 func (d *badgerNodeDB) StartMultipartInsert(version uint64) error {
 	d.metaUpdateLock.Lock()
 	defer d.metaUpdateLock.Unlock()
@@ -864,6 +1266,11 @@ func (d *badgerNodeDB) Size() (int64, error) {
 	return lsm + vlog, nil
 }
 
+func (d *badgerNodeDB) ValueLogSize() (int64, error) {
+	_, vlog := d.db.Size()
+	return vlog, nil
+}
+
 func (d *badgerNodeDB) Sync() error {
 	return d.db.Sync()
 }
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
@@ -325,3 +326,161 @@ func TestReadOnlyBatch(t *testing.T) {
 	_, err = badgerdb.NewBatch(node.Root{}, 13, false)
 	require.Error(err, "NewBatch()")
 }
+
+func TestPinnedRoots(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// Pins must survive a restart, so we need persistence.
+	dir, err := ioutil.TempDir("", "oasis-storage-database-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(dir)
+
+	cfg := *dbCfg
+	cfg.MemoryOnly = false
+	cfg.DB = dir
+
+	ndb, err := New(&cfg)
+	require.NoError(err, "New()")
+
+	tree := mkvs.New(nil, ndb)
+	defer tree.Close()
+	err = tree.Insert(ctx, []byte("key"), testValues[0])
+	require.NoError(err, "Insert()")
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(err, "Commit()")
+	err = ndb.Finalize(ctx, 0, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize()")
+
+	err = ndb.PinRoot(ctx, hash.NewFromBytes([]byte("unknown root")))
+	require.Equal(api.ErrRootNotFound, err, "PinRoot() should fail for an unknown root")
+
+	err = ndb.PinRoot(ctx, rootHash)
+	require.NoError(err, "PinRoot()")
+	err = ndb.Prune(ctx, 0)
+	require.Equal(api.ErrRootPinned, err, "Prune() should fail for a pinned root")
+	ndb.Close()
+
+	// Reopen the database and make sure the pin is still there.
+	ndb, err = New(&cfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+
+	err = ndb.Prune(ctx, 0)
+	require.Equal(api.ErrRootPinned, err, "Prune() should fail for a pinned root after restart")
+
+	err = ndb.UnpinRoot(ctx, rootHash)
+	require.NoError(err, "UnpinRoot()")
+	err = ndb.Prune(ctx, 0)
+	require.NoError(err, "Prune()")
+}
+
+func TestNewWithContextCanceled(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-storage-database-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(dir)
+
+	cfg := *dbCfg
+	cfg.MemoryOnly = false
+	cfg.DB = dir
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = NewWithContext(ctx, &cfg)
+	require.Equal(context.Canceled, err, "NewWithContext() should fail with a canceled context")
+
+	// The database must be released so that it can be opened again.
+	require.Eventually(func() bool {
+		ndb, err := New(&cfg)
+		if err != nil {
+			return false
+		}
+		ndb.Close()
+		return true
+	}, 10*time.Second, 100*time.Millisecond, "New() should succeed after a canceled open")
+}
+
+func TestIterateFinalizedRoots(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ndb, err := New(dbCfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+
+	var roots []hash.Hash
+	for version := uint64(0); version < 4; version++ {
+		tree := mkvs.New(nil, ndb)
+		err = tree.Insert(ctx, []byte("key"), testValues[int(version)%len(testValues)])
+		require.NoError(err, "Insert()")
+		var rootHash hash.Hash
+		_, rootHash, err = tree.Commit(ctx, testNs, version)
+		require.NoError(err, "Commit()")
+		tree.Close()
+		roots = append(roots, rootHash)
+
+		// Leave the last version unfinalized.
+		if version < 3 {
+			err = ndb.Finalize(ctx, version, []hash.Hash{rootHash})
+			require.NoError(err, "Finalize()")
+		}
+	}
+
+	var (
+		iterRoots    []hash.Hash
+		iterVersions []uint64
+	)
+	err = ndb.IterateFinalizedRoots(ctx, 1, func(root hash.Hash, version uint64) bool {
+		iterRoots = append(iterRoots, root)
+		iterVersions = append(iterVersions, version)
+		return true
+	})
+	require.NoError(err, "IterateFinalizedRoots()")
+	require.Equal(roots[1:3], iterRoots, "IterateFinalizedRoots() should return finalized roots")
+	require.Equal([]uint64{1, 2}, iterVersions, "IterateFinalizedRoots() should iterate in version order")
+
+	var count int
+	err = ndb.IterateFinalizedRoots(ctx, 0, func(root hash.Hash, version uint64) bool {
+		count++
+		return false
+	})
+	require.NoError(err, "IterateFinalizedRoots()")
+	require.Equal(1, count, "IterateFinalizedRoots() should stop when the callback returns false")
+}
+
+func TestCompact(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ndb, err := New(dbCfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+
+	tree := mkvs.New(nil, ndb)
+	defer tree.Close()
+	err = tree.Insert(ctx, []byte("key"), testValues[0])
+	require.NoError(err, "Insert()")
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(err, "Commit()")
+	err = ndb.Finalize(ctx, 0, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize()")
+
+	err = ndb.Compact(ctx, 0)
+	require.Error(err, "Compact() should fail with an invalid number of workers")
+
+	// Concurrent compactions should be rejected.
+	bdb := ndb.(*badgerNodeDB)
+	bdb.compacting = 1
+	err = ndb.Compact(ctx, 1)
+	require.Equal(api.ErrCompactionInProgress, err, "Compact() should fail while a compaction is in progress")
+	bdb.compacting = 0
+
+	err = ndb.Compact(ctx, 1)
+	require.NoError(err, "Compact()")
+
+	root := node.Root{Namespace: testNs, Version: 0, Hash: rootHash}
+	require.True(ndb.HasRoot(root), "HasRoot() should return true after compaction")
+}
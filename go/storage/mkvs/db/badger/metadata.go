@@ -24,6 +24,8 @@ type serializedMetadata struct {
 	LastFinalizedVersion *uint64 `json:"last_finalized_version"`
 	// MultipartVersion is the version for the in-progress multipart restore, or 0 if none was in progress.
 	MultipartVersion uint64 `json:"multipart_version"`
+	// LastAppliedRoot is the hash of the most recently applied root, if any.
+	LastAppliedRoot *hash.Hash `json:"last_applied_root,omitempty"`
 }
 
 // metadata is the database metadata.
@@ -94,6 +96,24 @@ func (m *metadata) setMultipartVersion(tx *badger.Txn, version uint64) error {
 	return m.save(tx)
 }
 
+func (m *metadata) getLastAppliedRoot() (hash.Hash, bool) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if m.value.LastAppliedRoot == nil {
+		return hash.Hash{}, false
+	}
+	return *m.value.LastAppliedRoot, true
+}
+
+func (m *metadata) setLastAppliedRoot(tx *badger.Txn, rootHash hash.Hash) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.value.LastAppliedRoot = &rootHash
+	return m.save(tx)
+}
+
 func (m *metadata) save(tx *badger.Txn) error {
 	return tx.Set(metadataKeyFmt.Encode(), cbor.Marshal(m.value))
 }
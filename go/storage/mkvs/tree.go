@@ -126,10 +126,12 @@ func (t *tree) ApplyWriteLog(ctx context.Context, wl writelog.Iterator) error {
 			return err
 		}
 
-		// Apply operation.
-		if entry.Value == nil {
+		// Apply operation. Deleted keys are removed from the tree, so they are neither returned
+		// by lookups nor by iterators.
+		switch entry.Type() {
+		case writelog.LogDelete:
 			err = t.Remove(ctx, entry.Key)
-		} else {
+		default:
 			err = t.Insert(ctx, entry.Key, entry.Value)
 		}
 		if err != nil {
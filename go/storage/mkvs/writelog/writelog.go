@@ -26,6 +26,9 @@ func (wl WriteLog) Equal(cmp WriteLog) bool {
 }
 
 // LogEntry is a write log entry.
+//
+// An entry with a nil value is a delete of the given key. Note that this is different from an
+// entry with an empty, but non-nil, value which inserts an empty value under the given key.
 type LogEntry struct {
 	_ struct{} `cbor:",toarray"` // nolint
 
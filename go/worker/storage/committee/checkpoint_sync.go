@@ -222,7 +222,7 @@ func (n *Node) handleCheckpoint(check *checkpoint.Metadata, nodesClient grpc.Nod
 
 	for i, c := range check.Chunks {
 		heap.Push(chunks, &checkpoint.ChunkMetadata{
-			Version: 1,
+			Version: check.Version,
 			Index:   uint64(i),
 			Digest:  c,
 			Root:    check.Root,
@@ -279,20 +279,26 @@ func (n *Node) handleCheckpoint(check *checkpoint.Metadata, nodesClient grpc.Nod
 func (n *Node) getCheckpointList(nodesClient grpc.NodesClient) ([]*checkpoint.Metadata, error) {
 	// Get checkpoint list from all current committee members.
 	listCh := make(chan []*checkpoint.Metadata)
-	req := &checkpoint.GetCheckpointsRequest{
-		Version:   1,
-		Namespace: n.commonNode.Runtime.ID(),
-	}
 	getter := func(ctx context.Context, conn *grpc.ConnWithNodeMeta) error {
 		api := storageApi.NewStorageClient(conn.ClientConn)
-		meta, err := api.GetCheckpoints(ctx, req)
-		if err != nil {
-			n.logger.Error("error calling GetCheckpoints",
-				"err", err,
-				"node", conn.Node.ID,
-				"this_node", n.commonNode.Identity.NodeSigner.Public,
-			)
-			return err
+
+		// Request checkpoints in all supported format versions.
+		var meta []*checkpoint.Metadata
+		for _, version := range checkpoint.SupportedVersions {
+			vmeta, err := api.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{
+				Version:   version,
+				Namespace: n.commonNode.Runtime.ID(),
+			})
+			if err != nil {
+				n.logger.Error("error calling GetCheckpoints",
+					"err", err,
+					"node", conn.Node.ID,
+					"this_node", n.commonNode.Identity.NodeSigner.Public,
+					"version", version,
+				)
+				return err
+			}
+			meta = append(meta, vmeta...)
 		}
 		n.logger.Debug("got checkpoint list from a node",
 			"length", len(meta),
@@ -322,10 +328,18 @@ resultLoop:
 	}
 
 	// Prepare the list: sort and deduplicate.
+	formatPreference := make(map[uint16]int)
+	for i, version := range checkpoint.SupportedVersions {
+		formatPreference[version] = i
+	}
 	sort.Slice(list, func(i, j int) bool {
 		// Descending!
 		if list[j].Root.Version == list[i].Root.Version {
-			return bytes.Compare(list[j].Root.Hash[:], list[i].Root.Hash[:]) < 0
+			if cmp := bytes.Compare(list[j].Root.Hash[:], list[i].Root.Hash[:]); cmp != 0 {
+				return cmp < 0
+			}
+			// Prefer more recent checkpoint formats for the same root.
+			return formatPreference[list[i].Version] < formatPreference[list[j].Version]
 		}
 		return list[j].Root.Version < list[i].Root.Version
 	})
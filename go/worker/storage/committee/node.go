@@ -24,6 +24,7 @@ import (
 	registryApi "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothashApi "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
 	"github.com/oasisprotocol/oasis-core/go/runtime/nodes"
 	"github.com/oasisprotocol/oasis-core/go/runtime/nodes/grpc"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
@@ -325,9 +326,10 @@ func NewNode(
 				}
 
 				return &checkpoint.CreationParameters{
-					Interval:  rt.Storage.CheckpointInterval,
-					NumKept:   rt.Storage.CheckpointNumKept,
-					ChunkSize: rt.Storage.CheckpointChunkSize,
+					Interval:       rt.Storage.CheckpointInterval,
+					InitialVersion: rt.Genesis.Round,
+					NumKept:        rt.Storage.CheckpointNumKept,
+					ChunkSize:      rt.Storage.CheckpointChunkSize,
 				}, nil
 			},
 			GetRoots: func(ctx context.Context, version uint64) ([]hash.Hash, error) {
@@ -1131,6 +1133,12 @@ func (p *pruneHandler) Prune(ctx context.Context, rounds []uint64) error {
 				"round", round,
 			)
 			continue
+		case mkvsDB.ErrRootPinned:
+			// Pruning must proceed in round order, so keep the round in history and retry later.
+			p.logger.Debug("round contains a pinned root, deferring prune",
+				"round", round,
+			)
+			return fmt.Errorf("%w: round %d contains a pinned root", history.ErrPruneDeferred, round)
 		default:
 			p.logger.Error("failed to prune block",
 				"err", err,
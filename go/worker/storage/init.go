@@ -44,6 +44,27 @@ const (
 	// CfgMaxCacheSize configures the maximum in-memory cache size.
 	CfgMaxCacheSize = "worker.storage.max_cache_size"
 
+	// CfgDetectConflicts configures whether the database performs transaction conflict detection.
+	CfgDetectConflicts = "worker.storage.detect_conflicts"
+
+	// CfgLogSamplingWindow configures the window within which identical database log messages are
+	// collapsed.
+	CfgLogSamplingWindow = "worker.storage.log_sampling_window"
+
+	// CfgMaxValueSize configures the maximum size of a value in an applied write log.
+	CfgMaxValueSize = "worker.storage.max_value_size"
+
+	// CfgVerifyOnStart enables a consistency check of recent roots on startup.
+	CfgVerifyOnStart = "worker.storage.verify_on_start"
+
+	// CfgMaxPendingApplies configures the maximum number of concurrently processed applies.
+	CfgMaxPendingApplies = "worker.storage.max_pending_applies"
+
+	// CfgOpenRetries configures the number of retries when the database is locked.
+	CfgOpenRetries = "worker.storage.open_retries"
+	// CfgOpenRetryInterval configures the initial interval between database open retries.
+	CfgOpenRetryInterval = "worker.storage.open_retry_interval"
+
 	cfgCrashEnabled       = "worker.storage.crash.enabled"
 	cfgInsecureSkipChecks = "worker.storage.debug.insecure_skip_checks"
 )
@@ -65,6 +86,13 @@ func NewLocalBackend(
 		InsecureSkipChecks: viper.GetBool(cfgInsecureSkipChecks) && cmdFlags.DebugDontBlameOasis(),
 		Namespace:          namespace,
 		MaxCacheSize:       int64(viper.GetSizeInBytes(CfgMaxCacheSize)),
+		DetectConflicts:    viper.GetBool(CfgDetectConflicts),
+		LogSamplingWindow:  viper.GetDuration(CfgLogSamplingWindow),
+		MaxValueSize:       uint64(viper.GetSizeInBytes(CfgMaxValueSize)),
+		VerifyOnStart:      viper.GetBool(CfgVerifyOnStart),
+		MaxPendingApplies:  viper.GetInt(CfgMaxPendingApplies),
+		OpenRetries:        viper.GetUint64(CfgOpenRetries),
+		OpenRetryInterval:  viper.GetDuration(CfgOpenRetryInterval),
 	}
 
 	var (
@@ -104,6 +132,13 @@ func init() {
 	Flags.Bool(cfgCrashEnabled, false, "Enable the crashing storage wrapper")
 	Flags.Int(CfgLRUSlots, 1000, "How many LRU slots to use for Apply call locks in the MKVS tree root cache")
 	Flags.String(CfgMaxCacheSize, "64mb", "Maximum in-memory cache size")
+	Flags.Bool(CfgDetectConflicts, false, "Enable database transaction conflict detection")
+	Flags.Duration(CfgLogSamplingWindow, 0, "Window within which identical database info/debug log messages are collapsed (0 = disabled)")
+	Flags.String(CfgMaxValueSize, "0", "Maximum size of a value in an applied write log (0 = unlimited)")
+	Flags.Bool(CfgVerifyOnStart, false, "Verify the consistency of recent roots on startup")
+	Flags.Int(CfgMaxPendingApplies, 0, "Maximum number of concurrently processed applies (0 = unlimited)")
+	Flags.Uint64(CfgOpenRetries, 0, "Number of retries when the database is locked by another process")
+	Flags.Duration(CfgOpenRetryInterval, 1*time.Second, "Initial interval between database open retries")
 
 	Flags.Bool(cfgInsecureSkipChecks, false, "INSECURE: Skip known root checks")
 